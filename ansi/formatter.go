@@ -0,0 +1,210 @@
+// Package ansi renders TrogonErrors with ANSI colors for CLI tools and local development.
+//
+// It lives in its own package so servers that never write to a terminal don't link it:
+//
+//	trogonerror.SetDefaultFormatter(ansi.NewFormatter(ansi.WithTTYDetection(os.Stderr)))
+package ansi
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+const (
+	reset     = "\x1b[0m"
+	bold      = "\x1b[1m"
+	dim       = "\x1b[2m"
+	underline = "\x1b[4m"
+	red       = "\x1b[31m"
+	yellow    = "\x1b[33m"
+	cyan      = "\x1b[36m"
+)
+
+// Formatter renders errors in the indented text layout, colorizing the code in red,
+// dimming metadata keys and underlining help links
+type Formatter struct {
+	enabled bool
+}
+
+// Option represents options for Formatter construction
+type Option func(*Formatter)
+
+// NewFormatter creates a colorizing formatter. Colors are enabled by default.
+func NewFormatter(options ...Option) *Formatter {
+	f := &Formatter{enabled: true}
+
+	for _, option := range options {
+		option(f)
+	}
+
+	return f
+}
+
+// WithColor explicitly enables or disables colors
+func WithColor(enabled bool) Option {
+	return func(f *Formatter) {
+		f.enabled = enabled
+	}
+}
+
+// WithTTYDetection enables colors only when file is a terminal and NO_COLOR is unset
+func WithTTYDetection(file *os.File) Option {
+	return func(f *Formatter) {
+		f.enabled = IsTerminal(file) && os.Getenv("NO_COLOR") == ""
+	}
+}
+
+// IsTerminal reports whether file is a character device such as an interactive terminal
+func IsTerminal(file *os.File) bool {
+	if file == nil {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// maxCauseDepth mirrors the nesting limit of the text layout
+const maxCauseDepth = 5
+
+func (f *Formatter) writeCauses(sb *strings.Builder, causes []*trogonerror.TrogonError, indent string, depth int) {
+	fmt.Fprintf(sb, "\n%s%s", indent, f.paint(dim, "causes:"))
+	for _, cause := range causes {
+		fmt.Fprintf(sb, "\n%s  - %s/%s (%s): %s", indent, cause.Domain(), cause.Reason(),
+			f.paint(red, cause.Code().String()), strings.TrimSpace(cause.Message()))
+		if len(cause.Causes()) == 0 {
+			continue
+		}
+		if depth >= maxCauseDepth {
+			fmt.Fprintf(sb, "\n%s    %s %d omitted", indent, f.paint(dim, "causes:"), len(cause.Causes()))
+			continue
+		}
+		f.writeCauses(sb, cause.Causes(), indent+"    ", depth+1)
+	}
+}
+
+func (f *Formatter) paint(style, text string) string {
+	if !f.enabled || text == "" {
+		return text
+	}
+	return style + text + reset
+}
+
+// Format renders the error, implementing trogonerror.Formatter
+func (f *Formatter) Format(e *trogonerror.TrogonError) string {
+	sb := &strings.Builder{}
+	sb.WriteString(f.paint(bold, strings.TrimSpace(e.Message())))
+	if e.PublicMessage() != "" {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "publicMessage:"), e.PublicMessage())
+	}
+
+	fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "visibility:"), e.Visibility().String())
+	fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "domain:"), e.Domain())
+	fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "reason:"), e.Reason())
+	fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "code:"), f.paint(red, e.Code().String()))
+
+	if e.ID() != "" {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "id:"), e.ID())
+	}
+	if e.Time() != nil {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "time:"), e.Time().Format(time.RFC3339))
+	}
+	if e.Subject() != "" {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "subject:"), e.Subject())
+	}
+	if e.SourceID() != "" {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "sourceId:"), e.SourceID())
+	}
+	if e.Owner() != "" {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "owner:"), e.Owner())
+	}
+	if e.IsTransient() || e.IsPermanent() {
+		fmt.Fprintf(sb, "\n  %s %t", f.paint(dim, "transient:"), e.IsTransient())
+	}
+	if e.Idempotency() != trogonerror.IdempotencyUnspecified {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "idempotency:"), e.Idempotency().String())
+	}
+	if tags := e.Tags(); len(tags) > 0 {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "tags:"), strings.Join(tags, ", "))
+	}
+	if operations := e.Operations(); len(operations) > 0 {
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "operations:"), strings.Join(operations, " > "))
+	}
+	if retryInfo := e.RetryInfo(); retryInfo != nil {
+		var retryStr string
+		if retryInfo.RetryOffset() != nil {
+			retryStr = "retryOffset=" + retryInfo.RetryOffset().String()
+		} else if retryInfo.RetryTime() != nil {
+			retryStr = "retryTime=" + retryInfo.RetryTime().Format(time.RFC3339)
+		}
+		fmt.Fprintf(sb, "\n  %s %s", f.paint(dim, "retryInfo:"), f.paint(yellow, retryStr))
+	}
+
+	if keys := slices.Sorted(metadataKeys(e)); len(keys) > 0 {
+		fmt.Fprintf(sb, "\n  %s", f.paint(dim, "metadata:"))
+		for _, k := range keys {
+			v, _ := e.LookupMetadata(k)
+			fmt.Fprintf(sb, "\n    - %s %s %s", f.paint(dim, k+":"), trogonerror.RedactMetadataValue(k, v.Value()), f.paint(dim, "visibility="+v.Visibility().String()))
+		}
+	}
+
+	if causes := e.Causes(); len(causes) > 0 {
+		f.writeCauses(sb, causes, "  ", 1)
+	}
+
+	if help := e.Help(); help != nil && len(help.Links()) > 0 {
+		sb.WriteString("\n")
+		for _, link := range help.Links() {
+			sb.WriteString("\n- ")
+			if link.Kind() != trogonerror.HelpLinkDocumentation {
+				fmt.Fprintf(sb, "%s ", f.paint(yellow, "["+strings.ToLower(link.Kind().String())+"]"))
+			}
+			fmt.Fprintf(sb, "%s: %s", link.Description(), f.paint(underline+cyan, link.URL()))
+		}
+	}
+
+	if wrapped := e.Unwrap(); wrapped != nil {
+		fmt.Fprintf(sb, "\n\n%s %s", f.paint(dim, "wrapped error:"), wrapped.Error())
+	}
+
+	if debugInfo := e.DebugInfo(); debugInfo != nil {
+		sb.WriteString("\n")
+		if debugInfo.Detail() != "" {
+			sb.WriteString("\n")
+			sb.WriteString(f.paint(yellow, debugInfo.Detail()))
+		}
+		for _, entry := range debugInfo.StackEntries() {
+			sb.WriteString("\n")
+			sb.WriteString(f.paint(dim, entry))
+		}
+		if debugInfo.SourceSnippet() != "" {
+			fmt.Fprintf(sb, "\n\n%s\n%s", f.paint(dim, "source:"), debugInfo.SourceSnippet())
+		}
+		if runtimeInfo := debugInfo.RuntimeInfo(); runtimeInfo != nil {
+			fmt.Fprintf(sb, "\n\n%s %s", f.paint(dim, "runtime:"), runtimeInfo.String())
+		}
+		if debugInfo.Goroutines() != "" {
+			fmt.Fprintf(sb, "\n\n%s\n%s", f.paint(dim, "goroutines:"), f.paint(dim, debugInfo.Goroutines()))
+		}
+	}
+
+	return sb.String()
+}
+
+func metadataKeys(e *trogonerror.TrogonError) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for k := range e.AllMetadata() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
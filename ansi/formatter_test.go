@@ -0,0 +1,75 @@
+package ansi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatter(t *testing.T) {
+	err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/5432109876"),
+		trogonerror.WithHelpLink("Order Management", "https://admin.shopify.com/orders/5432109876"))
+
+	t.Run("Without colors matches the text layout", func(t *testing.T) {
+		formatter := ansi.NewFormatter(ansi.WithColor(false))
+
+		assert.Equal(t, trogonerror.TextFormatter.Format(err), formatter.Format(err))
+	})
+
+	t.Run("With colors highlights code, metadata keys and help links", func(t *testing.T) {
+		formatter := ansi.NewFormatter()
+
+		expected := "\x1b[1mresource not found\x1b[0m" +
+			"\n  \x1b[2mvisibility:\x1b[0m INTERNAL" +
+			"\n  \x1b[2mdomain:\x1b[0m shopify.orders" +
+			"\n  \x1b[2mreason:\x1b[0m ORDER_FAILED" +
+			"\n  \x1b[2mcode:\x1b[0m \x1b[31mNOT_FOUND\x1b[0m" +
+			"\n  \x1b[2mmetadata:\x1b[0m" +
+			"\n    - \x1b[2morderId:\x1b[0m gid://shopify/Order/5432109876 \x1b[2mvisibility=PUBLIC\x1b[0m" +
+			"\n" +
+			"\n- Order Management: \x1b[4m\x1b[36mhttps://admin.shopify.com/orders/5432109876\x1b[0m"
+
+		assert.Equal(t, expected, formatter.Format(err))
+	})
+
+	t.Run("TTY detection disables colors for regular files", func(t *testing.T) {
+		file, createErr := os.Create(filepath.Join(t.TempDir(), "out.log"))
+		assert.NoError(t, createErr)
+		defer file.Close()
+
+		assert.False(t, ansi.IsTerminal(file))
+		assert.False(t, ansi.IsTerminal(nil))
+
+		formatter := ansi.NewFormatter(ansi.WithTTYDetection(file))
+		assert.Equal(t, trogonerror.TextFormatter.Format(err), formatter.Format(err))
+	})
+
+	t.Run("Can be installed as the default formatter", func(t *testing.T) {
+		trogonerror.SetDefaultFormatter(ansi.NewFormatter())
+		t.Cleanup(func() { trogonerror.SetDefaultFormatter(nil) })
+
+		assert.Contains(t, err.Error(), "\x1b[31mNOT_FOUND\x1b[0m")
+	})
+}
+
+func TestFormatterCauses(t *testing.T) {
+	cause := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+		trogonerror.WithCode(trogonerror.CodeUnavailable),
+		trogonerror.WithCause(trogonerror.NewError("shopify.network", "CONNECTION_RESET")))
+	err := trogonerror.NewError("shopify.users", "USER_FETCH_FAILED",
+		trogonerror.WithCause(cause))
+
+	t.Run("Without colors matches the text layout", func(t *testing.T) {
+		assert.Equal(t, trogonerror.TextFormatter.Format(err), ansi.NewFormatter(ansi.WithColor(false)).Format(err))
+	})
+
+	t.Run("With colors highlights cause codes", func(t *testing.T) {
+		assert.Contains(t, ansi.NewFormatter().Format(err), "- shopify.database/CONNECTION_FAILED (\x1b[31mUNAVAILABLE\x1b[0m): service unavailable")
+	})
+}
@@ -0,0 +1,133 @@
+package trogonerror
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// AuthInfo describes what an Unauthenticated or PermissionDenied error requires: the auth scheme,
+// and the scopes and roles the caller lacks, so API gateways can render messages like
+// "you need scope orders:write". The principal class of the authenticated caller, such as
+// "service-account", is internal: like debug info, it is only serialized for an internal audience
+// or over a trusted channel.
+type AuthInfo struct {
+	scheme         string
+	requiredScopes []string
+	requiredRoles  []string
+	principalClass string
+}
+
+func (a AuthInfo) Scheme() string           { return a.scheme }
+func (a AuthInfo) RequiredScopes() []string { return slices.Clone(a.requiredScopes) }
+func (a AuthInfo) RequiredRoles() []string  { return slices.Clone(a.requiredRoles) }
+func (a AuthInfo) PrincipalClass() string   { return a.principalClass }
+
+// AuthInfo returns what the failed request required, nil when nothing was recorded
+func (e TrogonError) AuthInfo() *AuthInfo { return e.authInfo }
+
+// WithAuthScheme sets the auth scheme the request must use, such as "Bearer"
+//
+// Example usage:
+//
+//	err := ErrInsufficientScope.NewError(
+//	    trogonerror.WithAuthScheme("Bearer"),
+//	    trogonerror.WithRequiredScopes("orders:write"))
+func WithAuthScheme(scheme string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.scheme = scheme })
+	})
+}
+
+// WithRequiredScopes adds scopes the request lacks
+func WithRequiredScopes(scopes ...string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.requiredScopes = appendUnique(info.requiredScopes, scopes) })
+	})
+}
+
+// WithRequiredRoles adds roles the caller lacks
+func WithRequiredRoles(roles ...string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.requiredRoles = appendUnique(info.requiredRoles, roles) })
+	})
+}
+
+// WithPrincipalClass records the class of the authenticated caller, such as "user" or "service-account".
+// It has internal visibility.
+func WithPrincipalClass(class string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.principalClass = class })
+	})
+}
+
+// WithChangeAuthScheme sets the auth scheme the request must use
+func WithChangeAuthScheme(scheme string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.scheme = scheme })
+	})
+}
+
+// WithChangeRequiredScopes adds scopes the request lacks
+func WithChangeRequiredScopes(scopes ...string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.requiredScopes = appendUnique(info.requiredScopes, scopes) })
+	})
+}
+
+// WithChangeRequiredRoles adds roles the caller lacks
+func WithChangeRequiredRoles(roles ...string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.requiredRoles = appendUnique(info.requiredRoles, roles) })
+	})
+}
+
+// WithChangePrincipalClass records the class of the authenticated caller
+func WithChangePrincipalClass(class string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		changeAuthInfo(e, func(info *AuthInfo) { info.principalClass = class })
+	})
+}
+
+// changeAuthInfo applies change to a copy of the auth info, which may be shared with other errors
+func changeAuthInfo(e *TrogonError, change func(*AuthInfo)) {
+	var info AuthInfo
+	if e.authInfo != nil {
+		info = *e.authInfo
+	}
+	change(&info)
+	e.authInfo = &info
+}
+
+// appendUnique returns a new slice with the values missing from existing appended in order
+func appendUnique(existing, values []string) []string {
+	result := slices.Clone(existing)
+	for _, value := range values {
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// SetWWWAuthenticate sets the WWW-Authenticate header from the error's auth scheme, if any,
+// with its required scopes and, for PermissionDenied errors, error="insufficient_scope" as in RFC 6750
+func SetWWWAuthenticate(header http.Header, err *TrogonError) {
+	if err == nil || err.authInfo == nil || err.authInfo.scheme == "" {
+		return
+	}
+
+	var params []string
+	if err.code == CodePermissionDenied && len(err.authInfo.requiredScopes) > 0 {
+		params = append(params, `error="insufficient_scope"`)
+	}
+	if len(err.authInfo.requiredScopes) > 0 {
+		params = append(params, `scope="`+strings.Join(err.authInfo.requiredScopes, " ")+`"`)
+	}
+
+	value := err.authInfo.scheme
+	if len(params) > 0 {
+		value += " " + strings.Join(params, ", ")
+	}
+	header.Set("WWW-Authenticate", value)
+}
@@ -0,0 +1,131 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthInfo(t *testing.T) {
+	t.Run("Records the auth requirements", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "INSUFFICIENT_SCOPE",
+			trogonerror.WithCode(trogonerror.CodePermissionDenied),
+			trogonerror.WithAuthScheme("Bearer"),
+			trogonerror.WithRequiredScopes("orders:write", "orders:read"),
+			trogonerror.WithRequiredScopes("orders:write"),
+			trogonerror.WithRequiredRoles("admin"),
+			trogonerror.WithPrincipalClass("service-account"))
+
+		info := err.AuthInfo()
+		assert.Equal(t, "Bearer", info.Scheme())
+		assert.Equal(t, []string{"orders:write", "orders:read"}, info.RequiredScopes())
+		assert.Equal(t, []string{"admin"}, info.RequiredRoles())
+		assert.Equal(t, "service-account", info.PrincipalClass())
+		assert.Nil(t, trogonerror.NewError("shopify.users", "NOT_FOUND").AuthInfo())
+	})
+
+	t.Run("Changes leave the original untouched", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "INSUFFICIENT_SCOPE",
+			trogonerror.WithRequiredScopes("orders:read"))
+
+		modified := original.WithChanges(
+			trogonerror.WithChangeRequiredScopes("orders:write"),
+			trogonerror.WithChangeRequiredRoles("admin"),
+			trogonerror.WithChangeAuthScheme("Bearer"),
+			trogonerror.WithChangePrincipalClass("user"))
+
+		assert.Equal(t, []string{"orders:read"}, original.AuthInfo().RequiredScopes())
+		assert.Empty(t, original.AuthInfo().Scheme())
+		assert.Equal(t, []string{"orders:read", "orders:write"}, modified.AuthInfo().RequiredScopes())
+		assert.Equal(t, "user", modified.AuthInfo().PrincipalClass())
+	})
+
+	t.Run("The principal class is internal", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "INSUFFICIENT_SCOPE",
+			trogonerror.WithCode(trogonerror.CodePermissionDenied),
+			trogonerror.WithAuthScheme("Bearer"),
+			trogonerror.WithRequiredScopes("orders:write"),
+			trogonerror.WithPrincipalClass("service-account"))
+
+		internal, marshalErr := err.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(internal), `"authInfo":{"scheme":"Bearer","requiredScopes":["orders:write"],"principalClass":"service-account"}`)
+		assert.NoError(t, trogonerror.ValidatePayload(internal))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(internal, &decoded))
+		assert.Empty(t, trogonerror.Diff(err, &decoded))
+
+		defaultPolicy, marshalErr := json.Marshal(err)
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(defaultPolicy), `"authInfo":{"scheme":"Bearer","requiredScopes":["orders:write"]}`)
+		assert.NotContains(t, string(defaultPolicy), "service-account")
+
+		trusted, marshalErr := err.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic,
+			trogonerror.SerializationPolicyWithTrustedChannel()))
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(trusted), `"principalClass":"service-account"`)
+
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic, trogonerror.SerializationPolicyWithVisibilityFiltering())
+		public, marshalErr := err.MarshalJSONFor(policy)
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(public), `"authInfo":{"scheme":"Bearer","requiredScopes":["orders:write"]}`)
+		assert.Empty(t, policy.Apply(err).AuthInfo().PrincipalClass())
+
+		masked := trogonerror.MaskForPublic(err)
+		assert.Equal(t, []string{"orders:write"}, masked.AuthInfo().RequiredScopes())
+		assert.Empty(t, masked.AuthInfo().PrincipalClass())
+		assert.Equal(t, "service-account", err.AuthInfo().PrincipalClass())
+	})
+}
+
+func TestSetWWWAuthenticate(t *testing.T) {
+	t.Run("Insufficient scope follows RFC 6750", func(t *testing.T) {
+		header := http.Header{}
+		err := trogonerror.NewError("shopify.orders", "INSUFFICIENT_SCOPE",
+			trogonerror.WithCode(trogonerror.CodePermissionDenied),
+			trogonerror.WithAuthScheme("Bearer"),
+			trogonerror.WithRequiredScopes("orders:read", "orders:write"))
+
+		trogonerror.SetWWWAuthenticate(header, err)
+
+		assert.Equal(t, `Bearer error="insufficient_scope", scope="orders:read orders:write"`, header.Get("WWW-Authenticate"))
+	})
+
+	t.Run("Unauthenticated errors challenge with the scheme", func(t *testing.T) {
+		header := http.Header{}
+		err := trogonerror.NewError("shopify.auth", "MISSING_CREDENTIALS",
+			trogonerror.WithCode(trogonerror.CodeUnauthenticated),
+			trogonerror.WithAuthScheme("Bearer"))
+
+		trogonerror.SetWWWAuthenticate(header, err)
+
+		assert.Equal(t, "Bearer", header.Get("WWW-Authenticate"))
+	})
+
+	t.Run("Skips errors without an auth scheme", func(t *testing.T) {
+		header := http.Header{}
+
+		trogonerror.SetWWWAuthenticate(header, trogonerror.NewError("shopify.orders", "INSUFFICIENT_SCOPE",
+			trogonerror.WithRequiredScopes("orders:write")))
+		trogonerror.SetWWWAuthenticate(header, nil)
+
+		assert.Empty(t, header)
+	})
+
+	t.Run("Respond renders the header", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.auth", "MISSING_CREDENTIALS",
+			trogonerror.WithCode(trogonerror.CodeUnauthenticated),
+			trogonerror.WithAuthScheme("Bearer"))
+		recorder := httptest.NewRecorder()
+
+		assert.NoError(t, trogonerror.Respond(recorder, httptest.NewRequest(http.MethodGet, "/", nil), err))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+	})
+}
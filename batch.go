@@ -0,0 +1,222 @@
+package trogonerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// BatchError reports the outcome of a bulk operation where only some items may fail,
+// mapping the key of every failed item (its index or identifier) to its error.
+// Failures keep the order of the batch keys.
+type BatchError struct {
+	keys     []string
+	known    map[string]struct{}
+	failures map[string]*TrogonError
+}
+
+// BatchItem is a failed item of a batch
+type BatchItem struct {
+	key string
+	err *TrogonError
+}
+
+func (b BatchItem) Key() string       { return b.key }
+func (b BatchItem) Err() *TrogonError { return b.err }
+
+// NewBatchError creates a batch of items identified by keys, such as resource IDs, none of them failed yet
+func NewBatchError(keys ...string) *BatchError {
+	batch := &BatchError{known: make(map[string]struct{}, len(keys)), failures: make(map[string]*TrogonError)}
+	for _, key := range keys {
+		batch.add(key)
+	}
+	return batch
+}
+
+// NewIndexedBatchError creates a batch of size items identified by their index
+func NewIndexedBatchError(size int) *BatchError {
+	batch := &BatchError{known: make(map[string]struct{}, size), failures: make(map[string]*TrogonError)}
+	for i := range size {
+		batch.add(strconv.Itoa(i))
+	}
+	return batch
+}
+
+func (b *BatchError) add(key string) {
+	if _, ok := b.known[key]; ok {
+		return
+	}
+	b.known[key] = struct{}{}
+	b.keys = append(b.keys, key)
+}
+
+// Fail records the error of the item with the given key. Unknown keys are appended to the batch,
+// a nil error marks the item as succeeded again.
+func (b *BatchError) Fail(key string, err *TrogonError) {
+	b.add(key)
+	if err == nil {
+		delete(b.failures, key)
+		return
+	}
+	b.failures[key] = err
+}
+
+// FailIndex records the error of the item at index
+func (b *BatchError) FailIndex(index int, err *TrogonError) {
+	b.Fail(strconv.Itoa(index), err)
+}
+
+// Len returns the number of items in the batch
+func (b *BatchError) Len() int { return len(b.keys) }
+
+// HasFailures reports whether any item failed
+func (b *BatchError) HasFailures() bool { return len(b.failures) > 0 }
+
+// Failed returns the failed items in batch order
+func (b *BatchError) Failed() []BatchItem {
+	items := make([]BatchItem, 0, len(b.failures))
+	for _, key := range b.keys {
+		if err, ok := b.failures[key]; ok {
+			items = append(items, BatchItem{key: key, err: err})
+		}
+	}
+	return items
+}
+
+// Succeeded returns the keys of the items that did not fail, in batch order
+func (b *BatchError) Succeeded() []string {
+	keys := make([]string, 0, len(b.keys)-len(b.failures))
+	for _, key := range b.keys {
+		if _, ok := b.failures[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ByCode groups the failed items by error code, each group in batch order
+func (b *BatchError) ByCode() map[Code][]BatchItem {
+	groups := make(map[Code][]BatchItem)
+	for _, item := range b.Failed() {
+		groups[item.err.code] = append(groups[item.err.code], item)
+	}
+	return groups
+}
+
+// Get returns the error of the item with the given key, if it failed
+func (b *BatchError) Get(key string) (*TrogonError, bool) {
+	err, ok := b.failures[key]
+	return err, ok
+}
+
+// Err returns the batch as an error when any item failed, and nil otherwise
+func (b *BatchError) Err() error {
+	if !b.HasFailures() {
+		return nil
+	}
+	return b
+}
+
+func (b *BatchError) Error() string {
+	failed := b.Failed()
+	if len(failed) == 0 {
+		return fmt.Sprintf("batch of %d items succeeded", len(b.keys))
+	}
+	return fmt.Sprintf("%d of %d items failed; first [%s]: %s", len(failed), len(b.keys), failed[0].key, failed[0].err.Error())
+}
+
+// Unwrap returns the item errors, so errors.Is and errors.As see every failure
+func (b *BatchError) Unwrap() []error {
+	failed := b.Failed()
+	errs := make([]error, len(failed))
+	for i, item := range failed {
+		errs[i] = item.err
+	}
+	return errs
+}
+
+// StatusCode returns the HTTP status of the batch response: 200 when every item succeeded,
+// the status of the shared code when every item failed with the same code, and 207 Multi-Status otherwise
+func (b *BatchError) StatusCode() int {
+	failed := b.Failed()
+	switch {
+	case len(failed) == 0:
+		return http.StatusOK
+	case len(failed) == len(b.keys) && len(b.ByCode()) == 1:
+		return failed[0].err.StatusCode()
+	default:
+		return http.StatusMultiStatus
+	}
+}
+
+type jsonBatch struct {
+	Total     int             `json:"total"`
+	Succeeded []string        `json:"succeeded"`
+	Failed    []jsonBatchItem `json:"failed"`
+}
+
+type jsonBatchItem struct {
+	Key   string          `json:"key"`
+	Error json.RawMessage `json:"error"`
+}
+
+// MarshalJSON encodes the batch as its total size, the succeeded keys and the failed items with their errors,
+// applying DefaultSerializationPolicy to every error
+func (b *BatchError) MarshalJSON() ([]byte, error) {
+	return b.MarshalJSONFor(DefaultSerializationPolicy())
+}
+
+// MarshalJSONFor encodes the batch like MarshalJSON, applying policy instead of the default one
+func (b *BatchError) MarshalJSONFor(policy *SerializationPolicy) ([]byte, error) {
+	out := jsonBatch{Total: len(b.keys), Succeeded: b.Succeeded(), Failed: []jsonBatchItem{}}
+	for _, item := range b.Failed() {
+		data, err := item.err.MarshalJSONFor(policy)
+		if err != nil {
+			return nil, err
+		}
+		out.Failed = append(out.Failed, jsonBatchItem{Key: item.key, Error: data})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a batch encoded by MarshalJSON. Items are ordered succeeded first, then failed.
+func (b *BatchError) UnmarshalJSON(data []byte) error {
+	var decoded jsonBatch
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	batch := NewBatchError(decoded.Succeeded...)
+	for _, item := range decoded.Failed {
+		var err TrogonError
+		if decodeErr := err.UnmarshalJSON(item.Error); decodeErr != nil {
+			return fmt.Errorf("batch item %q: %w", item.Key, decodeErr)
+		}
+		batch.Fail(item.Key, &err)
+	}
+	if len(batch.keys) != decoded.Total {
+		return errors.New("trogonerror: batch total does not match its items")
+	}
+
+	*b = *batch
+	return nil
+}
+
+// WriteHTTPResponse writes the batch as a JSON response with the status returned by StatusCode.
+// A nil policy uses DefaultSerializationPolicy.
+func (b *BatchError) WriteHTTPResponse(w http.ResponseWriter, policy *SerializationPolicy) error {
+	if policy == nil {
+		policy = DefaultSerializationPolicy()
+	}
+	data, err := b.MarshalJSONFor(policy)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(b.StatusCode())
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,83 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchError(t *testing.T) {
+	outOfStock := trogonerror.NewErrorTemplate("shopify.inventory", "OUT_OF_STOCK",
+		trogonerror.TemplateWithCode(trogonerror.CodeFailedPrecondition),
+		trogonerror.TemplateWithVisibility(trogonerror.VisibilityPublic))
+	notFound := trogonerror.NewErrorTemplate("shopify.products", "PRODUCT_NOT_FOUND",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound),
+		trogonerror.TemplateWithVisibility(trogonerror.VisibilityPublic))
+
+	t.Run("Partial failure", func(t *testing.T) {
+		batch := trogonerror.NewIndexedBatchError(4)
+		batch.FailIndex(3, outOfStock.NewError())
+		batch.FailIndex(1, notFound.NewError())
+		batch.FailIndex(2, outOfStock.NewError())
+
+		failed := batch.Failed()
+		assert.Len(t, failed, 3)
+		assert.Equal(t, "1", failed[0].Key())
+		assert.Equal(t, "3", failed[2].Key())
+		assert.Equal(t, []string{"0"}, batch.Succeeded())
+		assert.Len(t, batch.ByCode()[trogonerror.CodeFailedPrecondition], 2)
+		assert.Equal(t, http.StatusMultiStatus, batch.StatusCode())
+		assert.True(t, errors.Is(batch.Err(), notFound.NewError()))
+		assert.Equal(t, "3 of 4 items failed; first [1]: "+notFound.NewError().Error(), batch.Error())
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		batch := trogonerror.NewBatchError("sku-1", "sku-2")
+		assert.Equal(t, http.StatusOK, batch.StatusCode())
+		assert.NoError(t, batch.Err())
+
+		batch.Fail("sku-1", outOfStock.NewError())
+		batch.Fail("sku-2", outOfStock.NewError())
+		assert.Equal(t, http.StatusBadRequest, batch.StatusCode())
+
+		batch.Fail("sku-2", nil)
+		assert.Equal(t, []string{"sku-2"}, batch.Succeeded())
+	})
+
+	t.Run("JSON round-trip", func(t *testing.T) {
+		batch := trogonerror.NewBatchError("sku-1", "sku-2")
+		batch.Fail("sku-2", outOfStock.NewError(trogonerror.WithSubject("/items/1")))
+
+		data, err := json.Marshal(batch)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"total": 2,
+			"succeeded": ["sku-1"],
+			"failed": [{"key": "sku-2", "error": {"specversion": 1, "code": "FAILED_PRECONDITION", "message": "failed precondition", "domain": "shopify.inventory", "reason": "OUT_OF_STOCK", "visibility": "PUBLIC", "subject": "/items/1"}}]
+		}`, string(data))
+
+		var decoded trogonerror.BatchError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, 2, decoded.Len())
+		failure, ok := decoded.Get("sku-2")
+		assert.True(t, ok)
+		assert.True(t, outOfStock.Is(failure))
+	})
+
+	t.Run("HTTP response", func(t *testing.T) {
+		batch := trogonerror.NewIndexedBatchError(2)
+		batch.FailIndex(0, notFound.NewError())
+		recorder := httptest.NewRecorder()
+
+		assert.NoError(t, batch.WriteHTTPResponse(recorder, nil))
+
+		assert.Equal(t, http.StatusMultiStatus, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), `"succeeded":["1"]`)
+	})
+}
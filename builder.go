@@ -0,0 +1,89 @@
+package trogonerror
+
+import "time"
+
+// Builder is a fluent alternative to functional options for errors with many fields.
+// Every method records the equivalent ErrorOption, so Err and ErrE produce exactly the errors
+// NewError and NewErrorE would, with the same validation.
+//
+// Example usage:
+//
+//	err := trogonerror.Build("shopify.orders", "ORDER_NOT_FOUND").
+//	    Code(trogonerror.CodeNotFound).
+//	    Meta(trogonerror.VisibilityPublic, "orderId", orderID).
+//	    Public().
+//	    Err()
+type Builder struct {
+	domain  string
+	reason  string
+	options []ErrorOption
+}
+
+// Build starts building an error with the given domain and reason
+func Build(domain, reason string) *Builder {
+	return &Builder{domain: domain, reason: reason}
+}
+
+// With records arbitrary options, for fields without a dedicated builder method
+func (b *Builder) With(options ...ErrorOption) *Builder {
+	b.options = append(b.options, options...)
+	return b
+}
+
+func (b *Builder) Code(code Code) *Builder           { return b.With(WithCode(code)) }
+func (b *Builder) Message(message string) *Builder   { return b.With(WithMessage(message)) }
+func (b *Builder) Subject(subject string) *Builder   { return b.With(WithSubject(subject)) }
+func (b *Builder) ID(id string) *Builder             { return b.With(WithID(id)) }
+func (b *Builder) Time(timestamp time.Time) *Builder { return b.With(WithTime(timestamp)) }
+func (b *Builder) Owner(owner string) *Builder       { return b.With(WithOwner(owner)) }
+func (b *Builder) Tags(tags ...string) *Builder      { return b.With(WithTags(tags...)) }
+func (b *Builder) Wrap(err error) *Builder           { return b.With(WithWrap(err)) }
+func (b *Builder) StackTrace() *Builder              { return b.With(WithStackTrace()) }
+
+// Meta sets a single metadata entry
+func (b *Builder) Meta(visibility Visibility, key, value string) *Builder {
+	return b.With(WithMetadataValue(visibility, key, value))
+}
+
+// Visibility sets the error visibility
+func (b *Builder) Visibility(visibility Visibility) *Builder {
+	return b.With(WithVisibility(visibility))
+}
+
+// Public makes the error visible to external users
+func (b *Builder) Public() *Builder { return b.Visibility(VisibilityPublic) }
+
+// Private makes the error visible within the organization
+func (b *Builder) Private() *Builder { return b.Visibility(VisibilityPrivate) }
+
+// Internal restricts the error to the producing service, the default
+func (b *Builder) Internal() *Builder { return b.Visibility(VisibilityInternal) }
+
+// PublicMessage sets the sanitized message for external users
+func (b *Builder) PublicMessage(message string) *Builder { return b.With(WithPublicMessage(message)) }
+
+// Cause attaches causes to the error
+func (b *Builder) Cause(causes ...*TrogonError) *Builder { return b.With(WithCause(causes...)) }
+
+// HelpLink adds a documentation link
+func (b *Builder) HelpLink(description, url string) *Builder {
+	return b.With(WithHelpLink(description, url))
+}
+
+// RetryAfter sets the retry offset
+func (b *Builder) RetryAfter(retryOffset time.Duration) *Builder {
+	return b.With(WithRetryInfoDuration(retryOffset))
+}
+
+// Transient marks the error as transient or permanent
+func (b *Builder) Transient(transient bool) *Builder { return b.With(WithTransient(transient)) }
+
+// Err creates the error like NewError
+func (b *Builder) Err() *TrogonError {
+	return NewError(b.domain, b.reason, b.options...)
+}
+
+// ErrE creates the error like NewErrorE, validating the domain, reason and recorded options
+func (b *Builder) ErrE() (*TrogonError, error) {
+	return NewErrorE(b.domain, b.reason, b.options...)
+}
@@ -0,0 +1,49 @@
+package trogonerror_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("Produces the same error as functional options", func(t *testing.T) {
+		timestamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+		built := trogonerror.Build("shopify.orders", "ORDER_NOT_FOUND").
+			Code(trogonerror.CodeNotFound).
+			Message("order not found").
+			Meta(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1").
+			Public().
+			Subject("/orderId").
+			ID("err_123").
+			Time(timestamp).
+			Tags("orders").
+			HelpLink("Orders API", "https://shopify.dev/docs/api/orders").
+			RetryAfter(5 * time.Second).
+			Err()
+
+		expected := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithMessage("order not found"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithSubject("/orderId"),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithTime(timestamp),
+			trogonerror.WithTags("orders"),
+			trogonerror.WithHelpLink("Orders API", "https://shopify.dev/docs/api/orders"),
+			trogonerror.WithRetryInfoDuration(5*time.Second))
+
+		assert.True(t, trogonerror.Equal(expected, built), trogonerror.Diff(expected, built))
+	})
+
+	t.Run("ErrE validates", func(t *testing.T) {
+		_, err := trogonerror.Build("Shopify Orders", "ORDER_NOT_FOUND").Public().Private().ErrE()
+
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidDomain)
+		assert.ErrorIs(t, err, trogonerror.ErrConflictingOptions)
+	})
+}
@@ -0,0 +1,49 @@
+package trogonerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// MarshalCanonicalJSON encodes the error as byte-for-byte deterministic JSON, suitable for
+// content-addressable storage, signing and deduplication hashes. Equal errors always produce
+// the same bytes: object keys are in a fixed order (metadata keys sorted), timestamps are
+// normalized to UTC, there is no insignificant whitespace and HTML characters are not escaped.
+// It is subject to DefaultSerializationPolicy; stack traces make the output build-dependent,
+// so hashes are usually computed for an audience without debug info.
+func (e TrogonError) MarshalCanonicalJSON() ([]byte, error) {
+	return e.MarshalCanonicalJSONFor(DefaultSerializationPolicy())
+}
+
+// MarshalCanonicalJSONFor encodes the error like MarshalCanonicalJSON, applying policy instead of the default one
+func (e TrogonError) MarshalCanonicalJSONFor(policy *SerializationPolicy) ([]byte, error) {
+	out := e.toJSON(policy)
+	normalizeJSONTimes(&out)
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(out); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func normalizeJSONTimes(j *jsonError) {
+	j.Time = utcTime(j.Time)
+	if j.RetryInfo != nil {
+		j.RetryInfo.RetryTime = utcTime(j.RetryInfo.RetryTime)
+	}
+	for i := range j.Causes {
+		normalizeJSONTimes(&j.Causes[i])
+	}
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,40 @@
+package trogonerror_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	newError := func(location *time.Location, metadata ...string) *trogonerror.TrogonError {
+		options := []trogonerror.ErrorOption{
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithTime(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC).In(location)),
+			trogonerror.WithRetryTime(time.Date(2024, 1, 15, 10, 35, 0, 0, time.UTC).In(location)),
+		}
+		for i := 0; i < len(metadata); i += 2 {
+			options = append(options, trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, metadata[i], metadata[i+1]))
+		}
+		return trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", options...)
+	}
+
+	t.Run("Layout", func(t *testing.T) {
+		data, err := newError(time.UTC, "query", "a<b&c").MarshalCanonicalJSON()
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"specversion":1,"code":"NOT_FOUND","message":"resource not found","domain":"shopify.orders","reason":"ORDER_NOT_FOUND","metadata":{"query":{"value":"a<b&c","visibility":"PUBLIC"}},"visibility":"INTERNAL","id":"err_123","time":"2024-01-15T10:30:00Z","retryInfo":{"retryTime":"2024-01-15T10:35:00Z"}}`, string(data))
+	})
+
+	t.Run("Same bytes regardless of time zone and metadata order", func(t *testing.T) {
+		tokyo := time.FixedZone("JST", 9*60*60)
+
+		first, _ := newError(time.UTC, "a", "1", "b", "2", "c", "3").MarshalCanonicalJSON()
+		second, _ := newError(tokyo, "c", "3", "b", "2", "a", "1").MarshalCanonicalJSON()
+
+		assert.Equal(t, string(first), string(second))
+	})
+}
@@ -0,0 +1,53 @@
+package trogonerror
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock returns the current time; it can be replaced to make timestamps deterministic
+type Clock func() time.Time
+
+var (
+	clock    atomic.Pointer[Clock]
+	autoTime atomic.Bool
+)
+
+// SetClock replaces the clock used to timestamp errors.
+// Passing nil restores time.Now.
+func SetClock(c Clock) {
+	if c == nil {
+		clock.Store(nil)
+		return
+	}
+	clock.Store(&c)
+}
+
+// SetAutoTime enables or disables automatic timestamping.
+// When enabled, every error created without an explicit time receives the current clock time.
+func SetAutoTime(enabled bool) {
+	autoTime.Store(enabled)
+}
+
+func now() time.Time {
+	if c := clock.Load(); c != nil {
+		return (*c)()
+	}
+	return time.Now()
+}
+
+// WithTimeNow sets the error timestamp to the current clock time
+func WithTimeNow() ErrorOption {
+	return mutable(func(e *TrogonError) {
+		timestamp := now()
+		e.time = &timestamp
+	})
+}
+
+// WithChangeTimeNow sets the timestamp to the current clock time
+func WithChangeTimeNow() ChangeOption {
+	return mutable(func(e *TrogonError) {
+		timestamp := now()
+		e.time = &timestamp
+	})
+}
@@ -0,0 +1,80 @@
+package trogonerror_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	fixed := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+
+	t.Run("WithTimeNow uses the injected clock", func(t *testing.T) {
+		trogonerror.SetClock(func() time.Time { return fixed })
+		t.Cleanup(func() { trogonerror.SetClock(nil) })
+
+		err := trogonerror.NewError("shopify.scheduler", "SCHEDULE_CONFLICT",
+			trogonerror.WithTimeNow())
+
+		assert.NotNil(t, err.Time())
+		assert.True(t, err.Time().Equal(fixed))
+	})
+
+	t.Run("WithTimeNow defaults to time.Now", func(t *testing.T) {
+		before := time.Now()
+		err := trogonerror.NewError("shopify.scheduler", "SCHEDULE_CONFLICT",
+			trogonerror.WithTimeNow())
+
+		assert.NotNil(t, err.Time())
+		assert.False(t, err.Time().Before(before))
+	})
+
+	t.Run("WithChangeTimeNow replaces the timestamp on the copy only", func(t *testing.T) {
+		trogonerror.SetClock(func() time.Time { return fixed })
+		t.Cleanup(func() { trogonerror.SetClock(nil) })
+
+		original := trogonerror.NewError("shopify.scheduler", "SCHEDULE_CONFLICT")
+		modified := original.WithChanges(trogonerror.WithChangeTimeNow())
+
+		assert.Nil(t, original.Time())
+		assert.True(t, modified.Time().Equal(fixed))
+	})
+
+	t.Run("SetAutoTime timestamps errors automatically", func(t *testing.T) {
+		trogonerror.SetClock(func() time.Time { return fixed })
+		trogonerror.SetAutoTime(true)
+		t.Cleanup(func() {
+			trogonerror.SetClock(nil)
+			trogonerror.SetAutoTime(false)
+		})
+
+		err := trogonerror.NewError("shopify.scheduler", "SCHEDULE_CONFLICT")
+		assert.NotNil(t, err.Time())
+		assert.True(t, err.Time().Equal(fixed))
+
+		template := trogonerror.NewErrorTemplate("shopify.scheduler", "SCHEDULE_CONFLICT")
+		assert.True(t, template.NewError().Time().Equal(fixed))
+	})
+
+	t.Run("SetAutoTime keeps explicit timestamps", func(t *testing.T) {
+		explicit := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+		trogonerror.SetClock(func() time.Time { return fixed })
+		trogonerror.SetAutoTime(true)
+		t.Cleanup(func() {
+			trogonerror.SetClock(nil)
+			trogonerror.SetAutoTime(false)
+		})
+
+		err := trogonerror.NewError("shopify.scheduler", "SCHEDULE_CONFLICT",
+			trogonerror.WithTime(explicit))
+
+		assert.True(t, err.Time().Equal(explicit))
+	})
+
+	t.Run("Errors have no timestamp when auto time is disabled", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.scheduler", "SCHEDULE_CONFLICT")
+		assert.Nil(t, err.Time())
+	})
+}
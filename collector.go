@@ -0,0 +1,105 @@
+package trogonerror
+
+import (
+	"context"
+	"sync"
+)
+
+// ErrMultiple is the template of the aggregate error produced by Collector.Err
+var ErrMultiple = NewErrorTemplate("trogon.runtime", "MULTIPLE_ERRORS")
+
+// Collector accumulates errors during a request, such as every field violation found while validating
+// a form, and finalizes them into a single aggregate error. It is safe for concurrent use.
+//
+// Example usage:
+//
+//	collector := trogonerror.NewCollector()
+//	ctx = trogonerror.NewCollectorContext(ctx, collector)
+//	validateAddress(ctx, req.Address) // calls trogonerror.Collect(ctx, ...) for each violation
+//	validatePayment(ctx, req.Payment)
+//	if err := collector.Err(); err != nil {
+//	    return err
+//	}
+type Collector struct {
+	mu     sync.Mutex
+	errors []*TrogonError
+}
+
+// NewCollector creates an empty collector
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records errors in the collector, ignoring nil ones
+func (c *Collector) Add(errs ...*TrogonError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, err := range errs {
+		if err != nil {
+			c.errors = append(c.errors, err)
+		}
+	}
+}
+
+// Len returns the number of collected errors
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.errors)
+}
+
+// Errors returns the collected errors in the order they were added
+func (c *Collector) Errors() []*TrogonError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*TrogonError(nil), c.errors...)
+}
+
+// Err finalizes the collected errors into an ErrMultiple error carrying them as causes, or returns nil
+// when nothing was collected. The aggregate takes the code shared by all causes (CodeUnknown when they
+// differ) and the least visible of their visibilities; options are applied afterwards and may override both.
+func (c *Collector) Err(options ...ErrorOption) *TrogonError {
+	causes := c.Errors()
+	if len(causes) == 0 {
+		return nil
+	}
+
+	code, visibility := causes[0].code, causes[0].visibility
+	for _, cause := range causes[1:] {
+		if cause.code != code {
+			code = CodeUnknown
+		}
+		visibility = min(visibility, cause.visibility)
+	}
+
+	return ErrMultiple.NewError(append([]ErrorOption{
+		WithCode(code),
+		WithVisibility(visibility),
+		WithCause(causes...),
+	}, options...)...)
+}
+
+type collectorContextKey struct{}
+
+// NewCollectorContext returns a copy of ctx carrying the collector, so nested calls can report errors with Collect
+func NewCollectorContext(ctx context.Context, collector *Collector) context.Context {
+	return context.WithValue(ctx, collectorContextKey{}, collector)
+}
+
+// CollectorFromContext returns the collector stored in ctx by NewCollectorContext, if any
+func CollectorFromContext(ctx context.Context) (*Collector, bool) {
+	collector, ok := ctx.Value(collectorContextKey{}).(*Collector)
+	return collector, ok && collector != nil
+}
+
+// Collect adds errors to the collector carried by ctx, reporting false when ctx has no collector
+func Collect(ctx context.Context, errs ...*TrogonError) bool {
+	collector, ok := CollectorFromContext(ctx)
+	if ok {
+		collector.Add(errs...)
+	}
+	return ok
+}
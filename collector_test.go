@@ -0,0 +1,53 @@
+package trogonerror_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	invalidField := trogonerror.NewErrorTemplate("shopify.checkout", "INVALID_FIELD",
+		trogonerror.TemplateWithCode(trogonerror.CodeInvalidArgument),
+		trogonerror.TemplateWithVisibility(trogonerror.VisibilityPublic))
+
+	t.Run("Aggregates the collected errors", func(t *testing.T) {
+		collector := trogonerror.NewCollector()
+		ctx := trogonerror.NewCollectorContext(context.Background(), collector)
+
+		assert.True(t, trogonerror.Collect(ctx, invalidField.NewError(trogonerror.WithSubject("/email"))))
+		assert.True(t, trogonerror.Collect(ctx, invalidField.NewError(trogonerror.WithSubject("/address/zip")), nil))
+
+		err := collector.Err(trogonerror.WithMessage("the checkout form is invalid"))
+
+		assert.True(t, trogonerror.ErrMultiple.Is(err))
+		assert.Equal(t, trogonerror.CodeInvalidArgument, err.Code())
+		assert.Equal(t, trogonerror.VisibilityPublic, err.Visibility())
+		assert.Equal(t, "the checkout form is invalid", err.Message())
+		assert.Len(t, err.Causes(), 2)
+		assert.Equal(t, "/email", err.Causes()[0].Subject())
+		assert.Equal(t, "/address/zip", err.Causes()[1].Subject())
+	})
+
+	t.Run("Mixed codes and visibilities", func(t *testing.T) {
+		collector := trogonerror.NewCollector()
+		collector.Add(invalidField.NewError(), trogonerror.NewError("shopify.inventory", "OUT_OF_STOCK",
+			trogonerror.WithCode(trogonerror.CodeFailedPrecondition),
+			trogonerror.WithVisibility(trogonerror.VisibilityPrivate)))
+
+		err := collector.Err()
+
+		assert.Equal(t, trogonerror.CodeUnknown, err.Code())
+		assert.Equal(t, trogonerror.VisibilityPrivate, err.Visibility())
+	})
+
+	t.Run("Nothing collected", func(t *testing.T) {
+		assert.Nil(t, trogonerror.NewCollector().Err())
+	})
+
+	t.Run("Context without a collector", func(t *testing.T) {
+		assert.False(t, trogonerror.Collect(context.Background(), invalidField.NewError()))
+	})
+}
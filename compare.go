@@ -0,0 +1,402 @@
+package trogonerror
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type compareOptions struct {
+	ignoreTime       bool
+	ignoreID         bool
+	ignoreStackTrace bool
+}
+
+// CompareOption represents options for Equal and Diff
+type CompareOption func(*compareOptions)
+
+// CompareIgnoringTime ignores the timestamps of the errors and their causes
+func CompareIgnoringTime() CompareOption {
+	return func(o *compareOptions) { o.ignoreTime = true }
+}
+
+// CompareIgnoringID ignores the IDs of the errors and their causes
+func CompareIgnoringID() CompareOption {
+	return func(o *compareOptions) { o.ignoreID = true }
+}
+
+// CompareIgnoringStackTrace ignores the stack traces of the errors and their causes
+func CompareIgnoringStackTrace() CompareOption {
+	return func(o *compareOptions) { o.ignoreStackTrace = true }
+}
+
+// Equal reports whether two errors are semantically equal, field by field and through their causes.
+// Stack frames are compared by file, line and function, and wrapped errors by their text,
+// so an error equals itself after a serialization round-trip.
+func Equal(a, b *TrogonError, options ...CompareOption) bool {
+	return Diff(a, b, options...) == ""
+}
+
+// Diff describes the differences between two errors, one field per line as
+// "path: want != got", or returns an empty string when they are equal
+func Diff(want, got *TrogonError, options ...CompareOption) string {
+	var opts compareOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var d differ
+	d.opts = opts
+	d.compare("", want, got)
+	return strings.Join(d.lines, "\n")
+}
+
+type differ struct {
+	opts  compareOptions
+	lines []string
+}
+
+func (d *differ) add(path, field string, want, got any) {
+	d.lines = append(d.lines, fmt.Sprintf("%s: %v != %v", fieldPath(path, field), want, got))
+}
+
+func (d *differ) compareString(path, field, want, got string) {
+	if want != got {
+		d.add(path, field, strconv.Quote(want), strconv.Quote(got))
+	}
+}
+
+func (d *differ) compare(path string, want, got *TrogonError) {
+	if want == nil || got == nil {
+		if want != got {
+			d.add(path, "error", describe(want), describe(got))
+		}
+		return
+	}
+
+	if want.specVersion != got.specVersion {
+		d.add(path, "specVersion", want.specVersion, got.specVersion)
+	}
+	if want.code != got.code {
+		d.add(path, "code", want.code, got.code)
+	}
+	d.compareString(path, "message", want.Message(), got.Message())
+	d.compareString(path, "publicMessage", want.publicMessage, got.publicMessage)
+	d.compareString(path, "domain", want.domain, got.domain)
+	d.compareString(path, "reason", want.reason, got.reason)
+	if want.visibility != got.visibility {
+		d.add(path, "visibility", want.visibility, got.visibility)
+	}
+	d.compareString(path, "subject", want.subject, got.subject)
+	if !d.opts.ignoreID {
+		d.compareString(path, "id", want.id, got.id)
+	}
+	if !d.opts.ignoreTime && !equalTime(want.time, got.time) {
+		d.add(path, "time", formatTime(want.time), formatTime(got.time))
+	}
+	d.compareString(path, "sourceId", want.sourceID, got.sourceID)
+	d.compareString(path, "owner", want.owner, got.owner)
+	if !slices.Equal(want.tags, got.tags) {
+		d.add(path, "tags", want.tags, got.tags)
+	}
+	if !slices.Equal(want.Operations(), got.Operations()) {
+		d.add(path, "operations", want.Operations(), got.Operations())
+	}
+	if want.StatusCode() != got.StatusCode() {
+		d.add(path, "statusCode", want.StatusCode(), got.StatusCode())
+	}
+	if want.grpcCode != got.grpcCode {
+		d.add(path, "grpcCode", want.grpcCode, got.grpcCode)
+	}
+	if want.idempotency != got.idempotency {
+		d.add(path, "idempotency", want.idempotency, got.idempotency)
+	}
+	if !equalBool(want.transient, got.transient) {
+		d.add(path, "transient", formatBool(want.transient), formatBool(got.transient))
+	}
+	if want.IsRetryable() != got.IsRetryable() {
+		d.add(path, "retryable", want.IsRetryable(), got.IsRetryable())
+	}
+
+	d.compareMetadata(path, want.metadata, got.metadata)
+	d.compareHelp(path, want.Help(), got.Help())
+	d.compareLocalizedMessage(path, want.localizedMessage, got.localizedMessage)
+	d.compareTranslations(path, want.translations, got.translations)
+	d.compareRetryInfo(path, want.retryInfo, got.retryInfo)
+	d.compareRateLimitInfo(path, want.rateLimitInfo, got.rateLimitInfo)
+	d.compareDeadlineInfo(path, want.deadlineInfo, got.deadlineInfo)
+	d.compareAuthInfo(path, want.authInfo, got.authInfo)
+	d.compareDebugInfo(path, want.debugInfo, got.debugInfo)
+	d.compareString(path, "wrappedError", errorText(want.wrappedErr), errorText(got.wrappedErr))
+
+	if len(want.causes) != len(got.causes) {
+		d.add(path, "causes", len(want.causes), len(got.causes))
+	}
+	for i := range min(len(want.causes), len(got.causes)) {
+		d.compare(fieldPath(path, "causes["+strconv.Itoa(i)+"]"), want.causes[i], got.causes[i])
+	}
+}
+
+func (d *differ) compareMetadata(path string, want, got Metadata) {
+	keys := slices.Sorted(maps.Keys(want))
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		wantValue, inWant := want[key]
+		gotValue, inGot := got[key]
+		if inWant == inGot && wantValue == gotValue {
+			continue
+		}
+		d.add(path, "metadata["+strconv.Quote(key)+"]", describeMetadata(wantValue, inWant), describeMetadata(gotValue, inGot))
+	}
+}
+
+func (d *differ) compareHelp(path string, want, got *Help) {
+	var wantLinks, gotLinks []HelpLink
+	if want != nil {
+		wantLinks = want.links
+	}
+	if got != nil {
+		gotLinks = got.links
+	}
+	if len(wantLinks) != len(gotLinks) {
+		d.add(path, "help.links", len(wantLinks), len(gotLinks))
+		return
+	}
+	for i := range wantLinks {
+		if wantLinks[i] != gotLinks[i] {
+			d.add(path, "help.links["+strconv.Itoa(i)+"]", describeHelpLink(wantLinks[i]), describeHelpLink(gotLinks[i]))
+		}
+	}
+}
+
+func (d *differ) compareLocalizedMessage(path string, want, got *LocalizedMessage) {
+	var wantValue, gotValue LocalizedMessage
+	if want != nil {
+		wantValue = *want
+	}
+	if got != nil {
+		gotValue = *got
+	}
+	d.compareString(path, "localizedMessage.locale", wantValue.locale, gotValue.locale)
+	d.compareString(path, "localizedMessage.message", wantValue.message, gotValue.message)
+}
+
+func (d *differ) compareTranslations(path string, want, got []LocalizedMessage) {
+	if len(want) != len(got) {
+		d.add(path, "localizedMessages", len(want), len(got))
+		return
+	}
+	for i := range want {
+		field := "localizedMessages[" + strconv.Itoa(i) + "]"
+		d.compareString(path, field+".locale", want[i].locale, got[i].locale)
+		d.compareString(path, field+".message", want[i].message, got[i].message)
+	}
+}
+
+func (d *differ) compareRetryInfo(path string, want, got *RetryInfo) {
+	var wantValue, gotValue RetryInfo
+	if want != nil {
+		wantValue = *want
+	}
+	if got != nil {
+		gotValue = *got
+	}
+	if !equalDuration(wantValue.retryOffset, gotValue.retryOffset) {
+		d.add(path, "retryInfo.retryOffset", formatDuration(wantValue.retryOffset), formatDuration(gotValue.retryOffset))
+	}
+	if !equalTime(wantValue.retryTime, gotValue.retryTime) {
+		d.add(path, "retryInfo.retryTime", formatTime(wantValue.retryTime), formatTime(gotValue.retryTime))
+	}
+	if wantValue.maxAttempts != gotValue.maxAttempts {
+		d.add(path, "retryInfo.maxAttempts", wantValue.maxAttempts, gotValue.maxAttempts)
+	}
+	if wantValue.backoffMultiplier != gotValue.backoffMultiplier {
+		d.add(path, "retryInfo.backoffMultiplier", wantValue.backoffMultiplier, gotValue.backoffMultiplier)
+	}
+	if wantValue.maxDelay != gotValue.maxDelay {
+		d.add(path, "retryInfo.maxDelay", wantValue.maxDelay, gotValue.maxDelay)
+	}
+}
+
+func (d *differ) compareRateLimitInfo(path string, want, got *RateLimitInfo) {
+	var wantValue, gotValue RateLimitInfo
+	if want != nil {
+		wantValue = *want
+	}
+	if got != nil {
+		gotValue = *got
+	}
+	if wantValue.limit != gotValue.limit {
+		d.add(path, "rateLimitInfo.limit", wantValue.limit, gotValue.limit)
+	}
+	if wantValue.remaining != gotValue.remaining {
+		d.add(path, "rateLimitInfo.remaining", wantValue.remaining, gotValue.remaining)
+	}
+	if wantValue.window != gotValue.window {
+		d.add(path, "rateLimitInfo.window", wantValue.window, gotValue.window)
+	}
+	if !equalTime(wantValue.resetTime, gotValue.resetTime) {
+		d.add(path, "rateLimitInfo.resetTime", formatTime(wantValue.resetTime), formatTime(gotValue.resetTime))
+	}
+}
+
+func (d *differ) compareDeadlineInfo(path string, want, got *DeadlineInfo) {
+	var wantValue, gotValue DeadlineInfo
+	if want != nil {
+		wantValue = *want
+	}
+	if got != nil {
+		gotValue = *got
+	}
+	if !wantValue.deadline.Equal(gotValue.deadline) {
+		d.add(path, "deadlineInfo.deadline", wantValue.deadline.Format(time.RFC3339Nano), gotValue.deadline.Format(time.RFC3339Nano))
+	}
+	if wantValue.elapsed != gotValue.elapsed {
+		d.add(path, "deadlineInfo.elapsed", wantValue.elapsed, gotValue.elapsed)
+	}
+	if len(wantValue.stages) != len(gotValue.stages) {
+		d.add(path, "deadlineInfo.stages", len(wantValue.stages), len(gotValue.stages))
+		return
+	}
+	for i := range wantValue.stages {
+		if wantValue.stages[i] != gotValue.stages[i] {
+			field := "deadlineInfo.stages[" + strconv.Itoa(i) + "]"
+			d.add(path, field, wantValue.stages[i].name+"="+wantValue.stages[i].duration.String(),
+				gotValue.stages[i].name+"="+gotValue.stages[i].duration.String())
+		}
+	}
+}
+
+func (d *differ) compareAuthInfo(path string, want, got *AuthInfo) {
+	var wantValue, gotValue AuthInfo
+	if want != nil {
+		wantValue = *want
+	}
+	if got != nil {
+		gotValue = *got
+	}
+	d.compareString(path, "authInfo.scheme", wantValue.scheme, gotValue.scheme)
+	d.compareString(path, "authInfo.requiredScopes", strings.Join(wantValue.requiredScopes, ","), strings.Join(gotValue.requiredScopes, ","))
+	d.compareString(path, "authInfo.requiredRoles", strings.Join(wantValue.requiredRoles, ","), strings.Join(gotValue.requiredRoles, ","))
+	d.compareString(path, "authInfo.principalClass", wantValue.principalClass, gotValue.principalClass)
+}
+
+func (d *differ) compareDebugInfo(path string, want, got *DebugInfo) {
+	var wantValue, gotValue DebugInfo
+	if want != nil {
+		wantValue = *want
+	}
+	if got != nil {
+		gotValue = *got
+	}
+	d.compareString(path, "debugInfo.detail", wantValue.detail, gotValue.detail)
+	d.compareString(path, "debugInfo.goroutines", wantValue.goroutines, gotValue.goroutines)
+	d.compareString(path, "debugInfo.source", wantValue.sourceSnippet, gotValue.sourceSnippet)
+	d.compareString(path, "debugInfo.runtime", runtimeText(wantValue.runtimeInfo), runtimeText(gotValue.runtimeInfo))
+
+	if d.opts.ignoreStackTrace {
+		return
+	}
+	wantEntries, gotEntries := wantValue.StackEntries(), gotValue.StackEntries()
+	if len(wantEntries) != len(gotEntries) {
+		d.add(path, "debugInfo.stackEntries", len(wantEntries), len(gotEntries))
+		return
+	}
+	for i := range wantEntries {
+		d.compareString(path, "debugInfo.stackEntries["+strconv.Itoa(i)+"]", wantEntries[i], gotEntries[i])
+	}
+}
+
+func fieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func describe(e *TrogonError) string {
+	if e == nil {
+		return "<nil>"
+	}
+	return e.summary()
+}
+
+func describeMetadata(value MetadataValue, ok bool) string {
+	if !ok {
+		return "<missing>"
+	}
+	return strconv.Quote(value.value) + " (" + value.visibility.String() + ")"
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func runtimeText(info *RuntimeInfo) string {
+	if info == nil {
+		return ""
+	}
+	return info.String()
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func equalDuration(a, b *time.Duration) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func describeHelpLink(link HelpLink) string {
+	description := link.description + " <" + link.url + ">"
+	if link.kind != HelpLinkDocumentation {
+		description = link.kind.String() + " " + description
+	}
+	return description
+}
+
+func equalBool(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatBool(b *bool) string {
+	if b == nil {
+		return "<nil>"
+	}
+	return strconv.FormatBool(*b)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "<nil>"
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func formatDuration(d *time.Duration) string {
+	if d == nil {
+		return "<nil>"
+	}
+	return d.String()
+}
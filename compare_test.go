@@ -0,0 +1,72 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	t.Run("Equal after a serialization round-trip", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithTimeNow(),
+			trogonerror.WithStackTrace(),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "orderId", "1"),
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "ROW_MISSING")))
+
+		data, err := original.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, err)
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+
+		assert.True(t, trogonerror.Equal(original, &decoded), trogonerror.Diff(original, &decoded))
+	})
+
+	t.Run("Ignoring time, ID and stack trace", func(t *testing.T) {
+		first := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithTimeNow(), trogonerror.WithGeneratedID(), trogonerror.WithStackTrace())
+		second := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithTime(time.Unix(0, 0)), trogonerror.WithGeneratedID(), trogonerror.WithStackTrace())
+
+		assert.False(t, trogonerror.Equal(first, second))
+		assert.True(t, trogonerror.Equal(first, second,
+			trogonerror.CompareIgnoringTime(),
+			trogonerror.CompareIgnoringID(),
+			trogonerror.CompareIgnoringStackTrace()))
+	})
+
+	t.Run("Nil errors", func(t *testing.T) {
+		assert.True(t, trogonerror.Equal(nil, nil))
+		assert.False(t, trogonerror.Equal(nil, trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND")))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	want := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shopId", "7"),
+		trogonerror.WithRetryInfoDuration(time.Second),
+		trogonerror.WithCause(trogonerror.NewError("shopify.database", "ROW_MISSING")))
+	got := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "orderId", "1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "region", "us"),
+		trogonerror.WithRetryInfoDuration(2*time.Second),
+		trogonerror.WithCause(trogonerror.NewError("shopify.database", "CONNECTION_FAILED")))
+
+	assert.Equal(t, `code: NOT_FOUND != INTERNAL
+message: "resource not found" != "internal error"
+statusCode: 404 != 500
+metadata["orderId"]: "1" (PUBLIC) != "1" (INTERNAL)
+metadata["region"]: <missing> != "us" (INTERNAL)
+metadata["shopId"]: "7" (INTERNAL) != <missing>
+retryInfo.retryOffset: 1s != 2s
+causes[0].reason: "ROW_MISSING" != "CONNECTION_FAILED"`, trogonerror.Diff(want, got))
+
+	assert.Empty(t, trogonerror.Diff(want, want))
+}
@@ -0,0 +1,125 @@
+package trogonerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ContextExtractor reads a single metadata value from a context.Context
+type ContextExtractor func(ctx context.Context) (string, bool)
+
+type contextExtractorEntry struct {
+	key       string
+	extractor ContextExtractor
+}
+
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   []contextExtractorEntry
+)
+
+// RegisterContextExtractor registers an extractor whose value WithContext records under the given metadata key.
+// Registering the same key again replaces the previous extractor.
+// It is intended to be called during program initialization and panics on keys in the reserved "trogon." namespace.
+func RegisterContextExtractor(key string, extractor ContextExtractor) {
+	if IsReservedMetadataKey(key) {
+		panic("trogonerror: context extractor key " + strconv.Quote(key) + " is in the reserved namespace")
+	}
+
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+
+	for i, entry := range contextExtractors {
+		if entry.key == key {
+			contextExtractors[i].extractor = extractor
+			return
+		}
+	}
+	contextExtractors = append(contextExtractors, contextExtractorEntry{key: key, extractor: extractor})
+}
+
+// RegisterContextKey registers a context key whose value WithContext records under the given metadata key.
+// Example: RegisterContextKey("tenantId", tenantIDKey{})
+func RegisterContextKey(key string, ctxKey any) {
+	RegisterContextExtractor(key, ContextKeyExtractor(ctxKey))
+}
+
+// ContextKeyExtractor returns an extractor reading the value stored in the context under ctxKey
+func ContextKeyExtractor(ctxKey any) ContextExtractor {
+	return func(ctx context.Context) (string, bool) {
+		value := ctx.Value(ctxKey)
+		if value == nil {
+			return "", false
+		}
+		return fmt.Sprint(value), true
+	}
+}
+
+// WithContext records well-known values from the context as metadata: every registered context extractor
+// and, when the context has a deadline, the remaining time under MetadataDeadlineRemainingKey as internal metadata,
+// and the request ID under MetadataRequestIDKey as private metadata, or internal on an internal error
+func WithContext(ctx context.Context) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if ctx == nil {
+			return
+		}
+
+		contextExtractorsMu.RLock()
+		for _, entry := range contextExtractors {
+			if value, ok := entry.extractor(ctx); ok {
+				addMetadataValue(e, VisibilityInternal, entry.key, value)
+			}
+		}
+		contextExtractorsMu.RUnlock()
+
+		withRequestID(ctx)(e)
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := deadline.Sub(now()).Round(time.Millisecond)
+			setMetadataValue(e, VisibilityInternal, MetadataDeadlineRemainingKey, remaining.String())
+		}
+	})
+}
+
+var (
+	// ErrContextCancelled is the template used by FromContextError for context.Canceled
+	ErrContextCancelled = NewErrorTemplate("trogon.context", "CANCELLED",
+		TemplateWithCode(CodeCancelled))
+
+	// ErrContextDeadlineExceeded is the template used by FromContextError for context.DeadlineExceeded
+	ErrContextDeadlineExceeded = NewErrorTemplate("trogon.context", "DEADLINE_EXCEEDED",
+		TemplateWithCode(CodeDeadlineExceeded))
+)
+
+// FromContextError converts context.Canceled into a CodeCancelled error and context.DeadlineExceeded
+// into a CodeDeadlineExceeded error, wrapping the original error.
+// Returns nil and false when err is not a context error.
+func FromContextError(err error, options ...ErrorOption) (*TrogonError, bool) {
+	var template *ErrorTemplate
+	switch {
+	case errors.Is(err, context.Canceled):
+		template = ErrContextCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		template = ErrContextDeadlineExceeded
+	default:
+		return nil, false
+	}
+
+	return template.NewError(append([]ErrorOption{WithWrap(err)}, options...)...), true
+}
+
+type errorContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the given error, so an outer layer can retrieve it with FromContext
+func NewContext(ctx context.Context, err *TrogonError) context.Context {
+	return context.WithValue(ctx, errorContextKey{}, err)
+}
+
+// FromContext returns the error stored in ctx by NewContext, if any
+func FromContext(ctx context.Context) (*TrogonError, bool) {
+	err, ok := ctx.Value(errorContextKey{}).(*TrogonError)
+	return err, ok && err != nil
+}
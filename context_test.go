@@ -0,0 +1,148 @@
+package trogonerror_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantIDKey struct{}
+
+type userIDKey struct{}
+
+func TestWithContext(t *testing.T) {
+	trogonerror.RegisterContextKey("tenantId", tenantIDKey{})
+	trogonerror.RegisterContextExtractor("userId", func(ctx context.Context) (string, bool) {
+		userID, ok := ctx.Value(userIDKey{}).(string)
+		return "gid://shopify/Customer/" + userID, ok
+	})
+
+	t.Run("WithContext records registered values as internal metadata", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), tenantIDKey{}, "mystore.myshopify.com")
+		ctx = context.WithValue(ctx, userIDKey{}, "1234567890")
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(ctx))
+
+		assert.Equal(t, "mystore.myshopify.com", err.Metadata()["tenantId"].Value())
+		assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()["tenantId"].Visibility())
+		assert.Equal(t, "gid://shopify/Customer/1234567890", err.Metadata()["userId"].Value())
+		assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()["userId"].Visibility())
+	})
+
+	t.Run("WithContext skips values missing from the context", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(context.Background()))
+
+		assert.Empty(t, err.Metadata())
+	})
+
+	t.Run("WithContext records the remaining deadline", func(t *testing.T) {
+		fixed := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+		trogonerror.SetClock(func() time.Time { return fixed })
+		t.Cleanup(func() { trogonerror.SetClock(nil) })
+
+		ctx, cancel := context.WithDeadline(context.Background(), fixed.Add(1500*time.Millisecond))
+		defer cancel()
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(ctx))
+
+		assert.Equal(t, "1.5s", err.Metadata()[trogonerror.MetadataDeadlineRemainingKey].Value())
+		assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()[trogonerror.MetadataDeadlineRemainingKey].Visibility())
+	})
+
+	t.Run("Explicit metadata after WithContext takes precedence", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), tenantIDKey{}, "mystore.myshopify.com")
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(ctx),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "tenantId", "otherstore.myshopify.com"))
+
+		assert.Equal(t, "otherstore.myshopify.com", err.Metadata()["tenantId"].Value())
+		assert.Equal(t, trogonerror.VisibilityPublic, err.Metadata()["tenantId"].Visibility())
+	})
+}
+
+func TestFromContextError(t *testing.T) {
+	t.Run("context.Canceled becomes CodeCancelled", func(t *testing.T) {
+		err, ok := trogonerror.FromContextError(context.Canceled)
+
+		assert.True(t, ok)
+		assert.Equal(t, trogonerror.CodeCancelled, err.Code())
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.True(t, trogonerror.ErrContextCancelled.Is(err))
+	})
+
+	t.Run("context.DeadlineExceeded becomes CodeDeadlineExceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		<-ctx.Done()
+
+		err, ok := trogonerror.FromContextError(fmt.Errorf("query users: %w", ctx.Err()),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPrivate, "operation", "users.query"))
+
+		assert.True(t, ok)
+		assert.Equal(t, trogonerror.CodeDeadlineExceeded, err.Code())
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.True(t, trogonerror.ErrContextDeadlineExceeded.Is(err))
+		assert.Equal(t, "users.query", err.Metadata()["operation"].Value())
+	})
+
+	t.Run("Other errors are not converted", func(t *testing.T) {
+		err, ok := trogonerror.FromContextError(errors.New("connection refused"))
+
+		assert.False(t, ok)
+		assert.Nil(t, err)
+	})
+}
+
+func TestErrorContext(t *testing.T) {
+	t.Run("FromContext returns the error stored by NewContext", func(t *testing.T) {
+		rateLimited := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted))
+
+		ctx := trogonerror.NewContext(context.Background(), rateLimited)
+
+		err, ok := trogonerror.FromContext(ctx)
+		assert.True(t, ok)
+		assert.Same(t, rateLimited, err)
+	})
+
+	t.Run("FromContext reports false when no error is stored", func(t *testing.T) {
+		err, ok := trogonerror.FromContext(context.Background())
+		assert.False(t, ok)
+		assert.Nil(t, err)
+
+		err, ok = trogonerror.FromContext(trogonerror.NewContext(context.Background(), nil))
+		assert.False(t, ok)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Inner layers can replace the stored error", func(t *testing.T) {
+		authErr := trogonerror.NewError("shopify.auth", "TOKEN_EXPIRED",
+			trogonerror.WithCode(trogonerror.CodeUnauthenticated))
+		rateErr := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted))
+
+		outer := trogonerror.NewContext(context.Background(), authErr)
+		inner := trogonerror.NewContext(outer, rateErr)
+
+		err, _ := trogonerror.FromContext(inner)
+		assert.Same(t, rateErr, err)
+
+		err, _ = trogonerror.FromContext(outer)
+		assert.Same(t, authErr, err)
+	})
+}
+
+func TestRegisterContextExtractor_ReservedKey(t *testing.T) {
+	assert.Panics(t, func() {
+		trogonerror.RegisterContextKey("trogon.traceId", struct{}{})
+	})
+}
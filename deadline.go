@@ -0,0 +1,109 @@
+package trogonerror
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// DeadlineInfo records the deadline a DeadlineExceeded error missed: the original deadline,
+// how long the operation ran, and optionally how that time was spent per stage.
+// Like debug info, it is only serialized when the policy allows debug info.
+type DeadlineInfo struct {
+	deadline time.Time
+	elapsed  time.Duration
+	stages   []DeadlineStage
+}
+
+// DeadlineStage is the time spent in one named stage of an operation, such as "auth" or "db.query"
+type DeadlineStage struct {
+	name     string
+	duration time.Duration
+}
+
+func (d DeadlineInfo) Deadline() time.Time    { return d.deadline }
+func (d DeadlineInfo) Elapsed() time.Duration { return d.elapsed }
+
+// Stages returns the per-stage breakdown in the order the stages were recorded
+func (d DeadlineInfo) Stages() []DeadlineStage { return slices.Clone(d.stages) }
+
+// Unaccounted returns the part of the elapsed time not covered by any stage
+func (d DeadlineInfo) Unaccounted() time.Duration {
+	unaccounted := d.elapsed
+	for _, stage := range d.stages {
+		unaccounted -= stage.duration
+	}
+	return max(unaccounted, 0)
+}
+
+func (s DeadlineStage) Name() string            { return s.name }
+func (s DeadlineStage) Duration() time.Duration { return s.duration }
+
+// DeadlineInfo returns the deadline the error missed, nil when none was recorded
+func (e TrogonError) DeadlineInfo() *DeadlineInfo { return e.deadlineInfo }
+
+// WithDeadlineInfo records the original deadline and how long the operation ran before missing it,
+// keeping stages recorded with WithDeadlineStage
+//
+// Example usage:
+//
+//	err := ErrCheckoutTimeout.NewError(
+//	    trogonerror.WithDeadlineInfo(deadline, time.Since(start)),
+//	    trogonerror.WithDeadlineStage("inventory", inventoryTime),
+//	    trogonerror.WithDeadlineStage("payment", paymentTime))
+func WithDeadlineInfo(deadline time.Time, elapsed time.Duration) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		setDeadline(e, deadline, elapsed)
+	})
+}
+
+// WithDeadlineFromContext records the deadline of ctx and the time elapsed since start.
+// It does nothing when ctx has no deadline.
+func WithDeadlineFromContext(ctx context.Context, start time.Time) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if deadline, ok := ctx.Deadline(); ok {
+			setDeadline(e, deadline, now().Sub(start))
+		}
+	})
+}
+
+// WithDeadlineStage appends the time spent in a stage to the deadline breakdown
+func WithDeadlineStage(name string, duration time.Duration) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		addDeadlineStage(e, name, duration)
+	})
+}
+
+// WithChangeDeadlineInfo records the original deadline and elapsed time, keeping the stages
+func WithChangeDeadlineInfo(deadline time.Time, elapsed time.Duration) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		setDeadline(e, deadline, elapsed)
+	})
+}
+
+// WithChangeDeadlineStage appends the time spent in a stage to the deadline breakdown
+func WithChangeDeadlineStage(name string, duration time.Duration) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		addDeadlineStage(e, name, duration)
+	})
+}
+
+// setDeadline replaces the deadline on a copy of the deadline info, which may be shared with other errors
+func setDeadline(e *TrogonError, deadline time.Time, elapsed time.Duration) {
+	var info DeadlineInfo
+	if e.deadlineInfo != nil {
+		info = *e.deadlineInfo
+	}
+	info.deadline = deadline
+	info.elapsed = elapsed
+	e.deadlineInfo = &info
+}
+
+func addDeadlineStage(e *TrogonError, name string, duration time.Duration) {
+	var info DeadlineInfo
+	if e.deadlineInfo != nil {
+		info = *e.deadlineInfo
+	}
+	info.stages = append(slices.Clip(info.stages), DeadlineStage{name: name, duration: duration})
+	e.deadlineInfo = &info
+}
@@ -0,0 +1,93 @@
+package trogonerror_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadlineInfo(t *testing.T) {
+	deadline := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+
+	t.Run("Records the deadline and the stage breakdown", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.checkout", "CHECKOUT_TIMEOUT",
+			trogonerror.WithCode(trogonerror.CodeDeadlineExceeded),
+			trogonerror.WithDeadlineStage("inventory", 300*time.Millisecond),
+			trogonerror.WithDeadlineInfo(deadline, 2*time.Second),
+			trogonerror.WithDeadlineStage("payment", time.Second))
+
+		info := err.DeadlineInfo()
+		assert.Equal(t, deadline, info.Deadline())
+		assert.Equal(t, 2*time.Second, info.Elapsed())
+		assert.Len(t, info.Stages(), 2)
+		assert.Equal(t, "inventory", info.Stages()[0].Name())
+		assert.Equal(t, time.Second, info.Stages()[1].Duration())
+		assert.Equal(t, 700*time.Millisecond, info.Unaccounted())
+	})
+
+	t.Run("WithDeadlineFromContext uses the context deadline", func(t *testing.T) {
+		start := deadline.Add(-5 * time.Second)
+		trogonerror.SetClock(func() time.Time { return deadline.Add(time.Second) })
+		t.Cleanup(func() { trogonerror.SetClock(nil) })
+
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		err := trogonerror.NewError("shopify.checkout", "CHECKOUT_TIMEOUT",
+			trogonerror.WithDeadlineFromContext(ctx, start))
+
+		assert.Equal(t, deadline, err.DeadlineInfo().Deadline())
+		assert.Equal(t, 6*time.Second, err.DeadlineInfo().Elapsed())
+
+		without := trogonerror.NewError("shopify.checkout", "CHECKOUT_TIMEOUT",
+			trogonerror.WithDeadlineFromContext(context.Background(), start))
+		assert.Nil(t, without.DeadlineInfo())
+	})
+
+	t.Run("Changes leave the original untouched", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.checkout", "CHECKOUT_TIMEOUT",
+			trogonerror.WithDeadlineInfo(deadline, time.Second),
+			trogonerror.WithDeadlineStage("inventory", 300*time.Millisecond))
+
+		modified := original.WithChanges(
+			trogonerror.WithChangeDeadlineStage("payment", 500*time.Millisecond),
+			trogonerror.WithChangeDeadlineInfo(deadline, 2*time.Second))
+
+		assert.Len(t, original.DeadlineInfo().Stages(), 1)
+		assert.Equal(t, time.Second, original.DeadlineInfo().Elapsed())
+		assert.Len(t, modified.DeadlineInfo().Stages(), 2)
+		assert.Equal(t, 2*time.Second, modified.DeadlineInfo().Elapsed())
+	})
+
+	t.Run("Serialized only when debug info is allowed", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.checkout", "CHECKOUT_TIMEOUT",
+			trogonerror.WithCode(trogonerror.CodeDeadlineExceeded),
+			trogonerror.WithDeadlineInfo(deadline, 2*time.Second),
+			trogonerror.WithDeadlineStage("payment", 1500*time.Millisecond))
+
+		data, marshalErr := err.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(data), `"deadlineInfo":{"deadline":"2024-01-15T14:30:45Z","elapsed":"2s","stages":[{"name":"payment","duration":"1.5s"}]}`)
+		assert.NoError(t, trogonerror.ValidatePayload(data))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Empty(t, trogonerror.Diff(err, &decoded))
+
+		public, marshalErr := json.Marshal(err)
+		assert.NoError(t, marshalErr)
+		assert.NotContains(t, string(public), "deadlineInfo")
+		assert.Nil(t, trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic).Apply(err).DeadlineInfo())
+	})
+
+	t.Run("Validate rejects negative durations and unnamed stages", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.checkout", "CHECKOUT_TIMEOUT",
+			trogonerror.WithDeadlineInfo(deadline, -time.Second),
+			trogonerror.WithDeadlineStage("", time.Second))
+
+		assert.ErrorIs(t, err.Validate(), trogonerror.ErrInvalidDeadlineInfo)
+	})
+}
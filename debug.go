@@ -0,0 +1,211 @@
+package trogonerror
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// packagePath is the import path of this package, used to skip its own frames when locating callers
+var packagePath = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	return name[:slash+strings.Index(name[slash:], ".")]
+}()
+
+func isPackageFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, packagePath+".")
+}
+
+// WithCallerInfo records only the first frame outside this package (file, line and function)
+// as a one-entry stack trace. It is far cheaper than WithStackTrace when only the origin matters.
+func WithCallerInfo() ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if frame, ok := callerFrame(); ok {
+			setStackFrames(e, []runtime.Frame{frame})
+		}
+	})
+}
+
+// callerFrame returns the first frame outside this package
+func callerFrame() (runtime.Frame, bool) {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !isPackageFrame(frame) {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+func setStackFrames(e *TrogonError, stackFrames []runtime.Frame) {
+	if e.debugInfo == nil {
+		e.debugInfo = &DebugInfo{stackFrames: stackFrames}
+	} else {
+		e.debugInfo.stackFrames = stackFrames
+	}
+}
+
+// DefaultGoroutineDumpLimit bounds the size of the dump captured by WithGoroutineDump
+const DefaultGoroutineDumpLimit = 64 << 10
+
+// WithGoroutineDump snapshots the stacks of all goroutines into debug info, truncated to
+// DefaultGoroutineDumpLimit bytes. Intended for deadlock and timeout class errors (internal use only).
+func WithGoroutineDump() ErrorOption {
+	return WithGoroutineDumpLimit(DefaultGoroutineDumpLimit)
+}
+
+// WithGoroutineDumpLimit snapshots the stacks of all goroutines into debug info, truncated to maxBytes
+func WithGoroutineDumpLimit(maxBytes int) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if maxBytes <= 0 {
+			maxBytes = DefaultGoroutineDumpLimit
+		}
+
+		buf := make([]byte, maxBytes)
+		n := runtime.Stack(buf, true)
+		dump := strings.TrimRight(string(buf[:n]), "\n")
+		if n == len(buf) {
+			dump += "\n… truncated"
+		}
+
+		if e.debugInfo == nil {
+			e.debugInfo = &DebugInfo{}
+		}
+		e.debugInfo.goroutines = dump
+	})
+}
+
+// Goroutines returns the goroutine dump captured by WithGoroutineDump
+func (d DebugInfo) Goroutines() string { return d.goroutines }
+
+// RuntimeInfo is a snapshot of the process environment an error was produced in
+type RuntimeInfo struct {
+	goos       string
+	goarch     string
+	goVersion  string
+	gomaxprocs int
+	env        map[string]string
+}
+
+// WithRuntimeInfo snapshots GOOS/GOARCH, the Go version, GOMAXPROCS and the given allowlisted
+// environment variables into debug info. Unset variables are skipped (internal use only).
+func WithRuntimeInfo(envAllowlist ...string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		info := &RuntimeInfo{
+			goos:       runtime.GOOS,
+			goarch:     runtime.GOARCH,
+			goVersion:  runtime.Version(),
+			gomaxprocs: runtime.GOMAXPROCS(0),
+		}
+		for _, key := range envAllowlist {
+			if value, ok := os.LookupEnv(key); ok {
+				if info.env == nil {
+					info.env = make(map[string]string, len(envAllowlist))
+				}
+				info.env[key] = value
+			}
+		}
+
+		if e.debugInfo == nil {
+			e.debugInfo = &DebugInfo{}
+		}
+		e.debugInfo.runtimeInfo = info
+	})
+}
+
+// RuntimeInfo returns the snapshot captured by WithRuntimeInfo, or nil
+func (d DebugInfo) RuntimeInfo() *RuntimeInfo { return d.runtimeInfo }
+
+func (r RuntimeInfo) GOOS() string      { return r.goos }
+func (r RuntimeInfo) GOARCH() string    { return r.goarch }
+func (r RuntimeInfo) GoVersion() string { return r.goVersion }
+func (r RuntimeInfo) GOMAXPROCS() int   { return r.gomaxprocs }
+
+// Env returns a copy of the captured allowlisted environment variables
+func (r RuntimeInfo) Env() map[string]string { return maps.Clone(r.env) }
+
+// String renders the snapshot on a single line, e.g. "go1.23.0 linux/amd64 GOMAXPROCS=8 REGION=us-east-1"
+func (r RuntimeInfo) String() string {
+	var sb strings.Builder
+	sb.WriteString(r.goVersion)
+	sb.WriteString(" ")
+	sb.WriteString(r.goos)
+	sb.WriteString("/")
+	sb.WriteString(r.goarch)
+	sb.WriteString(" GOMAXPROCS=")
+	sb.WriteString(strconv.Itoa(r.gomaxprocs))
+	for _, key := range slices.Sorted(maps.Keys(r.env)) {
+		sb.WriteString(" ")
+		sb.WriteString(key)
+		sb.WriteString("=")
+		sb.WriteString(r.env[key])
+	}
+	return sb.String()
+}
+
+// WithSourceSnippet reads contextLines lines of source on each side of the caller into debug info,
+// marking the calling line with ">". It does nothing when the source file is not available,
+// so it is only useful in builds running next to their source tree (internal use only).
+func WithSourceSnippet(contextLines int) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		frame, ok := callerFrame()
+		if !ok {
+			return
+		}
+
+		snippet, ok := readSourceSnippet(frame.File, frame.Line, max(contextLines, 0))
+		if !ok {
+			return
+		}
+
+		if e.debugInfo == nil {
+			e.debugInfo = &DebugInfo{}
+		}
+		e.debugInfo.sourceSnippet = snippet
+	})
+}
+
+// SourceSnippet returns the source captured by WithSourceSnippet
+func (d DebugInfo) SourceSnippet() string { return d.sourceSnippet }
+
+func readSourceSnippet(path string, line, contextLines int) (string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	first, last := max(line-contextLines, 1), line+contextLines
+	width := len(strconv.Itoa(last))
+
+	var sb strings.Builder
+	scanner := bufio.NewScanner(file)
+	for current := 1; current <= last && scanner.Scan(); current++ {
+		if current < first {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		marker := " "
+		if current == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&sb, "%s %*d | %s", marker, width, current, scanner.Text())
+	}
+
+	return sb.String(), sb.Len() > 0
+}
@@ -0,0 +1,176 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCallerInfo(t *testing.T) {
+	t.Run("Records the immediate caller only", func(t *testing.T) {
+		_, file, line, _ := runtime.Caller(0)
+		err := trogonerror.NewError("shopify.validation", "INVALID_EMAIL", trogonerror.WithCallerInfo())
+
+		frames := err.DebugInfo().StackFrames()
+		assert.Len(t, frames, 1)
+		assert.Equal(t, file, frames[0].File)
+		assert.Equal(t, line+1, frames[0].Line)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "TestWithCallerInfo.func1"))
+	})
+
+	t.Run("Skips template frames", func(t *testing.T) {
+		template := trogonerror.NewErrorTemplate("shopify.validation", "INVALID_EMAIL")
+
+		err := template.NewError(trogonerror.WithCallerInfo())
+
+		frames := err.DebugInfo().StackFrames()
+		assert.Len(t, frames, 1)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "TestWithCallerInfo.func2"))
+	})
+
+	t.Run("Keeps existing debug detail", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.validation", "INVALID_EMAIL",
+			trogonerror.WithDebugDetail("email failed RFC 5322 validation"),
+			trogonerror.WithCallerInfo())
+
+		assert.Equal(t, "email failed RFC 5322 validation", err.DebugInfo().Detail())
+		assert.Len(t, err.DebugInfo().StackEntries(), 1)
+	})
+}
+
+func BenchmarkCallerInfo(b *testing.B) {
+	b.Run("WithCallerInfo", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = trogonerror.NewError("shopify.validation", "INVALID_EMAIL", trogonerror.WithCallerInfo())
+		}
+	})
+
+	b.Run("WithStackTrace", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = trogonerror.NewError("shopify.validation", "INVALID_EMAIL", trogonerror.WithStackTrace())
+		}
+	})
+}
+
+func TestWithGoroutineDump(t *testing.T) {
+	t.Run("Captures all goroutine stacks", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		go func() { <-block }()
+
+		err := trogonerror.NewError("shopify.queue", "CONSUMER_DEADLOCK",
+			trogonerror.WithCode(trogonerror.CodeDeadlineExceeded),
+			trogonerror.WithGoroutineDump())
+
+		dump := err.DebugInfo().Goroutines()
+		assert.Contains(t, dump, "goroutine ")
+		assert.Contains(t, dump, "TestWithGoroutineDump")
+		assert.Greater(t, strings.Count(dump, "goroutine "), 1)
+		assert.Contains(t, err.Error(), "\n\ngoroutines:\ngoroutine ")
+	})
+
+	t.Run("Truncates to the size limit", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.queue", "CONSUMER_DEADLOCK",
+			trogonerror.WithGoroutineDumpLimit(128))
+
+		dump := err.DebugInfo().Goroutines()
+		assert.LessOrEqual(t, len(dump), 128+len("\n… truncated"))
+		assert.True(t, strings.HasSuffix(dump, "\n… truncated"))
+	})
+
+	t.Run("Keeps stack trace and detail", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.queue", "CONSUMER_DEADLOCK",
+			trogonerror.WithStackTrace(),
+			trogonerror.WithDebugDetail("no progress for 30s"),
+			trogonerror.WithGoroutineDump())
+
+		assert.Equal(t, "no progress for 30s", err.DebugInfo().Detail())
+		assert.NotEmpty(t, err.DebugInfo().StackFrames())
+		assert.NotEmpty(t, err.DebugInfo().Goroutines())
+	})
+
+	t.Run("Survives WithChanges", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.queue", "CONSUMER_DEADLOCK",
+			trogonerror.WithGoroutineDump())
+
+		modified := err.WithChanges(trogonerror.WithChangeID("err_123"))
+
+		assert.Equal(t, err.DebugInfo().Goroutines(), modified.DebugInfo().Goroutines())
+	})
+}
+
+func TestWithRuntimeInfo(t *testing.T) {
+	t.Setenv("DEPLOY_REGION", "us-east-1")
+	t.Setenv("DATABASE_PASSWORD", "hunter2")
+
+	err := trogonerror.NewError("shopify.orders", "ORDER_PROCESSING_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithRuntimeInfo("DEPLOY_REGION", "UNSET_VARIABLE"))
+
+	info := err.DebugInfo().RuntimeInfo()
+	assert.NotNil(t, info)
+	assert.Equal(t, runtime.GOOS, info.GOOS())
+	assert.Equal(t, runtime.GOARCH, info.GOARCH())
+	assert.Equal(t, runtime.Version(), info.GoVersion())
+	assert.Equal(t, runtime.GOMAXPROCS(0), info.GOMAXPROCS())
+	assert.Equal(t, map[string]string{"DEPLOY_REGION": "us-east-1"}, info.Env())
+
+	t.Run("Renders a single line in the text output", func(t *testing.T) {
+		want := "\n\nruntime: " + runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH +
+			" GOMAXPROCS=" + strconv.Itoa(runtime.GOMAXPROCS(0)) + " DEPLOY_REGION=us-east-1"
+		assert.Contains(t, err.Error(), want)
+		assert.NotContains(t, err.Error(), "hunter2")
+	})
+
+	t.Run("Encodes into debugInfo.runtime", func(t *testing.T) {
+		data, marshalErr := err.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, marshalErr)
+
+		var decoded struct {
+			DebugInfo struct {
+				Runtime struct {
+					GOOS       string            `json:"goos"`
+					GOMAXPROCS int               `json:"gomaxprocs"`
+					Env        map[string]string `json:"env"`
+				} `json:"runtime"`
+			} `json:"debugInfo"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, runtime.GOOS, decoded.DebugInfo.Runtime.GOOS)
+		assert.Equal(t, runtime.GOMAXPROCS(0), decoded.DebugInfo.Runtime.GOMAXPROCS)
+		assert.Equal(t, "us-east-1", decoded.DebugInfo.Runtime.Env["DEPLOY_REGION"])
+	})
+
+	t.Run("Env returns a copy", func(t *testing.T) {
+		info.Env()["DEPLOY_REGION"] = "eu-west-1"
+		assert.Equal(t, "us-east-1", info.Env()["DEPLOY_REGION"])
+	})
+}
+
+func TestWithSourceSnippet(t *testing.T) {
+	t.Run("Captures lines around the caller", func(t *testing.T) {
+		_, _, line, _ := runtime.Caller(0)
+		err := trogonerror.NewError("shopify.orders", "ORDER_PROCESSING_FAILED", trogonerror.WithSourceSnippet(1))
+
+		snippet := err.DebugInfo().SourceSnippet()
+		lines := strings.Split(snippet, "\n")
+		assert.Len(t, lines, 3)
+		assert.Equal(t, "  "+strconv.Itoa(line)+" | \t\t_, _, line, _ := runtime.Caller(0)", lines[0])
+		assert.True(t, strings.HasPrefix(lines[1], "> "+strconv.Itoa(line+1)+" | "))
+		assert.Contains(t, lines[1], "WithSourceSnippet(1)")
+		assert.Contains(t, err.Error(), "\n\nsource:\n"+snippet)
+	})
+
+	t.Run("Clamps at the top of the file", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_PROCESSING_FAILED", trogonerror.WithSourceSnippet(100000))
+
+		assert.True(t, strings.HasPrefix(err.DebugInfo().SourceSnippet(), "       1 | package trogonerror_test"))
+	})
+}
@@ -0,0 +1,90 @@
+package trogonerror
+
+import "errors"
+
+// DispatchHandler handles an error selected by a Dispatcher
+type DispatchHandler func(err *TrogonError)
+
+// Dispatcher routes errors to handlers registered per domain and reason, per code, or a default,
+// replacing long switch statements in API gateways. It is immutable once built and safe for concurrent use.
+//
+//	dispatcher := trogonerror.NewDispatcher(
+//	    trogonerror.DispatcherWithTemplate(orders.ErrOrderNotFound, renderNotFound),
+//	    trogonerror.DispatcherWithCode(trogonerror.CodeUnavailable, renderRetryLater),
+//	    trogonerror.DispatcherWithDefault(renderInternal),
+//	)
+//	dispatcher.Dispatch(err)
+type Dispatcher struct {
+	byReason map[ErrorKey]DispatchHandler
+	byCode   map[Code]DispatchHandler
+	fallback func(err error)
+}
+
+// DispatcherOption represents options for dispatcher construction
+type DispatcherOption func(*Dispatcher)
+
+// NewDispatcher creates a dispatcher from its handlers
+func NewDispatcher(options ...DispatcherOption) *Dispatcher {
+	dispatcher := &Dispatcher{
+		byReason: make(map[ErrorKey]DispatchHandler),
+		byCode:   make(map[Code]DispatchHandler),
+	}
+
+	for _, option := range options {
+		option(dispatcher)
+	}
+
+	return dispatcher
+}
+
+// DispatcherWithReason handles errors with the given domain and reason
+func DispatcherWithReason(domain, reason string, handler DispatchHandler) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.byReason[ErrorKey{Domain: domain, Reason: reason}] = handler
+	}
+}
+
+// DispatcherWithTemplate handles errors created from the given template
+func DispatcherWithTemplate(template *ErrorTemplate, handler DispatchHandler) DispatcherOption {
+	return DispatcherWithReason(template.domain, template.reason, handler)
+}
+
+// DispatcherWithCode handles errors with the given code that no reason handler matched
+func DispatcherWithCode(code Code, handler DispatchHandler) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.byCode[code] = handler
+	}
+}
+
+// DispatcherWithDefault handles every other error, including errors without a TrogonError in their chain
+func DispatcherWithDefault(handler func(err error)) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.fallback = handler
+	}
+}
+
+// Dispatch runs the most specific handler for the first TrogonError in err's chain:
+// its domain and reason, then its code, then the default. It reports whether a handler ran.
+func (d *Dispatcher) Dispatch(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var trogonErr *TrogonError
+	if errors.As(err, &trogonErr) && trogonErr != nil {
+		if handler, ok := d.byReason[trogonErr.Key()]; ok {
+			handler(trogonErr)
+			return true
+		}
+		if handler, ok := d.byCode[trogonErr.code]; ok {
+			handler(trogonErr)
+			return true
+		}
+	}
+
+	if d.fallback != nil {
+		d.fallback(err)
+		return true
+	}
+	return false
+}
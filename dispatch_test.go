@@ -0,0 +1,70 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+var errOrderNotFound = trogonerror.NewErrorTemplate("shopify.orders", "ORDER_NOT_FOUND",
+	trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+
+func TestDispatcher(t *testing.T) {
+	var handled string
+	dispatcher := trogonerror.NewDispatcher(
+		trogonerror.DispatcherWithTemplate(errOrderNotFound, func(err *trogonerror.TrogonError) { handled = "order not found" }),
+		trogonerror.DispatcherWithCode(trogonerror.CodeNotFound, func(err *trogonerror.TrogonError) { handled = "not found" }),
+		trogonerror.DispatcherWithCode(trogonerror.CodeUnavailable, func(err *trogonerror.TrogonError) { handled = "unavailable" }),
+		trogonerror.DispatcherWithDefault(func(err error) { handled = fmt.Sprintf("default: %v", err) }))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Domain and reason win over code",
+			err:  errOrderNotFound.NewError(),
+			want: "order not found",
+		},
+		{
+			name: "Code",
+			err:  trogonerror.NewError("shopify.products", "PRODUCT_NOT_FOUND", trogonerror.WithCode(trogonerror.CodeNotFound)),
+			want: "not found",
+		},
+		{
+			name: "Unwraps the chain",
+			err:  fmt.Errorf("checkout: %w", trogonerror.NewError("shopify.inventory", "DOWN", trogonerror.WithCode(trogonerror.CodeUnavailable))),
+			want: "unavailable",
+		},
+		{
+			name: "Default for unmatched TrogonErrors",
+			err:  trogonerror.NewError("shopify.orders", "ORDER_LOCKED", trogonerror.WithCode(trogonerror.CodeAborted)),
+			want: "default: shopify.orders/ORDER_LOCKED (ABORTED): operation aborted",
+		},
+		{
+			name: "Default for other errors",
+			err:  errors.New("connection reset"),
+			want: "default: connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = ""
+
+			assert.True(t, dispatcher.Dispatch(tt.err))
+			assert.Equal(t, tt.want, handled)
+		})
+	}
+
+	t.Run("Without a default", func(t *testing.T) {
+		dispatcher := trogonerror.NewDispatcher()
+
+		assert.False(t, dispatcher.Dispatch(errors.New("connection reset")))
+		assert.False(t, dispatcher.Dispatch(nil))
+	})
+}
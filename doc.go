@@ -165,6 +165,15 @@
 //	fmt.Println(err.Message())                    // "resource not found" (default)
 //	fmt.Println(err.LocalizedMessage().Message()) // "Usuario no encontrado"
 //
+// Attach a whole translation set at once and let MessageFor pick the best match:
+//
+//	err := ErrUserNotFound.NewError(trogonerror.WithLocalizedMessages(map[string]string{
+//		"es": "Usuario no encontrado",
+//		"fr": "Utilisateur introuvable",
+//	}))
+//
+//	fmt.Println(err.MessageFor("fr-CA")) // "Utilisateur introuvable"
+//
 // # Error Mutation with WithChanges
 //
 // Create modified copies of errors efficiently:
@@ -177,6 +186,10 @@
 //		trogonerror.WithChangeSourceID("payment-service"),
 //		trogonerror.WithChangeMetadataValuef(trogonerror.VisibilityPublic, "customerId", "gid://shopify/Customer/%s", userID))
 //
+// Errors are safe to read from many goroutines as long as nobody changes them in place.
+// Freeze an error before sharing it to enforce that: applying options to a frozen error panics,
+// while WithChanges keeps working because it changes a copy.
+//
 // # Standard Go Error Compatibility
 //
 // TrogonError implements the standard Go error interface and works with
@@ -1,11 +1,14 @@
 package trogonerror
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"iter"
 	"maps"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -46,8 +49,10 @@ const (
 
 // HelpLink provides documentation link
 type HelpLink struct {
+	kind        HelpLinkKind
 	description string
 	url         string
+	template    bool
 }
 
 // Help provides links to relevant documentation
@@ -66,8 +71,11 @@ type Metadata = map[string]MetadataValue
 
 // DebugInfo contains technical details for internal debugging
 type DebugInfo struct {
-	stackFrames []runtime.Frame
-	detail      string
+	stackFrames   []runtime.Frame
+	detail        string
+	goroutines    string
+	runtimeInfo   *RuntimeInfo
+	sourceSnippet string
 }
 
 // LocalizedMessage provides translated error message
@@ -77,10 +85,15 @@ type LocalizedMessage struct {
 }
 
 // RetryInfo describes when a client can retry a failed request
-// Following ADR requirements: servers MUST set either retry_offset OR retry_time, never both
+// Following ADR requirements: servers MUST set either retry_offset OR retry_time, never both.
+// It may also carry the full retry contract set with WithRetryBackoff: how many attempts are allowed
+// and how later retries back off from the first wait.
 type RetryInfo struct {
-	retryOffset *time.Duration
-	retryTime   *time.Time
+	retryOffset       *time.Duration
+	retryTime         *time.Time
+	maxAttempts       int
+	backoffMultiplier float64
+	maxDelay          time.Duration
 }
 
 // TrogonError represents the standardized error format following the ADR
@@ -88,6 +101,7 @@ type TrogonError struct {
 	specVersion      int
 	code             Code
 	message          string
+	publicMessage    string
 	domain           string
 	reason           string
 	metadata         Metadata
@@ -99,85 +113,324 @@ type TrogonError struct {
 	help             *Help
 	debugInfo        *DebugInfo
 	localizedMessage *LocalizedMessage
+	translations     []LocalizedMessage
 	retryInfo        *RetryInfo
+	rateLimitInfo    *RateLimitInfo
+	deadlineInfo     *DeadlineInfo
+	authInfo         *AuthInfo
 	sourceID         string
+	owner            string
+	tags             []string
+	operations       []string
 	wrappedErr       error
+	retryable        *bool
+	transient        *bool
+	idempotency      Idempotency
+	httpStatusCode   int
+	grpcCode         Code
+	rendered         string
+	frozen           bool
 }
 
+// Error renders the error with the default Formatter, TextFormatter unless changed with SetDefaultFormatter
 func (e TrogonError) Error() string {
-	sb := &strings.Builder{}
-	sb.WriteString(strings.TrimSpace(e.Message()))
+	if e.rendered != "" {
+		return e.rendered
+	}
+	return e.render()
+}
 
-	fmt.Fprintf(sb, "\n  visibility: %s", e.visibility.String())
-	fmt.Fprintf(sb, "\n  domain: %s", e.domain)
-	fmt.Fprintf(sb, "\n  reason: %s", e.reason)
-	fmt.Fprintf(sb, "\n  code: %s", e.code.String())
+func (e TrogonError) render() string {
+	return defaultFormatter().Format(&e)
+}
+
+// Freeze marks the error as shared and precomputes the Error() string so later calls return it
+// without rendering again. The string is rendered with the Formatter configured at the time of the call,
+// and cannot go stale since no option can change a frozen error.
+//
+// A *TrogonError is safe for concurrent reads; all changes must go through WithChanges, which returns
+// a copy and leaves the original untouched. Every ErrorOption and ChangeOption panics when applied
+// directly to a frozen error, catching in-place mutation of errors other goroutines may be reading.
+// Copies made by WithChanges and Clone are not frozen. Call Freeze before sharing the error, since it
+// mutates the receiver.
+func (e *TrogonError) Freeze() *TrogonError {
+	e.rendered = ""
+	e.rendered = e.Error()
+	e.frozen = true
+	return e
+}
+
+// IsFrozen reports whether Freeze was called on the error
+func (e TrogonError) IsFrozen() bool { return e.frozen }
+
+// mutable builds an ErrorOption or ChangeOption that panics when applied in place to a frozen error.
+// Every option of the package is built with it, so copy-on-write is enforced for all fields at once
+// and the Error() string cached by Freeze cannot go stale.
+func mutable(option func(*TrogonError)) func(*TrogonError) {
+	return func(e *TrogonError) {
+		if e.frozen {
+			panic("trogonerror: cannot modify frozen error " + e.domain + "." + e.reason + " in place, use WithChanges")
+		}
+		option(e)
+	}
+}
+
+func (e TrogonError) text() string {
+	return e.textWithin(nil)
+}
+
+// textWithin renders the text layout, eliding the trailing stack frames shared with the enclosing error's stack
+func (e TrogonError) textWithin(enclosing []runtime.Frame) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteString(strings.TrimSpace(e.Message()))
+
+	if e.publicMessage != "" {
+		writeField(buf, "publicMessage", e.publicMessage)
+	}
+
+	writeField(buf, "visibility", e.visibility.String())
+	writeField(buf, "domain", e.domain)
+	writeField(buf, "reason", e.reason)
+	writeField(buf, "code", e.code.String())
 
 	if e.id != "" {
-		fmt.Fprintf(sb, "\n  id: %s", e.id)
+		writeField(buf, "id", e.id)
 	}
 
 	if e.time != nil {
-		fmt.Fprintf(sb, "\n  time: %s", e.time.Format(time.RFC3339))
+		buf.WriteString("\n  time: ")
+		buf.Write(e.time.AppendFormat(buf.AvailableBuffer(), time.RFC3339))
 	}
 
 	if e.subject != "" {
-		fmt.Fprintf(sb, "\n  subject: %s", e.subject)
+		writeField(buf, "subject", e.subject)
 	}
 
 	if e.sourceID != "" {
-		fmt.Fprintf(sb, "\n  sourceId: %s", e.sourceID)
+		writeField(buf, "sourceId", e.sourceID)
+	}
+
+	if e.owner != "" {
+		writeField(buf, "owner", e.owner)
+	}
+
+	if e.transient != nil {
+		writeField(buf, "transient", strconv.FormatBool(*e.transient))
+	}
+
+	if e.idempotency != IdempotencyUnspecified {
+		writeField(buf, "idempotency", e.idempotency.String())
+	}
+
+	if len(e.tags) > 0 {
+		writeField(buf, "tags", strings.Join(e.tags, ", "))
+	}
+
+	if operations := e.Operations(); len(operations) > 0 {
+		writeField(buf, "operations", strings.Join(operations, " > "))
 	}
 
 	if e.retryInfo != nil {
-		var retryStr string
+		buf.WriteString("\n  retryInfo: ")
 		if e.retryInfo.retryOffset != nil {
-			retryStr = fmt.Sprintf("retryOffset=%s", e.retryInfo.retryOffset.String())
+			buf.WriteString("retryOffset=")
+			buf.WriteString(e.retryInfo.retryOffset.String())
 		} else if e.retryInfo.retryTime != nil {
-			retryStr = fmt.Sprintf("retryTime=%s", e.retryInfo.retryTime.Format(time.RFC3339))
+			buf.WriteString("retryTime=")
+			buf.Write(e.retryInfo.retryTime.AppendFormat(buf.AvailableBuffer(), time.RFC3339))
+		}
+	}
+
+	if e.rateLimitInfo != nil {
+		buf.WriteString("\n  rateLimitInfo: limit=")
+		buf.WriteString(strconv.Itoa(e.rateLimitInfo.limit))
+		buf.WriteString(" remaining=")
+		buf.WriteString(strconv.Itoa(e.rateLimitInfo.remaining))
+		if e.rateLimitInfo.window != 0 {
+			buf.WriteString(" window=")
+			buf.WriteString(e.rateLimitInfo.window.String())
+		}
+		if e.rateLimitInfo.resetTime != nil {
+			buf.WriteString(" resetTime=")
+			buf.Write(e.rateLimitInfo.resetTime.AppendFormat(buf.AvailableBuffer(), time.RFC3339))
 		}
+	}
 
-		fmt.Fprintf(sb, "\n  retryInfo: %s", retryStr)
+	if e.deadlineInfo != nil {
+		buf.WriteString("\n  deadlineInfo: deadline=")
+		buf.Write(e.deadlineInfo.deadline.AppendFormat(buf.AvailableBuffer(), time.RFC3339))
+		buf.WriteString(" elapsed=")
+		buf.WriteString(e.deadlineInfo.elapsed.String())
+		for _, stage := range e.deadlineInfo.stages {
+			buf.WriteString("\n    ")
+			buf.WriteString(stage.name)
+			buf.WriteString(": ")
+			buf.WriteString(stage.duration.String())
+		}
+	}
+
+	if e.authInfo != nil {
+		buf.WriteString("\n  authInfo:")
+		if e.authInfo.scheme != "" {
+			buf.WriteString(" scheme=")
+			buf.WriteString(e.authInfo.scheme)
+		}
+		if len(e.authInfo.requiredScopes) > 0 {
+			buf.WriteString(" requiredScopes=")
+			buf.WriteString(strings.Join(e.authInfo.requiredScopes, ","))
+		}
+		if len(e.authInfo.requiredRoles) > 0 {
+			buf.WriteString(" requiredRoles=")
+			buf.WriteString(strings.Join(e.authInfo.requiredRoles, ","))
+		}
+		if e.authInfo.principalClass != "" {
+			buf.WriteString(" principalClass=")
+			buf.WriteString(e.authInfo.principalClass)
+		}
 	}
 
 	if len(e.metadata) > 0 {
-		sb.WriteString("\n  metadata:")
+		buf.WriteString("\n  metadata:")
 
 		for _, k := range slices.Sorted(maps.Keys(e.metadata)) {
 			v := e.metadata[k]
-			fmt.Fprintf(sb, "\n    - %s: %s visibility=%s", k, v.value, v.visibility.String())
+			buf.WriteString("\n    - ")
+			buf.WriteString(k)
+			buf.WriteString(": ")
+			buf.WriteString(RedactMetadataValue(k, v.value))
+			buf.WriteString(" visibility=")
+			buf.WriteString(v.visibility.String())
 		}
 	}
 
-	if e.help != nil && len(e.help.links) > 0 {
-		sb.WriteString("\n\n")
-		for i, link := range e.help.links {
-			if i > 0 {
-				sb.WriteString("\n")
+	if len(e.causes) > 0 {
+		writeCauses(buf, e.causes, "  ", 1)
+	}
+
+	if help := e.Help(); help != nil && len(help.links) > 0 {
+		buf.WriteString("\n")
+		for _, link := range help.links {
+			buf.WriteString("\n- ")
+			if link.kind != HelpLinkDocumentation {
+				buf.WriteString("[")
+				buf.WriteString(strings.ToLower(link.kind.String()))
+				buf.WriteString("] ")
 			}
-			fmt.Fprintf(sb, "- %s: %s", link.description, link.url)
+			buf.WriteString(link.description)
+			buf.WriteString(": ")
+			buf.WriteString(link.url)
 		}
 	}
 
 	if e.wrappedErr != nil {
-		sb.WriteString("\n\nwrapped error: ")
-		sb.WriteString(e.wrappedErr.Error())
+		buf.WriteString("\n\nwrapped error: ")
+		if inner, ok := e.wrappedErr.(*TrogonError); ok && inner != nil {
+			buf.WriteString(inner.textWithin(e.stackFrames()))
+		} else {
+			buf.WriteString(e.wrappedErr.Error())
+		}
 	}
 
 	if e.debugInfo != nil {
-		sb.WriteString("\n")
+		buf.WriteString("\n")
 		if e.debugInfo.detail != "" {
-			sb.WriteString("\n")
-			sb.WriteString(e.debugInfo.detail)
+			buf.WriteString("\n")
+			buf.WriteString(e.debugInfo.detail)
+		}
+
+		common := commonFrameSuffix(e.debugInfo.stackFrames, enclosing)
+		for _, frame := range e.debugInfo.stackFrames[:len(e.debugInfo.stackFrames)-common] {
+			buf.WriteString("\n")
+			writeFrame(buf, frame)
+		}
+		if common > 0 {
+			buf.WriteString("\n… ")
+			buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(common), 10))
+			buf.WriteString(" frames in common …")
 		}
 
-		for _, entry := range e.debugInfo.StackEntries() {
-			sb.WriteString("\n")
-			sb.WriteString(entry)
+		if e.debugInfo.sourceSnippet != "" {
+			buf.WriteString("\n\nsource:\n")
+			buf.WriteString(e.debugInfo.sourceSnippet)
+		}
+
+		if e.debugInfo.runtimeInfo != nil {
+			buf.WriteString("\n\nruntime: ")
+			buf.WriteString(e.debugInfo.runtimeInfo.String())
+		}
+
+		if e.debugInfo.goroutines != "" {
+			buf.WriteString("\n\ngoroutines:\n")
+			buf.WriteString(e.debugInfo.goroutines)
+		}
+	}
+
+	return buf.String()
+}
+
+func (e TrogonError) stackFrames() []runtime.Frame {
+	if e.debugInfo == nil {
+		return nil
+	}
+	return e.debugInfo.stackFrames
+}
+
+// commonFrameSuffix counts the trailing frames two stacks share, i.e. the callers both errors were created under
+func commonFrameSuffix(frames, enclosing []runtime.Frame) int {
+	common := 0
+	for common < len(frames) && common < len(enclosing) {
+		a := frames[len(frames)-1-common]
+		b := enclosing[len(enclosing)-1-common]
+		if a.Function != b.Function || a.File != b.File || a.Line != b.Line {
+			break
 		}
+		common++
 	}
+	return common
+}
+
+func writeField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString("\n  ")
+	buf.WriteString(name)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+}
+
+func writeFrame(buf *bytes.Buffer, frame runtime.Frame) {
+	buf.WriteString(frame.File)
+	buf.WriteByte(':')
+	buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(frame.Line), 10))
+	buf.WriteByte(' ')
+	buf.WriteString(frame.Function)
+}
+
+// maxRenderedCauseDepth limits how many levels of nested causes the text layout renders
+const maxRenderedCauseDepth = 5
 
-	return sb.String()
+func writeCauses(buf *bytes.Buffer, causes []*TrogonError, indent string, depth int) {
+	buf.WriteString("\n")
+	buf.WriteString(indent)
+	buf.WriteString("causes:")
+	for _, cause := range causes {
+		buf.WriteString("\n")
+		buf.WriteString(indent)
+		buf.WriteString("  - ")
+		buf.WriteString(cause.summary())
+		if len(cause.causes) == 0 {
+			continue
+		}
+		if depth >= maxRenderedCauseDepth {
+			buf.WriteString("\n")
+			buf.WriteString(indent)
+			buf.WriteString("    causes: ")
+			buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(len(cause.causes)), 10))
+			buf.WriteString(" omitted")
+			continue
+		}
+		writeCauses(buf, cause.causes, indent+"    ", depth+1)
+	}
 }
 
 func (e TrogonError) Is(target error) bool {
@@ -234,7 +487,18 @@ func (c Code) Message() string {
 	}
 }
 
+// HttpStatusCode returns the HTTP status for the code, from the table set with SetHttpStatusMapping
+// when it maps the code, and the package default otherwise
 func (c Code) HttpStatusCode() int {
+	if mapping := httpStatusMapping.Load(); mapping != nil {
+		if status, ok := (*mapping)[c]; ok {
+			return status
+		}
+	}
+	return c.defaultHttpStatusCode()
+}
+
+func (c Code) defaultHttpStatusCode() int {
 	switch c {
 	case CodeCancelled:
 		return 499
@@ -331,6 +595,9 @@ type ErrorOption func(*TrogonError)
 // NewError creates a new TrogonError following the ADR specification.
 // Domain should be a simple identifier like "myapp.users" (not reversed-DNS).
 // Reason should be an UPPERCASE identifier like "NOT_FOUND".
+//
+// Metadata and causes are allocated lazily, so an error without options costs a single allocation
+// and scalar options such as WithCode or WithMessage add none beyond their own closures.
 func NewError(domain, reason string, options ...ErrorOption) *TrogonError {
 	err := &TrogonError{
 		specVersion: SpecVersion,
@@ -338,117 +605,151 @@ func NewError(domain, reason string, options ...ErrorOption) *TrogonError {
 		message:     "", // empty string means use code's default message
 		domain:      domain,
 		reason:      reason,
-		metadata:    make(Metadata),
-		causes:      make([]*TrogonError, 0),
 		visibility:  VisibilityInternal,
 	}
 
 	for _, option := range options {
 		option(err)
 	}
+	applyDefaults(err)
 
 	return err
 }
 
+// applyDefaults fills in the process-wide defaults once all options have been applied
+func applyDefaults(err *TrogonError) {
+	if err.time == nil && autoTime.Load() {
+		timestamp := now()
+		err.time = &timestamp
+	}
+	applyDefaultSourceID(err)
+	applyRequestIDVisibility(err)
+	applyRedactionOnCreation(err)
+	applyMetadataLimits(err)
+	runCreateHooks(err)
+}
+
 // WithCode sets the error code
 func WithCode(code Code) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.code = code
-	}
+	})
 }
 
 // WithMessage sets the error message
 func WithMessage(message string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.message = message
-	}
+	})
 }
 
-// WithMetadata sets metadata with explicit visibility control
+// WithMessagef sets the error message formatted according to a format specifier
+func WithMessagef(format string, args ...any) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.message = fmt.Sprintf(format, args...)
+	})
+}
+
+// WithMetadata sets metadata with explicit visibility control.
+// Keys in the reserved "trogon." namespace are ignored.
 func WithMetadata(metadata map[string]MetadataValue) ErrorOption {
-	return func(e *TrogonError) {
-		maps.Copy(e.metadata, metadata)
-	}
+	return mutable(func(e *TrogonError) {
+		if len(metadata) == 0 {
+			return
+		}
+		if e.metadata == nil {
+			e.metadata = make(Metadata, len(metadata))
+		}
+		copyUserMetadata(e.metadata, metadata)
+	})
 }
 
 // WithMetadataValue sets a single metadata entry with specific visibility
 func WithMetadataValue(visibility Visibility, key, value string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addMetadataValue(e, visibility, key, value)
-	}
+	})
 }
 
 // WithMetadataValuef sets a single metadata entry with printf-style formatting for the value
 // Example: WithMetadataValuef(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/%s", orderID)
 func WithMetadataValuef(visibility Visibility, key, valueFormat string, args ...any) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addMetadataValue(e, visibility, key, fmt.Sprintf(valueFormat, args...))
-	}
+	})
 }
 
 // WithVisibility sets the error visibility
 func WithVisibility(visibility Visibility) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.visibility = visibility
-	}
+	})
 }
 
 // WithSubject sets the error subject
 func WithSubject(subject string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.subject = subject
-	}
+	})
 }
 
 // WithID sets the error ID
 func WithID(id string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.id = id
-	}
+	})
 }
 
 // WithTime sets the error timestamp
 func WithTime(timestamp time.Time) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.time = &timestamp
-	}
+	})
 }
 
 // WithSourceID sets the source ID
 func WithSourceID(sourceID string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.sourceID = sourceID
-	}
+	})
+}
+
+// WithOwner sets the team owning the error, e.g. "team-payments", so on-call tooling
+// can route it without deriving the team from the domain
+func WithOwner(owner string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.owner = owner
+	})
 }
 
 // WithHelp sets the help information
 func WithHelp(help Help) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.help = &help
-	}
+	})
 }
 
 // WithHelpLink adds a help link with a static URL.
 // Use WithHelpLinkf for URLs that need parameter interpolation.
 func WithHelpLink(description, url string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addHelpLink(e, description, url)
-	}
+	})
 }
 
 // WithHelpLinkf adds a help link with printf-style formatting for the URL.
 // Example: WithHelpLinkf("User Console", "https://console.myapp.com/users/%s", userID)
 func WithHelpLinkf(description, urlFormat string, args ...any) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addHelpLink(e, description, fmt.Sprintf(urlFormat, args...))
-	}
+	})
 }
 
 // WithDebugInfo sets debug information (for internal use only)
 func WithDebugInfo(debugInfo DebugInfo) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.debugInfo = &debugInfo
-	}
+	})
 }
 
 // WithStackTrace annotates the error with a stack trace at the point WithStackTrace was called
@@ -459,27 +760,20 @@ func WithStackTrace() ErrorOption {
 
 // WithDebugDetail sets debug detail message without capturing stack trace
 func WithDebugDetail(detail string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		if e.debugInfo == nil {
 			e.debugInfo = &DebugInfo{detail: detail}
 		} else {
 			e.debugInfo.detail = detail
 		}
-	}
+	})
 }
 
 // WithStackTraceDepth annotates the error with a stack trace up to the specified depth
 func WithStackTraceDepth(maxDepth int) ErrorOption {
-	return func(e *TrogonError) {
-		stackFrames := captureStackTrace(2, maxDepth) // Skip WithStackTraceDepth and the calling ErrorOption wrapper
-		if e.debugInfo == nil {
-			e.debugInfo = &DebugInfo{
-				stackFrames: stackFrames,
-			}
-		} else {
-			e.debugInfo.stackFrames = stackFrames
-		}
-	}
+	return mutable(func(e *TrogonError) {
+		setStackFrames(e, captureStackTrace(3, maxDepth)) // Skip captureStackTrace and the option closure called by mutable
+	})
 }
 
 // captureStackTrace captures the current call stack up to maxDepth frames
@@ -491,72 +785,69 @@ func captureStackTrace(skip, maxDepth int) []runtime.Frame {
 	var pcs = make([]uintptr, maxDepth)
 	n := runtime.Callers(skip, pcs[:])
 
-	frames := runtime.CallersFrames(pcs[:n])
-	var stackFrames []runtime.Frame
-
-	for {
-		frame, more := frames.Next()
-		stackFrames = append(stackFrames, frame)
-
-		if !more {
-			break
-		}
-	}
-
-	return stackFrames
+	return framesFromPCs(pcs[:n])
 }
 
 // WithLocalizedMessage sets localized message
 func WithLocalizedMessage(locale, message string) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.localizedMessage = &LocalizedMessage{
 			locale:  locale,
 			message: message,
 		}
-	}
+	})
 }
 
 // WithRetryInfoDuration sets retry information with a duration offset
 // Following ADR: servers MUST set either retry_offset OR retry_time, never both
 func WithRetryInfoDuration(retryOffset time.Duration) ErrorOption {
-	return func(e *TrogonError) {
-		e.retryInfo = &RetryInfo{
-			retryOffset: &retryOffset,
-			retryTime:   nil, // Explicitly ensure only one is set per ADR
-		}
-	}
+	return mutable(func(e *TrogonError) {
+		setRetryWait(e, &retryOffset, nil)
+	})
 }
 
 // WithRetryTime sets retry information with an absolute time
 // Following ADR: servers MUST set either retry_offset OR retry_time, never both
 func WithRetryTime(retryTime time.Time) ErrorOption {
-	return func(e *TrogonError) {
-		e.retryInfo = &RetryInfo{
-			retryOffset: nil, // Explicitly ensure only one is set per ADR
-			retryTime:   &retryTime,
-		}
-	}
+	return mutable(func(e *TrogonError) {
+		setRetryWait(e, nil, &retryTime)
+	})
 }
 
 // WithCause adds one or more causes to the error
 func WithCause(causes ...*TrogonError) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.causes = append(e.causes, causes...)
-	}
+	})
 }
 
 // WithErrorMessage sets the error message to the error's Error() string
 func WithErrorMessage(err error) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.message = err.Error()
-	}
+	})
 }
 
 // WithWrap wraps an existing error
 func WithWrap(err error) ErrorOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.wrappedErr = err
-	}
+	})
+}
+
+// Chain creates an error from template for a failure caused by err: the new error wraps err,
+// adopts its stack trace if it carries one and records it as a cause with WithCauseFromError.
+// Options are applied afterwards and can override any of these.
+//
+//	if err := db.Insert(ctx, order); err != nil {
+//	    return trogonerror.Chain(ErrOrderNotSaved, err, trogonerror.WithSubject("/order"))
+//	}
+func Chain(template *ErrorTemplate, err error, options ...ErrorOption) *TrogonError {
+	return template.NewError(append([]ErrorOption{
+		WithWrap(err),
+		WithStackTraceFrom(err),
+		WithCauseFromError(err),
+	}, options...)...)
 }
 
 func (e *TrogonError) copy() *TrogonError {
@@ -564,6 +855,7 @@ func (e *TrogonError) copy() *TrogonError {
 		specVersion:      e.specVersion,
 		code:             e.code,
 		message:          e.message,
+		publicMessage:    e.publicMessage,
 		domain:           e.domain,
 		reason:           e.reason,
 		visibility:       e.visibility,
@@ -571,9 +863,21 @@ func (e *TrogonError) copy() *TrogonError {
 		id:               e.id,
 		time:             e.time,
 		sourceID:         e.sourceID,
+		owner:            e.owner,
+		tags:             e.tags,
+		operations:       e.operations,
 		retryInfo:        e.retryInfo,
+		rateLimitInfo:    e.rateLimitInfo,
+		deadlineInfo:     e.deadlineInfo,
+		authInfo:         e.authInfo,
 		localizedMessage: e.localizedMessage,
+		translations:     e.translations,
 		wrappedErr:       e.wrappedErr,
+		retryable:        e.retryable,
+		transient:        e.transient,
+		idempotency:      e.idempotency,
+		httpStatusCode:   e.httpStatusCode,
+		grpcCode:         e.grpcCode,
 	}
 
 	if len(e.metadata) > 0 {
@@ -600,6 +904,61 @@ func (e *TrogonError) copy() *TrogonError {
 	return clonedErr
 }
 
+// Clone returns a deep copy of the error, including its causes and a wrapped TrogonError,
+// so the copy can be changed for a different audience without affecting the original.
+// Wrapped errors of other types are shared, as they cannot be copied generically.
+func (e *TrogonError) Clone() *TrogonError {
+	if e == nil {
+		return nil
+	}
+
+	cloned := e.copy()
+	for i, cause := range cloned.causes {
+		cloned.causes[i] = cause.Clone()
+	}
+	if wrapped, ok := e.wrappedErr.(*TrogonError); ok {
+		cloned.wrappedErr = wrapped.Clone()
+	}
+	if e.time != nil {
+		timestamp := *e.time
+		cloned.time = &timestamp
+	}
+	if e.retryInfo != nil {
+		retryInfo := *e.retryInfo
+		cloned.retryInfo = &retryInfo
+	}
+	if e.rateLimitInfo != nil {
+		rateLimitInfo := *e.rateLimitInfo
+		cloned.rateLimitInfo = &rateLimitInfo
+	}
+	if e.deadlineInfo != nil {
+		deadlineInfo := *e.deadlineInfo
+		deadlineInfo.stages = slices.Clone(deadlineInfo.stages)
+		cloned.deadlineInfo = &deadlineInfo
+	}
+	if e.authInfo != nil {
+		authInfo := *e.authInfo
+		authInfo.requiredScopes = slices.Clone(authInfo.requiredScopes)
+		authInfo.requiredRoles = slices.Clone(authInfo.requiredRoles)
+		cloned.authInfo = &authInfo
+	}
+	if e.localizedMessage != nil {
+		localizedMessage := *e.localizedMessage
+		cloned.localizedMessage = &localizedMessage
+	}
+	cloned.translations = slices.Clone(e.translations)
+	if e.retryable != nil {
+		retryable := *e.retryable
+		cloned.retryable = &retryable
+	}
+	if e.transient != nil {
+		transient := *e.transient
+		cloned.transient = &transient
+	}
+
+	return cloned
+}
+
 // ChangeOption represents a change to apply to a TrogonError
 type ChangeOption func(*TrogonError)
 
@@ -609,97 +968,109 @@ func (e *TrogonError) WithChanges(changes ...ChangeOption) *TrogonError {
 	for _, change := range changes {
 		change(clonedErr)
 	}
+	applyRedactionOnCreation(clonedErr)
+	applyMetadataLimits(clonedErr)
 	return clonedErr
 }
 
 // Change options for error mutation
 
-// WithChangeMetadata sets metadata with explicit visibility control
+// WithChangeMetadata replaces metadata with explicit visibility control.
+// Entries in the reserved "trogon." namespace are kept and cannot be replaced.
 func WithChangeMetadata(metadata map[string]MetadataValue) ChangeOption {
-	return func(e *TrogonError) {
-		e.metadata = make(Metadata)
-		maps.Copy(e.metadata, metadata)
-	}
+	return mutable(func(e *TrogonError) {
+		replaced := make(Metadata, len(metadata))
+		for key, value := range e.metadata {
+			if IsReservedMetadataKey(key) {
+				replaced[key] = value
+			}
+		}
+		copyUserMetadata(replaced, metadata)
+		e.metadata = replaced
+	})
 }
 
 // WithChangeMetadataValue sets a single metadata entry with specific visibility
 func WithChangeMetadataValue(visibility Visibility, key, value string) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addMetadataValue(e, visibility, key, value)
-	}
+	})
 }
 
 // WithChangeMetadataValuef sets a single metadata entry with printf-style formatting for the value
 // Example: WithChangeMetadataValuef(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/%s", orderID)
 func WithChangeMetadataValuef(visibility Visibility, key, valueFormat string, args ...any) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addMetadataValue(e, visibility, key, fmt.Sprintf(valueFormat, args...))
-	}
+	})
 }
 
 // WithChangeID sets the error ID
 func WithChangeID(id string) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.id = id
-	}
+	})
 }
 
 // WithChangeTime sets the timestamp
 func WithChangeTime(timestamp time.Time) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.time = &timestamp
-	}
+	})
+}
+
+// WithChangeOwner sets the owning team
+func WithChangeOwner(owner string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.owner = owner
+	})
 }
 
 // WithChangeSourceID sets the source ID
 func WithChangeSourceID(sourceID string) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.sourceID = sourceID
-	}
+	})
 }
 
 // WithChangeHelpLink adds a help link with a static URL (appends to existing help).
 // Use WithChangeHelpLinkf for URLs that need parameter interpolation.
 func WithChangeHelpLink(description, url string) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addHelpLink(e, description, url)
-	}
+	})
 }
 
 // WithChangeHelpLinkf adds a help link with printf-style formatting for the URL (appends to existing help).
 // Example: WithChangeHelpLinkf("Order Details", "https://console.myapp.com/orders/%s", orderID)
 func WithChangeHelpLinkf(description, urlFormat string, args ...any) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		addHelpLink(e, description, fmt.Sprintf(urlFormat, args...))
-	}
+	})
 }
 
-// WithChangeRetryInfoDuration sets retry duration (replaces existing retry info)
+// WithChangeRetryInfoDuration sets retry duration (replaces the existing retry offset or time)
 func WithChangeRetryInfoDuration(retryOffset time.Duration) ChangeOption {
-	return func(e *TrogonError) {
-		e.retryInfo = &RetryInfo{
-			retryOffset: &retryOffset,
-		}
-	}
+	return mutable(func(e *TrogonError) {
+		setRetryWait(e, &retryOffset, nil)
+	})
 }
 
-// WithChangeRetryTime sets absolute retry time (replaces existing retry info)
+// WithChangeRetryTime sets absolute retry time (replaces the existing retry offset or time)
 func WithChangeRetryTime(retryTime time.Time) ChangeOption {
-	return func(e *TrogonError) {
-		e.retryInfo = &RetryInfo{
-			retryTime: &retryTime,
-		}
-	}
+	return mutable(func(e *TrogonError) {
+		setRetryWait(e, nil, &retryTime)
+	})
 }
 
 // WithChangeLocalizedMessage sets localized message (replaces existing)
 func WithChangeLocalizedMessage(locale, message string) ChangeOption {
-	return func(e *TrogonError) {
+	return mutable(func(e *TrogonError) {
 		e.localizedMessage = &LocalizedMessage{
 			locale:  locale,
 			message: message,
 		}
-	}
+	})
 }
 
 func (e TrogonError) SpecVersion() int { return e.specVersion }
@@ -712,17 +1083,30 @@ func (e TrogonError) Message() string {
 }
 func (e TrogonError) Domain() string                      { return e.domain }
 func (e TrogonError) Reason() string                      { return e.reason }
-func (e TrogonError) Metadata() Metadata                  { return e.metadata }
-func (e TrogonError) Causes() []*TrogonError              { return e.causes }
+func (e TrogonError) Causes() []*TrogonError              { return slices.Clone(e.causes) }
 func (e TrogonError) Visibility() Visibility              { return e.visibility }
 func (e TrogonError) Subject() string                     { return e.subject }
 func (e TrogonError) ID() string                          { return e.id }
 func (e TrogonError) Time() *time.Time                    { return e.time }
-func (e TrogonError) Help() *Help                         { return e.help }
+func (e TrogonError) Help() *Help                         { return e.resolvedHelp() }
 func (e TrogonError) DebugInfo() *DebugInfo               { return e.debugInfo }
 func (e TrogonError) LocalizedMessage() *LocalizedMessage { return e.localizedMessage }
 func (e TrogonError) RetryInfo() *RetryInfo               { return e.retryInfo }
 func (e TrogonError) SourceID() string                    { return e.sourceID }
+func (e TrogonError) Owner() string                       { return e.owner }
+
+// Metadata returns a copy of the metadata, so changing it never affects the error.
+// Use AllMetadata or LookupMetadata to read without copying.
+func (e TrogonError) Metadata() Metadata { return maps.Clone(e.metadata) }
+
+// AllMetadata iterates over the metadata entries in unspecified order without copying them
+func (e TrogonError) AllMetadata() iter.Seq2[string, MetadataValue] { return maps.All(e.metadata) }
+
+// LookupMetadata returns the metadata entry for key without copying the metadata
+func (e TrogonError) LookupMetadata(key string) (MetadataValue, bool) {
+	value, ok := e.metadata[key]
+	return value, ok
+}
 
 func (m MetadataValue) Value() string          { return m.value }
 func (m MetadataValue) Visibility() Visibility { return m.visibility }
@@ -740,18 +1124,13 @@ func (h Help) copy() Help {
 	return Help{links: copiedLinks}
 }
 
-func (h Help) Links() []HelpLink { return h.links }
+// Links returns a copy of the help links, so callers cannot alter links shared with a template
+func (h Help) Links() []HelpLink { return slices.Clone(h.links) }
 
 func (d DebugInfo) copy() DebugInfo {
-	if len(d.stackFrames) == 0 {
-		return DebugInfo{detail: d.detail}
-	}
-	copiedStackFrames := make([]runtime.Frame, len(d.stackFrames))
-	copy(copiedStackFrames, d.stackFrames)
-	return DebugInfo{
-		stackFrames: copiedStackFrames,
-		detail:      d.detail,
-	}
+	// Strings and the runtime snapshot are immutable, so only the frame slice needs a fresh backing array
+	d.stackFrames = slices.Clone(d.stackFrames)
+	return d
 }
 
 // StackEntries converts the runtime.Frame objects to formatted strings
@@ -786,15 +1165,27 @@ func (l LocalizedMessage) Message() string { return l.message }
 
 func (r RetryInfo) RetryOffset() *time.Duration { return r.retryOffset }
 func (r RetryInfo) RetryTime() *time.Time       { return r.retryTime }
-
-// ErrorTemplate represents a reusable error definition
+func (r RetryInfo) MaxAttempts() int            { return r.maxAttempts }
+func (r RetryInfo) BackoffMultiplier() float64  { return r.backoffMultiplier }
+func (r RetryInfo) MaxDelay() time.Duration     { return r.maxDelay }
+
+// ErrorTemplate represents a reusable error definition.
+// Templates are immutable once NewErrorTemplate returns: no method changes them, their accessors
+// return copies, and every instance gets its own copy of the help links and tags before options apply.
+// A template is therefore safe to share as a package-level variable and to use from any number of goroutines.
 type ErrorTemplate struct {
-	domain     string
-	reason     string
-	code       Code
-	message    string // empty string means use code's default message
-	visibility Visibility
-	help       *Help
+	domain        string
+	reason        string
+	code          Code
+	message       string // empty string means use code's default message
+	visibility    Visibility
+	help          *Help
+	publicMessage string
+	owner         string
+	idempotency   Idempotency
+	tags          []string
+	translations  []LocalizedMessage
+	prototype     *TrogonError
 }
 
 // TemplateOption represents options that can be applied to ErrorTemplate
@@ -813,10 +1204,33 @@ func NewErrorTemplate(domain, reason string, options ...TemplateOption) *ErrorTe
 	for _, option := range options {
 		option(template)
 	}
+	template.prototype = template.bake()
 
 	return template
 }
 
+// bake builds the immutable prototype every instance is copied from
+func (et *ErrorTemplate) bake() *TrogonError {
+	prototype := &TrogonError{
+		specVersion:   SpecVersion,
+		code:          et.code,
+		message:       et.message,
+		domain:        et.domain,
+		reason:        et.reason,
+		visibility:    et.visibility,
+		publicMessage: et.publicMessage,
+		owner:         et.owner,
+		idempotency:   et.idempotency,
+		tags:          slices.Clip(et.tags),
+		translations:  slices.Clip(et.translations),
+	}
+	if et.help != nil {
+		// Clipping guarantees that appending links to an instance reallocates instead of writing into the prototype
+		prototype.help = &Help{links: slices.Clip(slices.Clone(et.help.links))}
+	}
+	return prototype
+}
+
 // Template option functions
 func TemplateWithCode(code Code) TemplateOption {
 	return func(t *ErrorTemplate) {
@@ -836,38 +1250,94 @@ func TemplateWithVisibility(visibility Visibility) TemplateOption {
 	}
 }
 
+func TemplateWithOwner(owner string) TemplateOption {
+	return func(t *ErrorTemplate) {
+		t.owner = owner
+	}
+}
+
 func TemplateWithHelp(help Help) TemplateOption {
 	return func(t *ErrorTemplate) {
+		help = help.copy()
 		t.help = &help
 	}
 }
 
+// TemplateWithHelpLink adds a documentation link whose URL may contain {key} placeholders,
+// resolved from each instance's metadata when rendered, e.g. "https://admin.shopify.com/orders/{orderId}"
 func TemplateWithHelpLink(description, url string) TemplateOption {
-	return func(t *ErrorTemplate) {
-		if t.help == nil {
-			t.help = &Help{}
-		}
-		t.help.links = append(t.help.links, HelpLink{
-			description: description,
-			url:         url,
-		})
-	}
+	return TemplateWithHelpLinkKind(HelpLinkDocumentation, description, url)
 }
 
 // NewError creates a new error instance from the template
+// by copying the template's pre-computed prototype, then applying options.
 func (et *ErrorTemplate) NewError(options ...ErrorOption) *TrogonError {
-	baseOptions := []ErrorOption{
-		WithCode(et.code),
-		WithVisibility(et.visibility)}
+	err := et.newPrototypeCopy()
 
-	if et.message != "" {
-		baseOptions = append(baseOptions, WithMessage(et.message))
+	for _, option := range options {
+		option(err)
 	}
-	if et.help != nil {
-		baseOptions = append(baseOptions, WithHelp(*et.help))
+	applyDefaults(err)
+
+	return err
+}
+
+// NewErrorf creates a new error instance from the template with a formatted message.
+// Arguments that are ErrorOptions are applied as options, after the message, instead of being formatted:
+//
+//	ErrUserNotFound.NewErrorf("user %s not found", userID, trogonerror.WithSubject("/userId"))
+func (et *ErrorTemplate) NewErrorf(format string, args ...any) *TrogonError {
+	var options []ErrorOption
+	formatArgs := make([]any, 0, len(args))
+	for _, arg := range args {
+		if option, ok := arg.(ErrorOption); ok {
+			options = append(options, option)
+			continue
+		}
+		formatArgs = append(formatArgs, arg)
+	}
+	return et.NewError(append([]ErrorOption{WithMessagef(format, formatArgs...)}, options...)...)
+}
+
+func (et *ErrorTemplate) newPrototypeCopy() *TrogonError {
+	prototype := et.prototype
+	if prototype == nil {
+		prototype = et.bake()
+	}
+
+	err := new(TrogonError)
+	*err = *prototype
+	if err.help != nil {
+		help := *err.help
+		err.help = &help
+	}
+	return err
+}
+
+func (et *ErrorTemplate) Domain() string           { return et.domain }
+func (et *ErrorTemplate) Reason() string           { return et.reason }
+func (et *ErrorTemplate) Code() Code               { return et.code }
+func (et *ErrorTemplate) Visibility() Visibility   { return et.visibility }
+func (et *ErrorTemplate) Owner() string            { return et.owner }
+func (et *ErrorTemplate) PublicMessage() string    { return et.publicMessage }
+func (et *ErrorTemplate) Tags() []string           { return slices.Clone(et.tags) }
+func (et *ErrorTemplate) Idempotency() Idempotency { return et.idempotency }
+
+// Help returns a copy of the template help links, with their URL placeholders unresolved
+func (et *ErrorTemplate) Help() *Help {
+	if et.help == nil {
+		return nil
 	}
+	help := et.help.copy()
+	return &help
+}
 
-	return NewError(et.domain, et.reason, append(baseOptions, options...)...)
+// Message returns the template message, or the code's default message when none was set
+func (et *ErrorTemplate) Message() string {
+	if et.message != "" {
+		return et.message
+	}
+	return et.code.Message()
 }
 
 // Is checks if the given error matches this template's domain and reason
@@ -885,13 +1355,7 @@ func (et *ErrorTemplate) Is(target error) bool {
 }
 
 func addHelpLink(e *TrogonError, description, url string) {
-	if e.help == nil {
-		e.help = &Help{}
-	}
-	e.help.links = append(e.help.links, HelpLink{
-		description: description,
-		url:         url,
-	})
+	addHelpLinkKind(e, HelpLinkDocumentation, description, url)
 }
 
 type trogonError interface {
@@ -923,9 +1387,47 @@ func As(err error, target trogonError) (*TrogonError, bool) {
 	return trogonErr, true
 }
 
-func addMetadataValue(e *TrogonError, visibility Visibility, key, value string) {
-	if len(e.metadata) == 0 {
-		e.metadata = make(Metadata)
+// AsAny checks the first TrogonError in err's chain against several templates at once and returns
+// it together with the first template it matches, so handlers don't call As in a loop.
+//
+// Example usage:
+//
+//	switch _, template, _ := trogonerror.AsAny(err, ErrInvalidEmail, ErrInvalidPhone); template {
+//	case ErrInvalidEmail:
+//	    // ...
+//	case ErrInvalidPhone:
+//	    // ...
+//	}
+func AsAny(err error, templates ...*ErrorTemplate) (*TrogonError, *ErrorTemplate, bool) {
+	var trogonErr *TrogonError
+	if !errors.As(err, &trogonErr) || trogonErr == nil {
+		return nil, nil, false
+	}
+
+	for _, template := range templates {
+		if template != nil && template.Is(trogonErr) {
+			return trogonErr, template, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// CodeOf returns the code of the first TrogonError in err's chain
+func CodeOf(err error) (Code, bool) {
+	var trogonErr *TrogonError
+	if !errors.As(err, &trogonErr) || trogonErr == nil {
+		return 0, false
 	}
-	e.metadata[key] = MetadataValue{value: value, visibility: visibility}
+	return trogonErr.code, true
+}
+
+// HasCode reports whether the first TrogonError in err's chain has the given code
+//
+//	if trogonerror.HasCode(err, trogonerror.CodeNotFound) {
+//	    return http.StatusNotFound
+//	}
+func HasCode(err error, code Code) bool {
+	c, ok := CodeOf(err)
+	return ok && c == code
 }
@@ -2,6 +2,7 @@ package trogonerror_test
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -355,3 +356,107 @@ func TestTrogonError_ExactFormat_WrappedErrorBeforeStackTrace(t *testing.T) {
 	assert.Contains(t, output, "wrapped error: database connection timeout")
 	assert.True(t, strings.Index(output, "wrapped error:") < strings.Index(output, "Debug: Connection pool exhausted"))
 }
+
+func TestTrogonError_ExactFormat_WithCauses(t *testing.T) {
+	network := trogonerror.NewError("shopify.network", "CONNECTION_RESET",
+		trogonerror.WithCode(trogonerror.CodeUnavailable))
+	database := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+		trogonerror.WithCode(trogonerror.CodeUnavailable),
+		trogonerror.WithMessage("PostgreSQL connection timeout"),
+		trogonerror.WithCause(network))
+	cache := trogonerror.NewError("shopify.cache", "CACHE_MISS")
+
+	err := trogonerror.NewError("shopify.users", "USER_FETCH_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/1234567890"),
+		trogonerror.WithCause(database, cache),
+		trogonerror.WithHelpLink("Status Page", "https://status.shopify.com"))
+
+	expected := `internal error
+  visibility: INTERNAL
+  domain: shopify.users
+  reason: USER_FETCH_FAILED
+  code: INTERNAL
+  metadata:
+    - userId: gid://shopify/Customer/1234567890 visibility=PUBLIC
+  causes:
+    - shopify.database/CONNECTION_FAILED (UNAVAILABLE): PostgreSQL connection timeout
+      causes:
+        - shopify.network/CONNECTION_RESET (UNAVAILABLE): service unavailable
+    - shopify.cache/CACHE_MISS (UNKNOWN): unknown error
+
+- Status Page: https://status.shopify.com`
+
+	assert.Equal(t, expected, err.Error())
+}
+
+func TestTrogonError_ExactFormat_CauseDepthLimit(t *testing.T) {
+	cause := trogonerror.NewError("shopify.level", "LEVEL_7")
+	for level := 6; level >= 1; level-- {
+		cause = trogonerror.NewError("shopify.level", fmt.Sprintf("LEVEL_%d", level),
+			trogonerror.WithCause(cause))
+	}
+
+	err := trogonerror.NewError("shopify.core", "SYSTEM_ERROR",
+		trogonerror.WithCause(cause))
+
+	expected := `unknown error
+  visibility: INTERNAL
+  domain: shopify.core
+  reason: SYSTEM_ERROR
+  code: UNKNOWN
+  causes:
+    - shopify.level/LEVEL_1 (UNKNOWN): unknown error
+      causes:
+        - shopify.level/LEVEL_2 (UNKNOWN): unknown error
+          causes:
+            - shopify.level/LEVEL_3 (UNKNOWN): unknown error
+              causes:
+                - shopify.level/LEVEL_4 (UNKNOWN): unknown error
+                  causes:
+                    - shopify.level/LEVEL_5 (UNKNOWN): unknown error
+                      causes: 1 omitted`
+
+	assert.Equal(t, expected, err.Error())
+}
+
+func newRepositoryError() *trogonerror.TrogonError {
+	return trogonerror.NewError("myapp.database", "QUERY_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithStackTrace())
+}
+
+func TestTrogonError_ExactFormat_WrappedStacksElideCommonFrames(t *testing.T) {
+	innerErr := newRepositoryError()
+	outerErr := trogonerror.NewError("myapp.users", "USER_FETCH_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithWrap(innerErr),
+		trogonerror.WithStackTrace())
+
+	innerFrames := innerErr.DebugInfo().StackEntries()
+	outerFrames := outerErr.DebugInfo().StackEntries()
+	common := 0
+	for common < len(innerFrames) && common < len(outerFrames) &&
+		innerFrames[len(innerFrames)-1-common] == outerFrames[len(outerFrames)-1-common] {
+		common++
+	}
+	assert.Greater(t, common, 0)
+
+	output := outerErr.Error()
+
+	assert.Contains(t, output, fmt.Sprintf("\n… %d frames in common …", common))
+	for _, entry := range innerFrames[:len(innerFrames)-common] {
+		assert.Contains(t, output, entry)
+	}
+	assert.Equal(t, 1, strings.Count(output, "testing.tRunner"), "shared frames should be printed once")
+	assert.Equal(t, 1, strings.Count(output, "newRepositoryError"))
+}
+
+func TestTrogonError_ExactFormat_WrappedStacksWithoutEnclosingStack(t *testing.T) {
+	innerErr := newRepositoryError()
+	outerErr := trogonerror.NewError("myapp.users", "USER_FETCH_FAILED",
+		trogonerror.WithWrap(innerErr))
+
+	assert.NotContains(t, outerErr.Error(), "frames in common")
+	assert.Contains(t, outerErr.Error(), innerErr.Error())
+}
@@ -1,6 +1,7 @@
 package trogonerror_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -878,6 +879,33 @@ func TestAs(t *testing.T) {
 
 }
 
+func TestAsAny(t *testing.T) {
+	invalidEmail := trogonerror.NewErrorTemplate("shopify.users", "INVALID_EMAIL")
+	invalidPhone := trogonerror.NewErrorTemplate("shopify.users", "INVALID_PHONE")
+	invalidName := trogonerror.NewErrorTemplate("shopify.users", "INVALID_NAME")
+
+	t.Run("Returns the matching template", func(t *testing.T) {
+		originalErr := invalidPhone.NewError()
+
+		trogonErr, template, ok := trogonerror.AsAny(fmt.Errorf("signup: %w", originalErr), invalidEmail, invalidPhone, invalidName)
+		assert.True(t, ok)
+		assert.Same(t, originalErr, trogonErr)
+		assert.Same(t, invalidPhone, template)
+	})
+
+	t.Run("No template matches", func(t *testing.T) {
+		trogonErr, template, ok := trogonerror.AsAny(invalidName.NewError(), invalidEmail, invalidPhone)
+		assert.False(t, ok)
+		assert.Nil(t, trogonErr)
+		assert.Nil(t, template)
+	})
+
+	t.Run("Non-TrogonError", func(t *testing.T) {
+		_, _, ok := trogonerror.AsAny(errors.New("regular error"), invalidEmail)
+		assert.False(t, ok)
+	})
+}
+
 func TestInternalMethods(t *testing.T) {
 	t.Run("TrogonError.is method delegates to Is", func(t *testing.T) {
 		err1 := trogonerror.NewError("shopify.session", "SESSION_EXPIRED")
@@ -971,3 +999,192 @@ func TestErrorTemplate(t *testing.T) {
 		assert.Len(t, err.Help().Links(), 1)
 	})
 }
+
+func TestNewErrorAllocations(t *testing.T) {
+	t.Run("NewError without options allocates only the error", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = trogonerror.NewError("shopify.users", "NOT_FOUND")
+		})
+
+		assert.Equal(t, float64(1), allocs)
+	})
+
+	t.Run("Metadata and causes are nil until used", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithMetadata(nil))
+
+		assert.Nil(t, err.Metadata())
+		assert.Nil(t, err.Causes())
+	})
+
+	t.Run("WithMetadata allocates metadata lazily", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithMetadata(map[string]trogonerror.MetadataValue{}))
+		assert.Nil(t, err.Metadata())
+
+		source := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/1"))
+		err = trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithMetadata(source.Metadata()))
+		assert.Equal(t, "gid://shopify/Customer/1", err.Metadata()["userId"].Value())
+	})
+}
+
+func BenchmarkNewError(b *testing.B) {
+	b.Run("NoOptions", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = trogonerror.NewError("shopify.users", "NOT_FOUND")
+		}
+	})
+
+	b.Run("ScalarOptions", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = trogonerror.NewError("shopify.users", "NOT_FOUND",
+				trogonerror.WithCode(trogonerror.CodeNotFound),
+				trogonerror.WithVisibility(trogonerror.VisibilityPublic))
+		}
+	})
+
+	b.Run("Metadata", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = trogonerror.NewError("shopify.users", "NOT_FOUND",
+				trogonerror.WithCode(trogonerror.CodeNotFound),
+				trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/1234567890"))
+		}
+	})
+}
+
+func TestClone(t *testing.T) {
+	t.Run("Deep copies causes and wrapped TrogonErrors", func(t *testing.T) {
+		nested := trogonerror.NewError("shopify.database", "DEADLOCK",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "table", "orders"))
+		cause := trogonerror.NewError("shopify.database", "CONNECTION_FAILED", trogonerror.WithCause(nested))
+		wrapped := trogonerror.NewError("shopify.inventory", "STOCK_UNAVAILABLE")
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithTimeNow(),
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithStackTrace(),
+			trogonerror.WithCause(cause),
+			trogonerror.WithWrap(wrapped))
+
+		cloned := original.Clone()
+
+		assert.Equal(t, original, cloned)
+		assert.NotSame(t, original, cloned)
+		assert.NotSame(t, original.Causes()[0], cloned.Causes()[0])
+		assert.NotSame(t, original.Causes()[0].Causes()[0], cloned.Causes()[0].Causes()[0])
+		assert.NotSame(t, wrapped, cloned.Unwrap())
+		assert.NotSame(t, original.Time(), cloned.Time())
+		assert.NotSame(t, original.RetryInfo(), cloned.RetryInfo())
+		assert.NotSame(t, original.DebugInfo(), cloned.DebugInfo())
+	})
+
+	t.Run("Mutating the clone leaves the original untouched", func(t *testing.T) {
+		cause := trogonerror.NewError("shopify.database", "CONNECTION_FAILED")
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithTimeNow(),
+			trogonerror.WithCause(cause))
+
+		cloned := original.Clone()
+		*cloned.Time() = time.Time{}
+		*cloned.Causes()[0] = *trogonerror.NewError("shopify.public", "REDACTED")
+
+		assert.False(t, original.Time().IsZero())
+		assert.Equal(t, "CONNECTION_FAILED", original.Causes()[0].Reason())
+	})
+
+	t.Run("Shares wrapped errors of other types", func(t *testing.T) {
+		wrapped := errors.New("connection reset")
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED", trogonerror.WithWrap(wrapped))
+
+		assert.Same(t, wrapped, original.Clone().Unwrap())
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var err *trogonerror.TrogonError
+		assert.Nil(t, err.Clone())
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	notFound := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithCode(trogonerror.CodeNotFound))
+
+	t.Run("Unwraps to the first TrogonError", func(t *testing.T) {
+		err := fmt.Errorf("lookup order: %w", notFound)
+
+		code, ok := trogonerror.CodeOf(err)
+		assert.True(t, ok)
+		assert.Equal(t, trogonerror.CodeNotFound, code)
+		assert.True(t, trogonerror.HasCode(err, trogonerror.CodeNotFound))
+		assert.False(t, trogonerror.HasCode(err, trogonerror.CodeInternal))
+	})
+
+	t.Run("Uses the outermost TrogonError", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.checkout", "CHECKOUT_FAILED",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithWrap(notFound))
+
+		assert.True(t, trogonerror.HasCode(err, trogonerror.CodeInternal))
+		assert.False(t, trogonerror.HasCode(err, trogonerror.CodeNotFound))
+	})
+
+	t.Run("Without a TrogonError", func(t *testing.T) {
+		code, ok := trogonerror.CodeOf(errors.New("connection reset"))
+		assert.False(t, ok)
+		assert.Zero(t, code)
+		assert.False(t, trogonerror.HasCode(nil, trogonerror.CodeUnknown))
+	})
+}
+
+func TestOwner(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.payments", "CHARGE_FAILED",
+		trogonerror.TemplateWithOwner("team-payments"))
+
+	err := template.NewError()
+	reassigned := err.WithChanges(trogonerror.WithChangeOwner("team-checkout"))
+	overridden := template.NewError(trogonerror.WithOwner("team-fraud"))
+
+	assert.Equal(t, "team-payments", err.Owner())
+	assert.Equal(t, "team-checkout", reassigned.Owner())
+	assert.Equal(t, "team-fraud", overridden.Owner())
+	assert.Contains(t, err.Error(), "\n  owner: team-payments")
+
+	data, marshalErr := json.Marshal(err)
+	var decoded trogonerror.TrogonError
+	assert.NoError(t, marshalErr)
+	assert.Contains(t, string(data), `"owner":"team-payments"`)
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "team-payments", decoded.Owner())
+}
+
+func TestChain(t *testing.T) {
+	errOrderNotSaved := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_NOT_SAVED",
+		trogonerror.TemplateWithCode(trogonerror.CodeInternal))
+
+	t.Run("Wraps a TrogonError, adopts its stack trace and records it as a cause", func(t *testing.T) {
+		cause := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithStackTrace())
+
+		err := trogonerror.Chain(errOrderNotSaved, cause, trogonerror.WithSubject("/order"))
+
+		assert.True(t, errOrderNotSaved.Is(err))
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, cause.StackTrace(), err.StackTrace())
+		assert.Equal(t, []*trogonerror.TrogonError{cause}, err.Causes())
+		assert.Equal(t, "/order", err.Subject())
+	})
+
+	t.Run("Records other errors as minimal causes", func(t *testing.T) {
+		cause := errors.New("connection refused")
+
+		err := trogonerror.Chain(errOrderNotSaved, cause)
+
+		assert.ErrorIs(t, err, cause)
+		assert.Len(t, err.Causes(), 1)
+		assert.Equal(t, "connection refused", err.Causes()[0].Message())
+	})
+}
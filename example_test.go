@@ -68,6 +68,8 @@ func ExampleWithCause() {
 	//   domain: shopify.users
 	//   reason: USER_FETCH_FAILED
 	//   code: INTERNAL
+	//   causes:
+	//     - shopify.database/CONNECTION_FAILED (INTERNAL): internal error
 	// 1
 	// shopify.database
 }
@@ -225,6 +227,29 @@ func ExampleWithRetryInfoDuration_retryLogic() {
 	// Window: 1m
 }
 
+func ExampleWithRateLimitInfo() {
+	// Error carrying the quota it was rejected by
+	resetTime := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+
+	err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+		trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+		trogonerror.WithMessage("API rate limit exceeded"),
+		trogonerror.WithRateLimitInfo(100, 0, time.Minute, resetTime))
+
+	if rateLimit := err.RateLimitInfo(); rateLimit != nil {
+		fmt.Printf("Rate limit: %d\n", rateLimit.Limit())
+		fmt.Printf("Remaining: %d\n", rateLimit.Remaining())
+		fmt.Printf("Window: %s\n", rateLimit.Window())
+		fmt.Printf("Resets in: %s\n", rateLimit.ResetAfter(resetTime.Add(-30*time.Second)))
+	}
+
+	// Output:
+	// Rate limit: 100
+	// Remaining: 0
+	// Window: 1m0s
+	// Resets in: 30s
+}
+
 func ExampleWithRetryTime_absoluteRetry() {
 	retryTime := time.Date(2024, 1, 15, 14, 35, 0, 0, time.UTC)
 
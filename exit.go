@@ -0,0 +1,78 @@
+package trogonerror
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExitCode returns the process exit code for the code, following the BSD sysexits.h conventions
+// where one applies, 130 for cancellation (as after SIGINT) and 1 otherwise
+func (c Code) ExitCode() int {
+	switch c {
+	case CodeCancelled:
+		return 130
+	case CodeInvalidArgument, CodeOutOfRange:
+		return 64 // EX_USAGE
+	case CodeFailedPrecondition:
+		return 65 // EX_DATAERR
+	case CodeNotFound:
+		return 66 // EX_NOINPUT
+	case CodeUnavailable, CodeUnimplemented:
+		return 69 // EX_UNAVAILABLE
+	case CodeInternal, CodeUnknown:
+		return 70 // EX_SOFTWARE
+	case CodeAlreadyExists:
+		return 73 // EX_CANTCREAT
+	case CodeDataLoss:
+		return 74 // EX_IOERR
+	case CodeDeadlineExceeded, CodeResourceExhausted, CodeAborted:
+		return 75 // EX_TEMPFAIL
+	case CodePermissionDenied, CodeUnauthenticated:
+		return 77 // EX_NOPERM
+	default:
+		return 1
+	}
+}
+
+// ExitStatus converts err into a process exit code and a terse one-line message for stderr,
+// "message (domain/REASON)". Errors that are not TrogonErrors are converted with Translate first.
+// A nil err yields 0 and an empty message.
+func ExitStatus(err error) (int, string) {
+	trogonErr := Translate(err)
+	if trogonErr == nil {
+		return 0, ""
+	}
+
+	message := strings.TrimSpace(trogonErr.Message())
+	if trogonErr.domain != MaskedDomain && trogonErr.domain != "" {
+		message += " (" + trogonErr.Key().String() + ")"
+	}
+	return trogonErr.code.ExitCode(), message
+}
+
+// Exit terminates the process for err, the last call of a CLI's main function: it prints the
+// ExitStatus message to stderr, prefixed by the program name, and exits with its code.
+// A nil err exits with status 0.
+//
+//	func main() {
+//	    trogonerror.Exit(run(os.Args[1:]))
+//	}
+func Exit(err error) {
+	code, message := ExitStatus(err)
+	if message != "" {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", programName(), message)
+	}
+	os.Exit(code)
+}
+
+func programName() string {
+	if len(os.Args) == 0 {
+		return "error"
+	}
+	name := os.Args[0]
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,43 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 64, trogonerror.CodeInvalidArgument.ExitCode())
+	assert.Equal(t, 66, trogonerror.CodeNotFound.ExitCode())
+	assert.Equal(t, 75, trogonerror.CodeResourceExhausted.ExitCode())
+	assert.Equal(t, 77, trogonerror.CodeUnauthenticated.ExitCode())
+	assert.Equal(t, 130, trogonerror.CodeCancelled.ExitCode())
+	assert.Equal(t, 1, trogonerror.Code(0).ExitCode())
+}
+
+func TestExitStatus(t *testing.T) {
+	t.Run("Converts a TrogonError", func(t *testing.T) {
+		code, message := trogonerror.ExitStatus(trogonerror.NewError("shopify.config", "MISSING_TOKEN",
+			trogonerror.WithCode(trogonerror.CodeUnauthenticated),
+			trogonerror.WithMessage("SHOPIFY_TOKEN is not set")))
+
+		assert.Equal(t, 77, code)
+		assert.Equal(t, "SHOPIFY_TOKEN is not set (shopify.config/MISSING_TOKEN)", message)
+	})
+
+	t.Run("Translates other errors", func(t *testing.T) {
+		code, message := trogonerror.ExitStatus(errors.New("boom"))
+
+		assert.Equal(t, 70, code)
+		assert.NotEmpty(t, message)
+	})
+
+	t.Run("Succeeds without an error", func(t *testing.T) {
+		code, message := trogonerror.ExitStatus(nil)
+
+		assert.Equal(t, 0, code)
+		assert.Empty(t, message)
+	})
+}
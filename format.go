@@ -0,0 +1,165 @@
+package trogonerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Format implements fmt.Formatter:
+//
+//	%s   the message only
+//	%q   the quoted message
+//	%v   a single-line summary: "domain/reason (CODE): message"
+//	%+v  the full multi-line form rendered by TextFormatter
+func (e TrogonError) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			_, _ = io.WriteString(f, e.text())
+			return
+		}
+		_, _ = io.WriteString(f, e.summary())
+	case 's':
+		_, _ = io.WriteString(f, strings.TrimSpace(e.Message()))
+	case 'q':
+		_, _ = fmt.Fprintf(f, "%q", strings.TrimSpace(e.Message()))
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(trogonerror.TrogonError=%s)", verb, e.summary())
+	}
+}
+
+func (e TrogonError) summary() string {
+	return fmt.Sprintf("%s/%s (%s): %s", e.domain, e.reason, e.code.String(), strings.TrimSpace(e.Message()))
+}
+
+// Formatter renders a TrogonError as a string
+type Formatter interface {
+	Format(e *TrogonError) string
+}
+
+// FormatterFunc adapts a function to the Formatter interface
+type FormatterFunc func(e *TrogonError) string
+
+// Format calls f(e)
+func (f FormatterFunc) Format(e *TrogonError) string { return f(e) }
+
+var (
+	// TextFormatter renders the indented multi-line layout with metadata, help links and stack traces
+	TextFormatter Formatter = FormatterFunc(func(e *TrogonError) string { return e.text() })
+
+	// CompactFormatter renders a single line: "domain/reason (CODE): message"
+	CompactFormatter Formatter = FormatterFunc(func(e *TrogonError) string { return e.summary() })
+
+	// KeyValueFormatter renders a single logfmt-style line of key=value pairs
+	KeyValueFormatter Formatter = FormatterFunc(func(e *TrogonError) string { return e.keyValue() })
+
+	// JSONFormatter renders the JSON representation of the error on a single line
+	JSONFormatter Formatter = FormatterFunc(func(e *TrogonError) string {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return e.summary()
+		}
+		return string(data)
+	})
+)
+
+var formatter atomic.Pointer[Formatter]
+
+// SetDefaultFormatter sets the Formatter used by Error().
+// Passing nil restores TextFormatter.
+func SetDefaultFormatter(f Formatter) {
+	if f == nil {
+		formatter.Store(nil)
+		return
+	}
+	formatter.Store(&f)
+}
+
+func defaultFormatter() Formatter {
+	if f := formatter.Load(); f != nil {
+		return *f
+	}
+	return TextFormatter
+}
+
+func (e TrogonError) keyValue() string {
+	sb := &strings.Builder{}
+	writeKeyValue(sb, "domain", e.domain)
+	writeKeyValue(sb, "reason", e.reason)
+	writeKeyValue(sb, "code", e.code.String())
+	writeKeyValue(sb, "message", strings.TrimSpace(e.Message()))
+	if e.publicMessage != "" {
+		writeKeyValue(sb, "publicMessage", e.publicMessage)
+	}
+	writeKeyValue(sb, "visibility", e.visibility.String())
+
+	if e.id != "" {
+		writeKeyValue(sb, "id", e.id)
+	}
+	if e.time != nil {
+		writeKeyValue(sb, "time", e.time.Format(time.RFC3339))
+	}
+	if e.subject != "" {
+		writeKeyValue(sb, "subject", e.subject)
+	}
+	if e.sourceID != "" {
+		writeKeyValue(sb, "sourceId", e.sourceID)
+	}
+	if e.owner != "" {
+		writeKeyValue(sb, "owner", e.owner)
+	}
+	if e.transient != nil {
+		writeKeyValue(sb, "transient", strconv.FormatBool(*e.transient))
+	}
+	if e.idempotency != IdempotencyUnspecified {
+		writeKeyValue(sb, "idempotency", e.idempotency.String())
+	}
+	if len(e.tags) > 0 {
+		writeKeyValue(sb, "tags", strings.Join(e.tags, ","))
+	}
+	if operations := e.Operations(); len(operations) > 0 {
+		writeKeyValue(sb, "operations", strings.Join(operations, ">"))
+	}
+	if e.retryInfo != nil {
+		if e.retryInfo.retryOffset != nil {
+			writeKeyValue(sb, "retryOffset", e.retryInfo.retryOffset.String())
+		} else if e.retryInfo.retryTime != nil {
+			writeKeyValue(sb, "retryTime", e.retryInfo.retryTime.Format(time.RFC3339))
+		}
+	}
+	if e.rateLimitInfo != nil {
+		writeKeyValue(sb, "rateLimit.limit", strconv.Itoa(e.rateLimitInfo.limit))
+		writeKeyValue(sb, "rateLimit.remaining", strconv.Itoa(e.rateLimitInfo.remaining))
+		if e.rateLimitInfo.resetTime != nil {
+			writeKeyValue(sb, "rateLimit.resetTime", e.rateLimitInfo.resetTime.Format(time.RFC3339))
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(e.metadata)) {
+		writeKeyValue(sb, "metadata."+k, RedactMetadataValue(k, e.metadata[k].value))
+	}
+	if e.wrappedErr != nil {
+		writeKeyValue(sb, "wrappedError", e.wrappedErr.Error())
+	}
+
+	return sb.String()
+}
+
+func writeKeyValue(sb *strings.Builder, key, value string) {
+	if sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(key)
+	sb.WriteByte('=')
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		sb.WriteString(strconv.Quote(value))
+		return
+	}
+	sb.WriteString(value)
+}
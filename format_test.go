@@ -0,0 +1,282 @@
+package trogonerror_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/1234567890"))
+
+	t.Run("%v prints a single-line summary", func(t *testing.T) {
+		assert.Equal(t, "shopify.users/NOT_FOUND (NOT_FOUND): resource not found", fmt.Sprintf("%v", err))
+	})
+
+	t.Run("%+v prints the full form", func(t *testing.T) {
+		assert.Equal(t, err.Error(), fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("%s prints the message", func(t *testing.T) {
+		assert.Equal(t, "resource not found", fmt.Sprintf("%s", err))
+	})
+
+	t.Run("%q prints the quoted message", func(t *testing.T) {
+		assert.Equal(t, `"resource not found"`, fmt.Sprintf("%q", err))
+	})
+
+	t.Run("Unsupported verbs are reported", func(t *testing.T) {
+		assert.Equal(t, "%!d(trogonerror.TrogonError=shopify.users/NOT_FOUND (NOT_FOUND): resource not found)", fmt.Sprintf("%d", err))
+	})
+
+	t.Run("Wrapping with %w keeps log lines compact", func(t *testing.T) {
+		wrapped := fmt.Errorf("load profile: %w", err)
+
+		assert.Equal(t, "load profile: shopify.users/NOT_FOUND (NOT_FOUND): resource not found", wrapped.Error())
+	})
+
+	t.Run("Value and pointer format the same", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%v", err), fmt.Sprintf("%v", *err))
+	})
+}
+
+func TestFormatters(t *testing.T) {
+	err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithMessage("Payment gateway unavailable"),
+		trogonerror.WithID("err_123"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/5432109876"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "gateway", "stripe"))
+
+	t.Run("TextFormatter matches the default Error output", func(t *testing.T) {
+		assert.Equal(t, err.Error(), trogonerror.TextFormatter.Format(err))
+	})
+
+	t.Run("CompactFormatter renders a single line", func(t *testing.T) {
+		assert.Equal(t, "shopify.orders/ORDER_FAILED (INTERNAL): Payment gateway unavailable", trogonerror.CompactFormatter.Format(err))
+	})
+
+	t.Run("KeyValueFormatter renders logfmt pairs", func(t *testing.T) {
+		expected := `domain=shopify.orders reason=ORDER_FAILED code=INTERNAL message="Payment gateway unavailable" visibility=INTERNAL id=err_123 metadata.gateway=stripe metadata.orderId=gid://shopify/Order/5432109876`
+
+		assert.Equal(t, expected, trogonerror.KeyValueFormatter.Format(err))
+	})
+
+	t.Run("JSONFormatter renders a single JSON line", func(t *testing.T) {
+		expected := `{"specversion":1,"code":"INTERNAL","message":"Payment gateway unavailable","domain":"shopify.orders","reason":"ORDER_FAILED","metadata":{"gateway":{"value":"stripe","visibility":"INTERNAL"},"orderId":{"value":"gid://shopify/Order/5432109876","visibility":"PUBLIC"}},"visibility":"INTERNAL","id":"err_123"}`
+
+		assert.Equal(t, expected, trogonerror.JSONFormatter.Format(err))
+	})
+
+	t.Run("SetDefaultFormatter changes Error output globally", func(t *testing.T) {
+		trogonerror.SetDefaultFormatter(trogonerror.CompactFormatter)
+		t.Cleanup(func() { trogonerror.SetDefaultFormatter(nil) })
+
+		assert.Equal(t, "shopify.orders/ORDER_FAILED (INTERNAL): Payment gateway unavailable", err.Error())
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\n  metadata:")
+	})
+
+	t.Run("FormatterFunc plugs in custom formatters", func(t *testing.T) {
+		trogonerror.SetDefaultFormatter(trogonerror.FormatterFunc(func(e *trogonerror.TrogonError) string {
+			return e.Domain() + ":" + e.Reason()
+		}))
+		t.Cleanup(func() { trogonerror.SetDefaultFormatter(nil) })
+
+		assert.Equal(t, "shopify.orders:ORDER_FAILED", err.Error())
+	})
+}
+
+func TestFreeze(t *testing.T) {
+	t.Run("Freeze caches the rendered string", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithCode(trogonerror.CodeInternal)).Freeze()
+
+		expected := trogonerror.TextFormatter.Format(err)
+		trogonerror.SetDefaultFormatter(trogonerror.CompactFormatter)
+		t.Cleanup(func() { trogonerror.SetDefaultFormatter(nil) })
+
+		assert.Equal(t, expected, err.Error())
+	})
+
+	t.Run("WithChanges renders the copy again", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED").Freeze()
+
+		modified := original.WithChanges(trogonerror.WithChangeID("err_123"))
+
+		assert.NotContains(t, original.Error(), "id: err_123")
+		assert.Contains(t, modified.Error(), "id: err_123")
+	})
+
+	t.Run("The cached string never disagrees with the fields", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithMessage("order not found")).Freeze()
+
+		assert.Panics(t, func() {
+			trogonerror.WithCode(trogonerror.CodeInternal)(err)
+			trogonerror.WithMessage("internal failure")(err)
+		})
+
+		assert.Equal(t, trogonerror.CodeNotFound, err.Code())
+		assert.Equal(t, "order not found", err.Message())
+		assert.Equal(t, trogonerror.TextFormatter.Format(err), err.Error())
+	})
+
+	t.Run("Freeze can be called again to refresh the cache", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED").Freeze()
+
+		trogonerror.SetDefaultFormatter(trogonerror.CompactFormatter)
+		t.Cleanup(func() { trogonerror.SetDefaultFormatter(nil) })
+
+		assert.Equal(t, "shopify.orders/ORDER_FAILED (UNKNOWN): unknown error", err.Freeze().Error())
+	})
+
+	t.Run("Options applied in place to a frozen error panic", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED").Freeze()
+
+		assert.True(t, err.IsFrozen())
+		assert.Panics(t, func() {
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1")(err)
+		})
+		assert.Panics(t, func() { trogonerror.WithHelpLink("Docs", "https://docs.example.com")(err) })
+		assert.Panics(t, func() { trogonerror.WithCause(trogonerror.NewError("shopify.db", "FAILED"))(err) })
+		assert.Empty(t, err.Metadata())
+	})
+
+	t.Run("Every option panics on a frozen error", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound)).Freeze()
+		rendered := err.Error()
+
+		for name, option := range map[string]func(*trogonerror.TrogonError){
+			"WithCode":                    trogonerror.WithCode(trogonerror.CodeInternal),
+			"WithMessage":                 trogonerror.WithMessage("changed"),
+			"WithSubject":                 trogonerror.WithSubject("/orderId"),
+			"WithID":                      trogonerror.WithID("err_123"),
+			"WithTime":                    trogonerror.WithTime(time.Now()),
+			"WithRetryInfoDuration":       trogonerror.WithRetryInfoDuration(time.Second),
+			"WithTags":                    trogonerror.WithTags("checkout"),
+			"WithChangeID":                trogonerror.WithChangeID("err_456"),
+			"WithChangeRetryInfoDuration": trogonerror.WithChangeRetryInfoDuration(time.Second),
+		} {
+			assert.Panics(t, func() { option(err) }, name)
+		}
+
+		assert.Equal(t, trogonerror.CodeNotFound, err.Code())
+		assert.Equal(t, rendered, err.Error())
+	})
+
+	t.Run("Every option constructor guards against frozen errors", func(t *testing.T) {
+		packages, parseErr := parser.ParseDir(token.NewFileSet(), ".", func(info fs.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, 0)
+		assert.NoError(t, parseErr)
+
+		for _, file := range packages["trogonerror"].Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+					continue
+				}
+				result, ok := fn.Type.Results.List[0].Type.(*ast.Ident)
+				if !ok || (result.Name != "ErrorOption" && result.Name != "ChangeOption") {
+					continue
+				}
+				ast.Inspect(fn.Body, func(node ast.Node) bool {
+					if ret, ok := node.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+						_, isLiteral := ret.Results[0].(*ast.FuncLit)
+						assert.False(t, isLiteral, "%s returns an option not built with mutable", fn.Name.Name)
+					}
+					return true
+				})
+			}
+		}
+	})
+
+	t.Run("WithChanges returns a mutable copy of a frozen error", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED").Freeze()
+
+		modified := original.WithChanges(trogonerror.WithChangeMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"))
+
+		assert.False(t, modified.IsFrozen())
+		assert.Equal(t, "1", modified.Metadata()["orderId"].Value())
+		assert.Empty(t, original.Metadata())
+	})
+
+	t.Run("Causes returns a copy", func(t *testing.T) {
+		cause := trogonerror.NewError("shopify.db", "FAILED")
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED", trogonerror.WithCause(cause)).Freeze()
+
+		err.Causes()[0] = nil
+
+		assert.Same(t, cause, err.Causes()[0])
+	})
+}
+
+func BenchmarkError(b *testing.B) {
+	newErr := func() *trogonerror.TrogonError {
+		return trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/5432109876"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "gateway", "stripe"),
+			trogonerror.WithStackTrace())
+	}
+
+	b.Run("Rendered", func(b *testing.B) {
+		err := newErr()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = err.Error()
+		}
+	})
+
+	b.Run("Frozen", func(b *testing.B) {
+		err := newErr().Freeze()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = err.Error()
+		}
+	})
+}
+
+func TestConcurrentRendering(t *testing.T) {
+	err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/5432109876"))
+	expected := err.Error()
+
+	results := make(chan string, 50)
+	for range 50 {
+		go func() {
+			results <- err.Error()
+		}()
+	}
+
+	for range 50 {
+		assert.Equal(t, expected, <-results)
+	}
+}
+
+func BenchmarkErrorParallel(b *testing.B) {
+	err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/5432109876"),
+		trogonerror.WithStackTrace())
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = err.Error()
+		}
+	})
+}
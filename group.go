@@ -0,0 +1,66 @@
+package trogonerror
+
+// ErrorList is a collection of errors, such as the causes of an aggregate error or the errors of a Collector
+type ErrorList []*TrogonError
+
+// GroupBy groups errs by the key computed for each of them, keeping their order within each group.
+// Nil errors are skipped.
+func GroupBy[K comparable, S ~[]*TrogonError](errs S, key func(*TrogonError) K) map[K]S {
+	groups := make(map[K]S)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		k := key(err)
+		groups[k] = append(groups[k], err)
+	}
+	return groups
+}
+
+// GroupByCode groups errs by code
+//
+// Example usage:
+//
+//	for code, errs := range trogonerror.GroupByCode(failures) {
+//	    log.Printf("%s: %d failures", code, len(errs))
+//	}
+func GroupByCode[S ~[]*TrogonError](errs S) map[Code]S {
+	return GroupBy(errs, func(err *TrogonError) Code { return err.code })
+}
+
+// GroupByDomain groups errs by domain
+func GroupByDomain[S ~[]*TrogonError](errs S) map[string]S {
+	return GroupBy(errs, func(err *TrogonError) string { return err.domain })
+}
+
+// Filter returns the errors of errs for which keep reports true, in their original order.
+// Nil errors are skipped.
+func Filter[S ~[]*TrogonError](errs S, keep func(*TrogonError) bool) S {
+	var kept S
+	for _, err := range errs {
+		if err != nil && keep(err) {
+			kept = append(kept, err)
+		}
+	}
+	return kept
+}
+
+// Flatten returns the errors of errs followed by their causes, depth first, so grouping and
+// filtering also see the individual failures inside aggregate errors
+func Flatten[S ~[]*TrogonError](errs S) S {
+	var flat S
+	var walk func(err *TrogonError)
+	walk = func(err *TrogonError) {
+		if err == nil {
+			return
+		}
+		flat = append(flat, err)
+		for _, cause := range err.causes {
+			walk(cause)
+		}
+	}
+	for _, err := range errs {
+		walk(err)
+	}
+	return flat
+}
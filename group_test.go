@@ -0,0 +1,44 @@
+package trogonerror_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrouping(t *testing.T) {
+	notFound := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithCode(trogonerror.CodeNotFound))
+	declined := trogonerror.NewError("shopify.payments", "CARD_DECLINED", trogonerror.WithCode(trogonerror.CodeFailedPrecondition))
+	cancelled := trogonerror.NewError("shopify.orders", "ORDER_CANCELLED", trogonerror.WithCode(trogonerror.CodeFailedPrecondition))
+	errs := trogonerror.ErrorList{notFound, nil, declined, cancelled}
+
+	t.Run("GroupByCode", func(t *testing.T) {
+		groups := trogonerror.GroupByCode(errs)
+
+		assert.Len(t, groups, 2)
+		assert.Equal(t, trogonerror.ErrorList{notFound}, groups[trogonerror.CodeNotFound])
+		assert.Equal(t, trogonerror.ErrorList{declined, cancelled}, groups[trogonerror.CodeFailedPrecondition])
+	})
+
+	t.Run("GroupByDomain", func(t *testing.T) {
+		groups := trogonerror.GroupByDomain([]*trogonerror.TrogonError(errs))
+
+		assert.Equal(t, []*trogonerror.TrogonError{notFound, cancelled}, groups["shopify.orders"])
+		assert.Equal(t, []*trogonerror.TrogonError{declined}, groups["shopify.payments"])
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		kept := trogonerror.Filter(errs, func(err *trogonerror.TrogonError) bool { return err.Domain() == "shopify.orders" })
+
+		assert.Equal(t, trogonerror.ErrorList{notFound, cancelled}, kept)
+	})
+
+	t.Run("Flatten", func(t *testing.T) {
+		aggregate := trogonerror.NewError("shopify.checkout", "INVALID_CART", trogonerror.WithCause(declined, cancelled))
+
+		flat := trogonerror.Flatten(trogonerror.ErrorList{notFound, aggregate})
+
+		assert.Equal(t, trogonerror.ErrorList{notFound, aggregate, declined, cancelled}, flat)
+	})
+}
@@ -0,0 +1,25 @@
+package trogonerror
+
+// GRPCCode returns the gRPC status code for the error: the WithGRPCCode override if set,
+// otherwise the error code, whose values match the gRPC codes.
+func (e TrogonError) GRPCCode() Code {
+	if e.grpcCode != 0 {
+		return e.grpcCode
+	}
+	return e.code
+}
+
+// WithGRPCCode overrides the gRPC status code derived from the error code, for endpoints whose
+// clients expect a different code than the error's classification
+func WithGRPCCode(code Code) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.grpcCode = code
+	})
+}
+
+// WithChangeGRPCCode overrides the gRPC status code derived from the error code
+func WithChangeGRPCCode(code Code) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.grpcCode = code
+	})
+}
@@ -0,0 +1,114 @@
+package trogonerror
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ErrorHeader is the HTTP header carrying the identity of an error encoded by EncodeHeader,
+// for hops where a body is not available: redirects, proxies and responses without content
+const ErrorHeader = "X-Trogon-Error"
+
+// MaxErrorHeaderSize caps the encoded header; the optional fields are dropped when it is exceeded
+const MaxErrorHeaderSize = 1024
+
+var (
+	// ErrHeaderTooLarge is returned by EncodeHeader when even the domain, reason and code exceed MaxErrorHeaderSize
+	ErrHeaderTooLarge = errors.New("trogonerror: error header too large")
+	// ErrInvalidHeader is returned by DecodeHeader for values that are not an encoded error
+	ErrInvalidHeader = errors.New("trogonerror: invalid error header")
+)
+
+var headerSerializationPolicy = NewSerializationPolicy(VisibilityPublic, SerializationPolicyWithVisibilityFiltering())
+
+// EncodeHeader encodes the essential identity of the error as a single header value: domain, reason,
+// code, ID and retry info, URL-encoded like "code=NOT_FOUND&domain=shopify.orders&id=...&reason=ORDER_NOT_FOUND".
+// Errors less visible than what policy allows are masked as in MaskForPublic, keeping only the code,
+// ID and retry info. A nil policy encodes for a public audience with visibility filtering.
+// When the value exceeds MaxErrorHeaderSize, the retry info and then the ID are dropped.
+//
+// Example usage:
+//
+//	value, _ := trogonerror.EncodeHeader(err, nil)
+//	w.Header().Set(trogonerror.ErrorHeader, value)
+func EncodeHeader(err *TrogonError, policy *SerializationPolicy) (string, error) {
+	if policy == nil {
+		policy = headerSerializationPolicy
+	}
+
+	values := url.Values{}
+	values.Set("code", err.code.String())
+	if policy.AllowsMetadata(err.visibility) {
+		values.Set("domain", err.domain)
+		values.Set("reason", err.reason)
+	} else {
+		values.Set("domain", MaskedDomain)
+		values.Set("reason", err.code.String())
+	}
+	if err.id != "" {
+		values.Set("id", err.id)
+	}
+	if err.retryInfo != nil {
+		if err.retryInfo.retryOffset != nil {
+			values.Set("retryOffset", formatJSONDuration(*err.retryInfo.retryOffset))
+		}
+		if err.retryInfo.retryTime != nil {
+			values.Set("retryTime", err.retryInfo.retryTime.UTC().Format(time.RFC3339Nano))
+		}
+	}
+
+	encoded := values.Encode()
+	for _, optional := range []string{"retryOffset", "retryTime", "id"} {
+		if len(encoded) <= MaxErrorHeaderSize {
+			return encoded, nil
+		}
+		values.Del(optional)
+		encoded = values.Encode()
+	}
+	if len(encoded) > MaxErrorHeaderSize {
+		return "", fmt.Errorf("%w: %s %s", ErrHeaderTooLarge, err.domain, err.reason)
+	}
+	return encoded, nil
+}
+
+// DecodeHeader reconstructs the error identity encoded by EncodeHeader. Unknown fields are ignored,
+// so newer producers can add fields.
+func DecodeHeader(value string) (*TrogonError, error) {
+	values, err := url.ParseQuery(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+	if values.Get("domain") == "" || values.Get("reason") == "" {
+		return nil, fmt.Errorf("%w: missing domain or reason", ErrInvalidHeader)
+	}
+
+	code, err := ParseCode(values.Get("code"))
+	if err != nil {
+		code = CodeUnknown
+	}
+
+	decoded := &TrogonError{
+		specVersion: SpecVersion,
+		code:        code,
+		domain:      values.Get("domain"),
+		reason:      values.Get("reason"),
+		id:          values.Get("id"),
+	}
+	if offset := values.Get("retryOffset"); offset != "" {
+		duration, err := parseJSONDuration("retryOffset", offset)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+		}
+		decoded.retryInfo = &RetryInfo{retryOffset: &duration}
+	} else if retryTime := values.Get("retryTime"); retryTime != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, retryTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid retry time %q", ErrInvalidHeader, retryTime)
+		}
+		decoded.retryInfo = &RetryInfo{retryTime: &parsed}
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,66 @@
+package trogonerror_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHeader(t *testing.T) {
+	t.Run("Round-trip", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithRetryInfoDuration(1500*time.Millisecond),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1234"))
+
+		value, err := trogonerror.EncodeHeader(original, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "code=NOT_FOUND&domain=shopify.orders&id=err_123&reason=ORDER_NOT_FOUND&retryOffset=1.5s", value)
+
+		decoded, err := trogonerror.DecodeHeader(value)
+		assert.NoError(t, err)
+		assert.Equal(t, "shopify.orders", decoded.Domain())
+		assert.Equal(t, "ORDER_NOT_FOUND", decoded.Reason())
+		assert.Equal(t, trogonerror.CodeNotFound, decoded.Code())
+		assert.Equal(t, "err_123", decoded.ID())
+		assert.Equal(t, 1500*time.Millisecond, *decoded.RetryInfo().RetryOffset())
+		assert.Empty(t, decoded.Metadata())
+	})
+
+	t.Run("Masks errors hidden from the audience", func(t *testing.T) {
+		internal := trogonerror.NewError("shopify.database", "DEADLOCK",
+			trogonerror.WithCode(trogonerror.CodeAborted),
+			trogonerror.WithID("err_456"))
+
+		value, err := trogonerror.EncodeHeader(internal, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "code=ABORTED&domain=trogon.masked&id=err_456&reason=ABORTED", value)
+
+		value, err = trogonerror.EncodeHeader(internal, trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, err)
+		assert.Contains(t, value, "domain=shopify.database")
+	})
+
+	t.Run("Drops optional fields over the size cap", func(t *testing.T) {
+		long := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithID(strings.Repeat("x", trogonerror.MaxErrorHeaderSize)))
+
+		value, err := trogonerror.EncodeHeader(long, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, value, "id=")
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		_, err := trogonerror.DecodeHeader("code=NOT_FOUND")
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidHeader)
+
+		_, err = trogonerror.DecodeHeader("domain=shopify.orders&reason=ORDER_NOT_FOUND&retryOffset=soon")
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidHeader)
+	})
+}
@@ -0,0 +1,160 @@
+package trogonerror
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// HelpLinkKind tells UIs and on-call tooling what a help link points to
+type HelpLinkKind int
+
+const (
+	// HelpLinkDocumentation is the kind of links added with WithHelpLink
+	HelpLinkDocumentation HelpLinkKind = 0
+	HelpLinkRunbook       HelpLinkKind = 1
+	HelpLinkDashboard     HelpLinkKind = 2
+	HelpLinkSupport       HelpLinkKind = 3
+)
+
+func (k HelpLinkKind) String() string {
+	switch k {
+	case HelpLinkRunbook:
+		return "RUNBOOK"
+	case HelpLinkDashboard:
+		return "DASHBOARD"
+	case HelpLinkSupport:
+		return "SUPPORT"
+	default:
+		return "DOCUMENTATION"
+	}
+}
+
+func parseHelpLinkKind(name string) HelpLinkKind {
+	for _, kind := range []HelpLinkKind{HelpLinkRunbook, HelpLinkDashboard, HelpLinkSupport} {
+		if kind.String() == name {
+			return kind
+		}
+	}
+	return HelpLinkDocumentation
+}
+
+// Kind returns what the link points to
+func (h HelpLink) Kind() HelpLinkKind { return h.kind }
+
+// Link returns the first link of the given kind, e.g. the runbook an on-call engineer should open
+func (h Help) Link(kind HelpLinkKind) (HelpLink, bool) {
+	for _, link := range h.links {
+		if link.kind == kind {
+			return link, true
+		}
+	}
+	return HelpLink{}, false
+}
+
+// WithHelpLinkKind adds a help link of the given kind
+func WithHelpLinkKind(kind HelpLinkKind, description, url string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		addHelpLinkKind(e, kind, description, url)
+	})
+}
+
+// WithRunbookLink adds a link to the runbook for the error
+func WithRunbookLink(description, url string) ErrorOption {
+	return WithHelpLinkKind(HelpLinkRunbook, description, url)
+}
+
+// WithDashboardLink adds a link to a dashboard relevant to the error
+func WithDashboardLink(description, url string) ErrorOption {
+	return WithHelpLinkKind(HelpLinkDashboard, description, url)
+}
+
+// WithSupportLink adds a link where users can get support for the error
+func WithSupportLink(description, url string) ErrorOption {
+	return WithHelpLinkKind(HelpLinkSupport, description, url)
+}
+
+// WithChangeHelpLinkKind adds a help link of the given kind (appends to existing help)
+func WithChangeHelpLinkKind(kind HelpLinkKind, description, url string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		addHelpLinkKind(e, kind, description, url)
+	})
+}
+
+// TemplateWithHelpLinkKind adds a help link of the given kind to every error created from the template.
+// Like every template link, its URL may contain {key} placeholders resolved from the instance's metadata.
+func TemplateWithHelpLinkKind(kind HelpLinkKind, description, url string) TemplateOption {
+	return func(t *ErrorTemplate) {
+		if t.help == nil {
+			t.help = &Help{}
+		}
+		t.help.links = append(t.help.links, HelpLink{kind: kind, description: description, url: url, template: true})
+	}
+}
+
+// TemplateWithRunbookLink adds a link to the runbook to every error created from the template
+func TemplateWithRunbookLink(description, url string) TemplateOption {
+	return TemplateWithHelpLinkKind(HelpLinkRunbook, description, url)
+}
+
+func addHelpLinkKind(e *TrogonError, kind HelpLinkKind, description, url string) {
+	if e.help == nil {
+		e.help = &Help{}
+	}
+	e.help.links = append(e.help.links, HelpLink{kind: kind, description: description, url: url})
+}
+
+// resolvedHelp resolves the placeholders of template links from the metadata. Links referencing
+// keys the instance does not have are omitted rather than rendered broken.
+func (e TrogonError) resolvedHelp() *Help {
+	return e.helpFor(func(Visibility) bool { return true })
+}
+
+// helpFor resolves the placeholders of template links only from metadata whose visibility is allowed,
+// so links never leak metadata the audience may not see. Links referencing other keys are omitted.
+func (e TrogonError) helpFor(allows func(Visibility) bool) *Help {
+	if e.help == nil || !slices.ContainsFunc(e.help.links, func(link HelpLink) bool { return link.template }) {
+		return e.help
+	}
+
+	resolved := &Help{links: make([]HelpLink, 0, len(e.help.links))}
+	for _, link := range e.help.links {
+		if link.template {
+			resolvedURL, ok := resolveHelpURL(link.url, e.metadata, allows)
+			if !ok {
+				continue
+			}
+			link.url = resolvedURL
+			link.template = false
+		}
+		resolved.links = append(resolved.links, link)
+	}
+	return resolved
+}
+
+// resolveHelpURL replaces each {key} in rawURL with the path-escaped metadata value, failing when
+// a key is missing, its visibility is not allowed or a redactor masks its value, since a link
+// carrying a redacted value would leak it or be broken
+func resolveHelpURL(rawURL string, metadata Metadata, allows func(Visibility) bool) (string, bool) {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(rawURL, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rawURL[start:], '}')
+		if end < 0 {
+			break
+		}
+		key := rawURL[start+1 : start+end]
+		value, ok := metadata[key]
+		if !ok || !allows(value.visibility) || RedactMetadataValue(key, value.value) != value.value {
+			return "", false
+		}
+		sb.WriteString(rawURL[:start])
+		sb.WriteString(url.PathEscape(value.value))
+		rawURL = rawURL[start+end+1:]
+	}
+	sb.WriteString(rawURL)
+	return sb.String(), true
+}
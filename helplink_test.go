@@ -0,0 +1,139 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelpLinkKinds(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.payments", "SETTLEMENT_FAILED",
+		trogonerror.TemplateWithHelpLink("Settlement docs", "https://shopify.dev/docs/settlements"),
+		trogonerror.TemplateWithRunbookLink("Settlement runbook", "https://runbooks.shopify.io/settlements"))
+
+	err := template.NewError(
+		trogonerror.WithDashboardLink("Settlement dashboard", "https://grafana.shopify.io/d/settlements"),
+		trogonerror.WithSupportLink("Contact support", "https://help.shopify.com"))
+
+	t.Run("Kinds", func(t *testing.T) {
+		links := err.Help().Links()
+
+		assert.Equal(t, trogonerror.HelpLinkDocumentation, links[0].Kind())
+		assert.Equal(t, trogonerror.HelpLinkRunbook, links[1].Kind())
+		assert.Equal(t, trogonerror.HelpLinkDashboard, links[2].Kind())
+		assert.Equal(t, trogonerror.HelpLinkSupport, links[3].Kind())
+	})
+
+	t.Run("Link finds the first link of a kind", func(t *testing.T) {
+		runbook, ok := err.Help().Link(trogonerror.HelpLinkRunbook)
+		assert.True(t, ok)
+		assert.Equal(t, "https://runbooks.shopify.io/settlements", runbook.URL())
+
+		_, ok = template.NewError().Help().Link(trogonerror.HelpLinkSupport)
+		assert.False(t, ok)
+	})
+
+	t.Run("Rendering", func(t *testing.T) {
+		assert.Contains(t, err.Error(), "\n- Settlement docs: https://shopify.dev/docs/settlements")
+		assert.Contains(t, err.Error(), "\n- [runbook] Settlement runbook: https://runbooks.shopify.io/settlements")
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		data, marshalErr := json.Marshal(err)
+		var decoded trogonerror.TrogonError
+		unmarshalErr := json.Unmarshal(data, &decoded)
+
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, unmarshalErr)
+		assert.Contains(t, string(data), `{"description":"Settlement docs","url":"https://shopify.dev/docs/settlements"}`)
+		assert.Contains(t, string(data), `"kind":"RUNBOOK"`)
+		assert.Equal(t, err.Help().Links(), decoded.Help().Links())
+	})
+}
+
+func TestHelpLinkTemplates(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_STUCK",
+		trogonerror.TemplateWithHelpLink("Order in admin", "https://admin.shopify.com/store/{shop}/orders/{orderId}"),
+		trogonerror.TemplateWithRunbookLink("Runbook", "https://runbooks.shopify.io/orders/stuck"))
+
+	t.Run("Placeholders resolve from metadata", func(t *testing.T) {
+		err := template.NewError(
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "shop", "acme"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1001/2"))
+
+		links := err.Help().Links()
+
+		assert.Equal(t, "https://admin.shopify.com/store/acme/orders/1001%2F2", links[0].URL())
+		assert.Equal(t, "https://runbooks.shopify.io/orders/stuck", links[1].URL())
+		assert.Contains(t, err.Error(), "- Order in admin: https://admin.shopify.com/store/acme/orders/1001%2F2")
+	})
+
+	t.Run("Resolved at render time", func(t *testing.T) {
+		err := template.NewError(trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "shop", "acme"))
+		changed := err.WithChanges(trogonerror.WithChangeMetadataValue(trogonerror.VisibilityPublic, "orderId", "1002"))
+
+		data, _ := json.Marshal(changed)
+
+		assert.Contains(t, string(data), `"url":"https://admin.shopify.com/store/acme/orders/1002"`)
+	})
+
+	t.Run("Links with missing keys are omitted", func(t *testing.T) {
+		links := template.NewError().Help().Links()
+
+		assert.Len(t, links, 1)
+		assert.Equal(t, "Runbook", links[0].Description())
+	})
+
+	t.Run("Placeholders only resolve from metadata the audience may see", func(t *testing.T) {
+		err := template.NewError(
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "shop", "acme"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "orderId", "secret-internal-id"))
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic, trogonerror.SerializationPolicyWithVisibilityFiltering())
+
+		data, marshalErr := err.MarshalJSONFor(policy)
+		assert.NoError(t, marshalErr)
+		assert.NotContains(t, string(data), "secret-internal-id")
+		assert.Contains(t, string(data), "https://runbooks.shopify.io/orders/stuck")
+
+		masked := trogonerror.MaskForPublic(err).Help().Links()
+		assert.Len(t, masked, 1)
+		assert.Equal(t, "Runbook", masked[0].Description())
+
+		assert.Len(t, err.Help().Links(), 2)
+	})
+
+	t.Run("Placeholders never resolve from redacted metadata", func(t *testing.T) {
+		trogonerror.SetRedactors(trogonerror.RedactKeys("orderId"))
+		t.Cleanup(func() { trogonerror.SetRedactors() })
+		err := template.NewError(
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "shop", "acme"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "secret-order-id"))
+
+		data, marshalErr := json.Marshal(err)
+		assert.NoError(t, marshalErr)
+		assert.NotContains(t, string(data), "secret-order-id")
+		assert.NotContains(t, err.Error(), "secret-order-id")
+
+		rec := httptest.NewRecorder()
+		assert.NoError(t, trogonerror.Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil), err))
+		assert.NotContains(t, rec.Header().Get("Link"), "secret-order-id")
+		assert.NotContains(t, rec.Body.String(), "secret-order-id")
+
+		links := err.Help().Links()
+		assert.Len(t, links, 1)
+		assert.Equal(t, "Runbook", links[0].Description())
+	})
+
+	t.Run("Instance links are not templates", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_STUCK",
+			trogonerror.WithHelpLink("Literal", "https://example.com/{orderId}"))
+
+		assert.Equal(t, "https://example.com/{orderId}", err.Help().Links()[0].URL())
+	})
+}
@@ -0,0 +1,49 @@
+package trogonerror
+
+import "sync"
+
+// CreateHook observes every error once it has been created by NewError, NewErrorE or
+// ErrorTemplate.NewError, after the process-wide defaults have been applied.
+// Hooks run synchronously on the creating goroutine, so they must be fast and must not modify the error.
+type CreateHook func(err *TrogonError)
+
+type createHookEntry struct {
+	name string
+	hook CreateHook
+}
+
+var (
+	createHooksMu sync.RWMutex
+	createHooks   []createHookEntry
+)
+
+// RegisterCreateHook registers a hook called for every created error, in registration order.
+// Registering the same name again replaces the previous hook, and registering a nil hook removes it.
+func RegisterCreateHook(name string, hook CreateHook) {
+	createHooksMu.Lock()
+	defer createHooksMu.Unlock()
+
+	for i, entry := range createHooks {
+		if entry.name != name {
+			continue
+		}
+		if hook == nil {
+			createHooks = append(createHooks[:i:i], createHooks[i+1:]...)
+		} else {
+			createHooks[i].hook = hook
+		}
+		return
+	}
+	if hook != nil {
+		createHooks = append(createHooks, createHookEntry{name: name, hook: hook})
+	}
+}
+
+func runCreateHooks(err *TrogonError) {
+	createHooksMu.RLock()
+	defer createHooksMu.RUnlock()
+
+	for _, entry := range createHooks {
+		entry.hook(err)
+	}
+}
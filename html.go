@@ -0,0 +1,156 @@
+package trogonerror
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"net/http"
+)
+
+// HTMLPage is the data an HTMLRenderer template is executed with
+type HTMLPage struct {
+	Brand     string
+	LogoURL   string
+	Status    int
+	Title     string
+	Message   string
+	ID        string
+	HelpLinks []HelpLink
+}
+
+// HTMLRenderer renders errors as minimal, branded HTML pages for browser-facing endpoints.
+// Errors are filtered for the renderer's audience before rendering: for a public audience
+// non-public errors are masked with MaskForPublic and only documentation and support links are shown.
+type HTMLRenderer struct {
+	template *template.Template
+	brand    string
+	logoURL  string
+	audience Visibility
+}
+
+// HTMLRendererOption represents options for HTML renderer construction
+type HTMLRendererOption func(*HTMLRenderer)
+
+// NewHTMLRenderer creates an HTML renderer. Defaults: the built-in page template and a public audience.
+func NewHTMLRenderer(options ...HTMLRendererOption) *HTMLRenderer {
+	renderer := &HTMLRenderer{
+		template: defaultHTMLTemplate,
+		audience: VisibilityPublic,
+	}
+
+	for _, option := range options {
+		option(renderer)
+	}
+
+	return renderer
+}
+
+// HTMLRendererWithBrand sets the product name shown in the page header and title
+func HTMLRendererWithBrand(name string) HTMLRendererOption {
+	return func(r *HTMLRenderer) {
+		r.brand = name
+	}
+}
+
+// HTMLRendererWithLogo sets the URL of the logo shown in the page header
+func HTMLRendererWithLogo(url string) HTMLRendererOption {
+	return func(r *HTMLRenderer) {
+		r.logoURL = url
+	}
+}
+
+// HTMLRendererWithTemplate replaces the built-in page template; it is executed with an HTMLPage
+func HTMLRendererWithTemplate(t *template.Template) HTMLRendererOption {
+	return func(r *HTMLRenderer) {
+		r.template = t
+	}
+}
+
+// HTMLRendererWithAudience sets the audience the page is rendered for
+func HTMLRendererWithAudience(audience Visibility) HTMLRendererOption {
+	return func(r *HTMLRenderer) {
+		r.audience = audience
+	}
+}
+
+// Page returns the data the page for err is rendered from, filtered for the renderer's audience
+func (r *HTMLRenderer) Page(err *TrogonError) HTMLPage {
+	if r.audience == VisibilityPublic {
+		err = MaskForPublic(err)
+	}
+
+	page := HTMLPage{
+		Brand:   r.brand,
+		LogoURL: r.logoURL,
+		Status:  err.StatusCode(),
+		Title:   http.StatusText(err.StatusCode()),
+		Message: err.MessageForAudience(r.audience),
+		ID:      err.id,
+	}
+	if help := err.helpFor(func(visibility Visibility) bool { return visibility >= r.audience }); help != nil {
+		for _, link := range help.links {
+			if r.audience == VisibilityPublic && link.kind != HelpLinkDocumentation && link.kind != HelpLinkSupport {
+				continue
+			}
+			page.HelpLinks = append(page.HelpLinks, link)
+		}
+	}
+	return page
+}
+
+// Render writes the page for err to w
+func (r *HTMLRenderer) Render(w io.Writer, err *TrogonError) error {
+	return r.template.Execute(w, r.Page(err))
+}
+
+// WriteHTTPResponse writes the page for err as the HTTP response, with the error's status code,
+// Retry-After, X-RateLimit-* and WWW-Authenticate headers
+func (r *HTMLRenderer) WriteHTTPResponse(w http.ResponseWriter, err *TrogonError) error {
+	var page bytes.Buffer
+	if renderErr := r.Render(&page, err); renderErr != nil {
+		return renderErr
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", MediaTypeHTML+"; charset=utf-8")
+	header.Set("X-Content-Type-Options", "nosniff")
+	SetRetryAfter(header, err)
+	SetRateLimitHeaders(header, err)
+	SetWWWAuthenticate(header, err)
+	w.WriteHeader(err.StatusCode())
+	_, writeErr := w.Write(page.Bytes())
+	return writeErr
+}
+
+var defaultHTMLTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Status}} {{.Title}}{{with .Brand}} · {{.}}{{end}}</title>
+<style>
+body{font-family:system-ui,sans-serif;max-width:40rem;margin:4rem auto;padding:0 1rem;color:#222}
+header{display:flex;align-items:center;gap:.75rem;margin-bottom:2rem;font-weight:600}
+header img{height:2rem}
+code{background:#f3f3f3;padding:.1rem .3rem;border-radius:.2rem}
+</style>
+</head>
+<body>
+{{- if or .Brand .LogoURL}}
+<header>{{with .LogoURL}}<img src="{{.}}" alt="">{{end}}{{.Brand}}</header>
+{{- end}}
+<h1>{{.Status}} {{.Title}}</h1>
+<p>{{.Message}}</p>
+{{- if .HelpLinks}}
+<ul>
+{{- range .HelpLinks}}
+<li><a href="{{.URL}}">{{.Description}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .ID}}
+<p>Error ID: <code>{{.ID}}</code></p>
+{{- end}}
+</body>
+</html>
+`))
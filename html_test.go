@@ -0,0 +1,70 @@
+package trogonerror_test
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLRenderer(t *testing.T) {
+	publicErr := trogonerror.NewError("shopify.users", "NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.WithID("err-123"),
+		trogonerror.WithMessage("user not found"),
+		trogonerror.WithHelpLink("User docs", "https://docs.example.com/users"),
+		trogonerror.WithHelpLinkKind(trogonerror.HelpLinkRunbook, "Runbook", "https://runbooks.internal/users"))
+
+	t.Run("Renders a branded page", func(t *testing.T) {
+		renderer := trogonerror.NewHTMLRenderer(
+			trogonerror.HTMLRendererWithBrand("Shopify"),
+			trogonerror.HTMLRendererWithLogo("https://cdn.example.com/logo.svg"))
+
+		var page strings.Builder
+		assert.NoError(t, renderer.Render(&page, publicErr))
+
+		assert.Contains(t, page.String(), "<title>404 Not Found · Shopify</title>")
+		assert.Contains(t, page.String(), `<img src="https://cdn.example.com/logo.svg" alt="">Shopify`)
+		assert.Contains(t, page.String(), "<p>user not found</p>")
+		assert.Contains(t, page.String(), `<a href="https://docs.example.com/users">User docs</a>`)
+		assert.Contains(t, page.String(), "<code>err-123</code>")
+		assert.NotContains(t, page.String(), "runbooks.internal")
+	})
+
+	t.Run("Masks non-public errors for a public audience", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.db", "QUERY_FAILED",
+			trogonerror.WithID("err-456"),
+			trogonerror.WithMessage("query failed on replica db-7"),
+			trogonerror.WithHelpLink("Schema", "https://docs.internal/schema"))
+
+		page := trogonerror.NewHTMLRenderer().Page(err)
+
+		assert.Equal(t, http.StatusInternalServerError, page.Status)
+		assert.Equal(t, "unknown error", page.Message)
+		assert.Equal(t, "err-456", page.ID)
+		assert.Empty(t, page.HelpLinks)
+	})
+
+	t.Run("Keeps every help link for a private audience", func(t *testing.T) {
+		page := trogonerror.NewHTMLRenderer(trogonerror.HTMLRendererWithAudience(trogonerror.VisibilityPrivate)).Page(publicErr)
+
+		assert.Len(t, page.HelpLinks, 2)
+	})
+
+	t.Run("Uses a custom template", func(t *testing.T) {
+		renderer := trogonerror.NewHTMLRenderer(trogonerror.HTMLRendererWithTemplate(
+			template.Must(template.New("custom").Parse(`{{.Status}}: {{.Message}}`))))
+
+		rec := httptest.NewRecorder()
+		assert.NoError(t, renderer.WriteHTTPResponse(rec, publicErr))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "404: user not found", rec.Body.String())
+	})
+}
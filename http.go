@@ -0,0 +1,105 @@
+package trogonerror
+
+import (
+	"maps"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var httpStatusMapping atomic.Pointer[map[Code]int]
+
+// SetHttpStatusMapping overrides the HTTP status of the given codes for the whole process, e.g.
+// 422 instead of 400 for CodeFailedPrecondition. It is consulted by Code.HttpStatusCode and therefore
+// by every HTTP writer; codes missing from the table keep their default status, and a per-error
+// WithHttpStatusCode still wins. The table is copied. Passing nil or an empty table restores the defaults.
+func SetHttpStatusMapping(mapping map[Code]int) {
+	if len(mapping) == 0 {
+		httpStatusMapping.Store(nil)
+		return
+	}
+	mapping = maps.Clone(mapping)
+	httpStatusMapping.Store(&mapping)
+}
+
+// StatusCode returns the HTTP status for the error: the WithHttpStatusCode override if set,
+// otherwise the status mapped from the error code.
+// It satisfies the interface{ StatusCode() int } probed by HTTP frameworks.
+func (e TrogonError) StatusCode() int {
+	if e.httpStatusCode != 0 {
+		return e.httpStatusCode
+	}
+	return e.code.HttpStatusCode()
+}
+
+// WithHttpStatusCode overrides the HTTP status derived from the error code
+func WithHttpStatusCode(status int) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.httpStatusCode = status
+	})
+}
+
+// WithChangeHttpStatusCode overrides the HTTP status derived from the error code
+func WithChangeHttpStatusCode(status int) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.httpStatusCode = status
+	})
+}
+
+// RetryAfter formats the retry info as a Retry-After header value:
+// delta-seconds (rounded up) for retry offsets and an HTTP-date for retry times.
+// Returns an empty string when neither is set.
+func (r RetryInfo) RetryAfter() string {
+	switch {
+	case r.retryOffset != nil:
+		seconds := math.Ceil(max(*r.retryOffset, 0).Seconds())
+		return strconv.FormatInt(int64(seconds), 10)
+	case r.retryTime != nil:
+		return r.retryTime.UTC().Format(http.TimeFormat)
+	default:
+		return ""
+	}
+}
+
+// SetRetryAfter sets the Retry-After header from the error's retry info, if any,
+// falling back to the reset time of its rate limit info
+func SetRetryAfter(header http.Header, err *TrogonError) {
+	if err == nil {
+		return
+	}
+	var value string
+	switch {
+	case err.retryInfo != nil:
+		value = err.retryInfo.RetryAfter()
+	case err.rateLimitInfo != nil:
+		value = RetryInfo{retryTime: err.rateLimitInfo.resetTime}.RetryAfter()
+	}
+	if value != "" {
+		header.Set("Retry-After", value)
+	}
+}
+
+// WithRetryAfter sets retry information from a Retry-After header value received from an upstream response.
+// Delta-seconds become a retry offset and HTTP-dates become a retry time; empty or malformed values are ignored.
+func WithRetryAfter(value string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		header := strings.TrimSpace(value)
+		if header == "" {
+			return
+		}
+
+		if seconds, err := strconv.ParseInt(header, 10, 64); err == nil {
+			if seconds >= 0 {
+				WithRetryInfoDuration(time.Duration(seconds) * time.Second)(e)
+			}
+			return
+		}
+
+		if retryTime, err := http.ParseTime(header); err == nil {
+			WithRetryTime(retryTime)(e)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package trogonerror_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("Retry offset renders as delta-seconds rounded up", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(1500*time.Millisecond))
+
+		assert.Equal(t, "2", err.RetryInfo().RetryAfter())
+	})
+
+	t.Run("Retry time renders as an HTTP-date", func(t *testing.T) {
+		retryTime := time.Date(2024, 1, 15, 14, 35, 45, 0, time.FixedZone("EST", -5*60*60))
+		err := trogonerror.NewError("shopify.maintenance", "SERVICE_UNAVAILABLE",
+			trogonerror.WithRetryTime(retryTime))
+
+		assert.Equal(t, "Mon, 15 Jan 2024 19:35:45 GMT", err.RetryInfo().RetryAfter())
+	})
+
+	t.Run("SetRetryAfter writes the header", func(t *testing.T) {
+		header := http.Header{}
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Minute))
+
+		trogonerror.SetRetryAfter(header, err)
+
+		assert.Equal(t, "60", header.Get("Retry-After"))
+	})
+
+	t.Run("SetRetryAfter skips errors without retry info", func(t *testing.T) {
+		header := http.Header{}
+
+		trogonerror.SetRetryAfter(header, trogonerror.NewError("shopify.users", "NOT_FOUND"))
+		trogonerror.SetRetryAfter(header, nil)
+
+		assert.Empty(t, header)
+	})
+
+	t.Run("WithRetryAfter parses delta-seconds", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.upstream", "RATE_LIMITED",
+			trogonerror.WithRetryAfter(" 120 "))
+
+		assert.Equal(t, 2*time.Minute, *err.RetryInfo().RetryOffset())
+		assert.Nil(t, err.RetryInfo().RetryTime())
+	})
+
+	t.Run("WithRetryAfter parses HTTP-dates", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.upstream", "MAINTENANCE",
+			trogonerror.WithRetryAfter("Mon, 15 Jan 2024 19:35:45 GMT"))
+
+		assert.Nil(t, err.RetryInfo().RetryOffset())
+		assert.True(t, err.RetryInfo().RetryTime().Equal(time.Date(2024, 1, 15, 19, 35, 45, 0, time.UTC)))
+	})
+
+	t.Run("WithRetryAfter ignores malformed values", func(t *testing.T) {
+		for _, value := range []string{"", "soon", "-5", "1.5"} {
+			err := trogonerror.NewError("shopify.upstream", "RATE_LIMITED",
+				trogonerror.WithRetryAfter(value))
+
+			assert.Nil(t, err.RetryInfo(), value)
+		}
+	})
+
+	t.Run("WithRetryAfter can be shared across goroutines", func(t *testing.T) {
+		option := trogonerror.WithRetryAfter(" 120 ")
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := trogonerror.NewError("shopify.upstream", "RATE_LIMITED", option)
+				assert.Equal(t, 2*time.Minute, *err.RetryInfo().RetryOffset())
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	t.Run("StatusCode derives from the error code", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound))
+
+		assert.Equal(t, http.StatusNotFound, err.StatusCode())
+	})
+
+	t.Run("WithHttpStatusCode overrides the mapping", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_CANCELLABLE",
+			trogonerror.WithCode(trogonerror.CodeFailedPrecondition),
+			trogonerror.WithHttpStatusCode(http.StatusUnprocessableEntity))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, err.StatusCode())
+		assert.Equal(t, http.StatusBadRequest, err.Code().HttpStatusCode())
+	})
+
+	t.Run("WithChangeHttpStatusCode overrides the copy only", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_NOT_CANCELLABLE",
+			trogonerror.WithCode(trogonerror.CodeFailedPrecondition))
+
+		modified := original.WithChanges(trogonerror.WithChangeHttpStatusCode(http.StatusConflict))
+
+		assert.Equal(t, http.StatusBadRequest, original.StatusCode())
+		assert.Equal(t, http.StatusConflict, modified.StatusCode())
+	})
+
+	t.Run("SetHttpStatusMapping overrides the default statuses", func(t *testing.T) {
+		mapping := map[trogonerror.Code]int{trogonerror.CodeFailedPrecondition: http.StatusUnprocessableEntity}
+		trogonerror.SetHttpStatusMapping(mapping)
+		t.Cleanup(func() { trogonerror.SetHttpStatusMapping(nil) })
+		mapping[trogonerror.CodeNotFound] = http.StatusGone
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_CANCELLABLE",
+			trogonerror.WithCode(trogonerror.CodeFailedPrecondition))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, err.StatusCode())
+		assert.Equal(t, http.StatusNotFound, trogonerror.CodeNotFound.HttpStatusCode())
+
+		rec := httptest.NewRecorder()
+		assert.NoError(t, trogonerror.Respond(rec, httptest.NewRequest(http.MethodPost, "/orders/1/cancel", nil), err))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Frameworks can probe the StatusCode interface", func(t *testing.T) {
+		var err error = trogonerror.NewError("shopify.auth", "TOKEN_EXPIRED",
+			trogonerror.WithCode(trogonerror.CodeUnauthenticated))
+
+		coder, ok := err.(interface{ StatusCode() int })
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, coder.StatusCode())
+	})
+}
+
+func TestGRPCCode(t *testing.T) {
+	t.Run("GRPCCode derives from the error code", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound))
+
+		assert.Equal(t, trogonerror.CodeNotFound, err.GRPCCode())
+	})
+
+	t.Run("WithGRPCCode overrides the mapping", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_CANCELLABLE",
+			trogonerror.WithCode(trogonerror.CodeFailedPrecondition),
+			trogonerror.WithGRPCCode(trogonerror.CodeAborted))
+
+		assert.Equal(t, trogonerror.CodeAborted, err.GRPCCode())
+		assert.Equal(t, trogonerror.CodeFailedPrecondition, err.Code())
+		assert.Equal(t, trogonerror.CodeAborted, trogonerror.MaskForPublic(err).GRPCCode())
+	})
+}
@@ -0,0 +1,73 @@
+package trogonerror
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator produces unique identifiers for errors
+type IDGenerator func() string
+
+var idGenerator atomic.Pointer[IDGenerator]
+
+// SetIDGenerator replaces the generator used by WithGeneratedID and WithChangeGeneratedID.
+// Passing nil restores the default UUIDv7 generator.
+func SetIDGenerator(generator IDGenerator) {
+	if generator == nil {
+		idGenerator.Store(nil)
+		return
+	}
+	idGenerator.Store(&generator)
+}
+
+// NewUUIDv7 returns a time-ordered RFC 9562 UUIDv7 string, the default error ID format
+func NewUUIDv7() string {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[6:])
+
+	ms := uint64(time.Now().UnixMilli())
+	uuid[0] = byte(ms >> 40)
+	uuid[1] = byte(ms >> 32)
+	uuid[2] = byte(ms >> 24)
+	uuid[3] = byte(ms >> 16)
+	uuid[4] = byte(ms >> 8)
+	uuid[5] = byte(ms)
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x70 // version 7
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // RFC 9562 variant
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf[:])
+}
+
+func generateID() string {
+	if generator := idGenerator.Load(); generator != nil {
+		return (*generator)()
+	}
+	return NewUUIDv7()
+}
+
+// WithGeneratedID sets the error ID using the configured IDGenerator
+func WithGeneratedID() ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.id = generateID()
+	})
+}
+
+// WithChangeGeneratedID replaces the error ID with a freshly generated one
+func WithChangeGeneratedID() ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.id = generateID()
+	})
+}
@@ -0,0 +1,53 @@
+package trogonerror_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+var uuidv7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestGeneratedID(t *testing.T) {
+	t.Run("WithGeneratedID sets a UUIDv7 by default", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithGeneratedID())
+
+		assert.Regexp(t, uuidv7Pattern, err.ID())
+	})
+
+	t.Run("WithGeneratedID produces unique IDs", func(t *testing.T) {
+		err1 := trogonerror.NewError("shopify.orders", "ORDER_FAILED", trogonerror.WithGeneratedID())
+		err2 := trogonerror.NewError("shopify.orders", "ORDER_FAILED", trogonerror.WithGeneratedID())
+
+		assert.NotEqual(t, err1.ID(), err2.ID())
+	})
+
+	t.Run("SetIDGenerator plugs in a custom generator", func(t *testing.T) {
+		trogonerror.SetIDGenerator(func() string { return "err_custom_123" })
+		t.Cleanup(func() { trogonerror.SetIDGenerator(nil) })
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED", trogonerror.WithGeneratedID())
+
+		assert.Equal(t, "err_custom_123", err.ID())
+	})
+
+	t.Run("WithChangeGeneratedID replaces the ID on the copy only", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithID("err_original"))
+
+		modified := original.WithChanges(trogonerror.WithChangeGeneratedID())
+
+		assert.Equal(t, "err_original", original.ID())
+		assert.Regexp(t, uuidv7Pattern, modified.ID())
+	})
+
+	t.Run("NewUUIDv7 is time ordered", func(t *testing.T) {
+		first := trogonerror.NewUUIDv7()
+		second := trogonerror.NewUUIDv7()
+
+		assert.LessOrEqual(t, first[:13], second[:13])
+	})
+}
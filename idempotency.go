@@ -0,0 +1,75 @@
+package trogonerror
+
+// Idempotency tells generic retry middleware whether the failed operation may be re-submitted
+type Idempotency int
+
+const (
+	// IdempotencyUnspecified leaves the decision to the caller's own rules, e.g. HTTP method safety
+	IdempotencyUnspecified Idempotency = 0
+	// IdempotencySafe means the operation can be blindly re-submitted
+	IdempotencySafe Idempotency = 1
+	// IdempotencyRequiresKey means the operation can only be re-submitted with the same idempotency key
+	IdempotencyRequiresKey Idempotency = 2
+	// IdempotencyUnsafe means re-submitting the operation may apply it twice
+	IdempotencyUnsafe Idempotency = 3
+)
+
+func (i Idempotency) String() string {
+	switch i {
+	case IdempotencySafe:
+		return "SAFE"
+	case IdempotencyRequiresKey:
+		return "REQUIRES_IDEMPOTENCY_KEY"
+	case IdempotencyUnsafe:
+		return "UNSAFE"
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+// AllowsResubmit reports whether the operation may be re-submitted, given whether the
+// request carries an idempotency key. Unspecified idempotency never allows it.
+func (i Idempotency) AllowsResubmit(hasIdempotencyKey bool) bool {
+	switch i {
+	case IdempotencySafe:
+		return true
+	case IdempotencyRequiresKey:
+		return hasIdempotencyKey
+	default:
+		return false
+	}
+}
+
+func parseIdempotency(name string) Idempotency {
+	for _, idempotency := range []Idempotency{IdempotencySafe, IdempotencyRequiresKey, IdempotencyUnsafe} {
+		if idempotency.String() == name {
+			return idempotency
+		}
+	}
+	return IdempotencyUnspecified
+}
+
+// WithIdempotency sets whether the failed operation is safe to re-submit
+func WithIdempotency(idempotency Idempotency) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.idempotency = idempotency
+	})
+}
+
+// WithChangeIdempotency sets whether the failed operation is safe to re-submit
+func WithChangeIdempotency(idempotency Idempotency) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.idempotency = idempotency
+	})
+}
+
+// TemplateWithIdempotency sets the idempotency of every error created from the template,
+// typically IdempotencyRequiresKey or IdempotencyUnsafe for write operations
+func TemplateWithIdempotency(idempotency Idempotency) TemplateOption {
+	return func(t *ErrorTemplate) {
+		t.idempotency = idempotency
+	}
+}
+
+// Idempotency returns whether the failed operation is safe to re-submit
+func (e TrogonError) Idempotency() Idempotency { return e.idempotency }
@@ -0,0 +1,55 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	createOrder := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_CREATION_FAILED",
+		trogonerror.TemplateWithCode(trogonerror.CodeUnavailable),
+		trogonerror.TemplateWithIdempotency(trogonerror.IdempotencyRequiresKey))
+
+	t.Run("Templates set the guidance for write operations", func(t *testing.T) {
+		err := createOrder.NewError()
+		unsafe := err.WithChanges(trogonerror.WithChangeIdempotency(trogonerror.IdempotencyUnsafe))
+
+		assert.Equal(t, trogonerror.IdempotencyRequiresKey, err.Idempotency())
+		assert.Equal(t, trogonerror.IdempotencyUnsafe, unsafe.Idempotency())
+		assert.Equal(t, trogonerror.IdempotencySafe,
+			createOrder.NewError(trogonerror.WithIdempotency(trogonerror.IdempotencySafe)).Idempotency())
+		assert.Contains(t, err.Error(), "\n  idempotency: REQUIRES_IDEMPOTENCY_KEY")
+	})
+
+	t.Run("AllowsResubmit", func(t *testing.T) {
+		tests := []struct {
+			idempotency trogonerror.Idempotency
+			withoutKey  bool
+			withKey     bool
+		}{
+			{trogonerror.IdempotencyUnspecified, false, false},
+			{trogonerror.IdempotencySafe, true, true},
+			{trogonerror.IdempotencyRequiresKey, false, true},
+			{trogonerror.IdempotencyUnsafe, false, false},
+		}
+
+		for _, tt := range tests {
+			assert.Equal(t, tt.withoutKey, tt.idempotency.AllowsResubmit(false), tt.idempotency.String())
+			assert.Equal(t, tt.withKey, tt.idempotency.AllowsResubmit(true), tt.idempotency.String())
+		}
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		data, marshalErr := json.Marshal(createOrder.NewError())
+		var decoded trogonerror.TrogonError
+		unmarshalErr := json.Unmarshal(data, &decoded)
+
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, unmarshalErr)
+		assert.Contains(t, string(data), `"idempotency":"REQUIRES_IDEMPOTENCY_KEY"`)
+		assert.Equal(t, trogonerror.IdempotencyRequiresKey, decoded.Idempotency())
+	})
+}
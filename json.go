@@ -0,0 +1,528 @@
+package trogonerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type jsonError struct {
+	SpecVersion      int                          `json:"specversion" cbor:"1,keyasint"`
+	Code             string                       `json:"code" cbor:"2,keyasint"`
+	Message          string                       `json:"message" cbor:"3,keyasint"`
+	Domain           string                       `json:"domain" cbor:"4,keyasint"`
+	Reason           string                       `json:"reason" cbor:"5,keyasint"`
+	Metadata         map[string]jsonMetadataValue `json:"metadata,omitempty" cbor:"6,keyasint,omitempty"`
+	Causes           []jsonError                  `json:"causes,omitempty" cbor:"7,keyasint,omitempty"`
+	Visibility       string                       `json:"visibility" cbor:"8,keyasint"`
+	Subject          string                       `json:"subject,omitempty" cbor:"9,keyasint,omitempty"`
+	ID               string                       `json:"id,omitempty" cbor:"10,keyasint,omitempty"`
+	Time             *time.Time                   `json:"time,omitempty" cbor:"11,keyasint,omitempty"`
+	Help             *jsonHelp                    `json:"help,omitempty" cbor:"12,keyasint,omitempty"`
+	DebugInfo        *jsonDebugInfo               `json:"debugInfo,omitempty" cbor:"13,keyasint,omitempty"`
+	LocalizedMessage *jsonLocalizedMessage        `json:"localizedMessage,omitempty" cbor:"14,keyasint,omitempty"`
+	RetryInfo        *jsonRetryInfo               `json:"retryInfo,omitempty" cbor:"15,keyasint,omitempty"`
+	SourceID         string                       `json:"sourceId,omitempty" cbor:"16,keyasint,omitempty"`
+	WrappedError     string                       `json:"wrappedError,omitempty" cbor:"17,keyasint,omitempty"`
+	Tags             []string                     `json:"tags,omitempty" cbor:"18,keyasint,omitempty"`
+	Operations       []string                     `json:"operations,omitempty" cbor:"19,keyasint,omitempty"`
+	Owner            string                       `json:"owner,omitempty" cbor:"20,keyasint,omitempty"`
+	Transient        *bool                        `json:"transient,omitempty" cbor:"21,keyasint,omitempty"`
+	Idempotency      string                       `json:"idempotency,omitempty" cbor:"22,keyasint,omitempty"`
+	PublicMessage    string                       `json:"publicMessage,omitempty" cbor:"23,keyasint,omitempty"`
+	Translations     []jsonLocalizedMessage       `json:"localizedMessages,omitempty" cbor:"24,keyasint,omitempty"`
+	RateLimitInfo    *jsonRateLimitInfo           `json:"rateLimitInfo,omitempty" cbor:"25,keyasint,omitempty"`
+	DeadlineInfo     *jsonDeadlineInfo            `json:"deadlineInfo,omitempty" cbor:"26,keyasint,omitempty"`
+	AuthInfo         *jsonAuthInfo                `json:"authInfo,omitempty" cbor:"27,keyasint,omitempty"`
+}
+
+type jsonMetadataValue struct {
+	Value      string `json:"value" cbor:"1,keyasint"`
+	Visibility string `json:"visibility" cbor:"2,keyasint"`
+}
+
+type jsonHelp struct {
+	Links []jsonHelpLink `json:"links" cbor:"1,keyasint"`
+}
+
+type jsonHelpLink struct {
+	Description string `json:"description" cbor:"1,keyasint"`
+	URL         string `json:"url" cbor:"2,keyasint"`
+	Kind        string `json:"kind,omitempty" cbor:"3,keyasint,omitempty"`
+}
+
+type jsonDebugInfo struct {
+	StackEntries []string         `json:"stackEntries,omitempty" cbor:"1,keyasint,omitempty"`
+	Detail       string           `json:"detail,omitempty" cbor:"2,keyasint,omitempty"`
+	Goroutines   string           `json:"goroutines,omitempty" cbor:"3,keyasint,omitempty"`
+	Source       string           `json:"source,omitempty" cbor:"4,keyasint,omitempty"`
+	Runtime      *jsonRuntimeInfo `json:"runtime,omitempty" cbor:"5,keyasint,omitempty"`
+}
+
+type jsonRuntimeInfo struct {
+	GOOS       string            `json:"goos" cbor:"1,keyasint"`
+	GOARCH     string            `json:"goarch" cbor:"2,keyasint"`
+	GoVersion  string            `json:"goVersion" cbor:"3,keyasint"`
+	GOMAXPROCS int               `json:"gomaxprocs" cbor:"4,keyasint"`
+	Env        map[string]string `json:"env,omitempty" cbor:"5,keyasint,omitempty"`
+}
+
+type jsonLocalizedMessage struct {
+	Locale  string `json:"locale" cbor:"1,keyasint"`
+	Message string `json:"message" cbor:"2,keyasint"`
+}
+
+type jsonRateLimitInfo struct {
+	Limit     int        `json:"limit" cbor:"1,keyasint"`
+	Remaining int        `json:"remaining" cbor:"2,keyasint"`
+	Window    string     `json:"window,omitempty" cbor:"3,keyasint,omitempty"`
+	ResetTime *time.Time `json:"resetTime,omitempty" cbor:"4,keyasint,omitempty"`
+}
+
+type jsonDeadlineInfo struct {
+	Deadline time.Time           `json:"deadline" cbor:"1,keyasint"`
+	Elapsed  string              `json:"elapsed" cbor:"2,keyasint"`
+	Stages   []jsonDeadlineStage `json:"stages,omitempty" cbor:"3,keyasint,omitempty"`
+}
+
+type jsonDeadlineStage struct {
+	Name     string `json:"name" cbor:"1,keyasint"`
+	Duration string `json:"duration" cbor:"2,keyasint"`
+}
+
+type jsonAuthInfo struct {
+	Scheme         string   `json:"scheme,omitempty" cbor:"1,keyasint,omitempty"`
+	RequiredScopes []string `json:"requiredScopes,omitempty" cbor:"2,keyasint,omitempty"`
+	RequiredRoles  []string `json:"requiredRoles,omitempty" cbor:"3,keyasint,omitempty"`
+	PrincipalClass string   `json:"principalClass,omitempty" cbor:"4,keyasint,omitempty"`
+}
+
+type jsonRetryInfo struct {
+	RetryOffset       string     `json:"retryOffset,omitempty" cbor:"1,keyasint,omitempty"`
+	RetryTime         *time.Time `json:"retryTime,omitempty" cbor:"2,keyasint,omitempty"`
+	MaxAttempts       int        `json:"maxAttempts,omitempty" cbor:"3,keyasint,omitempty"`
+	BackoffMultiplier float64    `json:"backoffMultiplier,omitempty" cbor:"4,keyasint,omitempty"`
+	MaxDelay          string     `json:"maxDelay,omitempty" cbor:"5,keyasint,omitempty"`
+}
+
+// MarshalJSON encodes the error using the camelCase field names of the specification.
+// Codes and visibilities are encoded by name and retry offsets as seconds with an "s" suffix.
+// Debug info and wrapped error text are subject to DefaultSerializationPolicy.
+func (e TrogonError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON(DefaultSerializationPolicy()))
+}
+
+func (e TrogonError) toJSON(policy *SerializationPolicy) jsonError {
+	return e.toJSONAt(policy, 1)
+}
+
+func (e TrogonError) toJSONAt(policy *SerializationPolicy, depth int) jsonError {
+	out := jsonError{
+		SpecVersion: e.specVersion,
+		Code:        e.code.String(),
+		Message:     e.MessageForAudience(policy.Audience()),
+		Domain:      e.domain,
+		Reason:      e.reason,
+		Visibility:  e.visibility.String(),
+		Subject:     e.subject,
+		ID:          e.id,
+		Time:        e.time,
+		SourceID:    e.sourceID,
+		Tags:        e.tags,
+		Operations:  e.Operations(),
+		Owner:       e.owner,
+		Transient:   e.transient,
+	}
+	if e.idempotency != IdempotencyUnspecified {
+		out.Idempotency = e.idempotency.String()
+	}
+	if policy.Audience() != VisibilityPublic {
+		out.PublicMessage = e.publicMessage
+	}
+
+	causes, omittedCauses := policy.keptCauses(e.causes, depth)
+	if len(causes) > 0 {
+		out.Causes = make([]jsonError, 0, len(causes))
+		for _, cause := range causes {
+			if cause != nil {
+				out.Causes = append(out.Causes, cause.toJSONAt(policy, depth+1))
+			}
+		}
+	}
+
+	if len(e.metadata) > 0 {
+		out.Metadata = make(map[string]jsonMetadataValue, len(e.metadata))
+		for k, v := range e.metadata {
+			if !policy.AllowsMetadata(v.visibility) {
+				continue
+			}
+			out.Metadata[k] = jsonMetadataValue{Value: RedactMetadataValue(k, v.value), Visibility: v.visibility.String()}
+		}
+	}
+	if omittedCauses > 0 {
+		if out.Metadata == nil {
+			out.Metadata = make(map[string]jsonMetadataValue, 1)
+		}
+		out.Metadata[MetadataCausesOmittedKey] = jsonMetadataValue{Value: strconv.Itoa(omittedCauses), Visibility: min(policy.Audience(), e.visibility).String()}
+	}
+
+	if help := e.helpFor(policy.AllowsMetadata); help != nil && len(help.links) > 0 {
+		out.Help = &jsonHelp{Links: make([]jsonHelpLink, len(help.links))}
+		for i, link := range help.links {
+			out.Help.Links[i] = jsonHelpLink{Description: link.description, URL: link.url}
+			if link.kind != HelpLinkDocumentation {
+				out.Help.Links[i].Kind = link.kind.String()
+			}
+		}
+	}
+
+	if e.debugInfo != nil && policy.AllowsDebugInfo() {
+		out.DebugInfo = &jsonDebugInfo{
+			StackEntries: e.debugInfo.StackEntries(),
+			Detail:       e.debugInfo.detail,
+			Goroutines:   e.debugInfo.goroutines,
+			Source:       e.debugInfo.sourceSnippet,
+		}
+		if r := e.debugInfo.runtimeInfo; r != nil {
+			out.DebugInfo.Runtime = &jsonRuntimeInfo{
+				GOOS:       r.goos,
+				GOARCH:     r.goarch,
+				GoVersion:  r.goVersion,
+				GOMAXPROCS: r.gomaxprocs,
+				Env:        r.env,
+			}
+		}
+	}
+
+	if e.localizedMessage != nil {
+		out.LocalizedMessage = &jsonLocalizedMessage{
+			Locale:  e.localizedMessage.locale,
+			Message: e.localizedMessage.message,
+		}
+	}
+	for _, translation := range e.translations {
+		out.Translations = append(out.Translations, jsonLocalizedMessage{Locale: translation.locale, Message: translation.message})
+	}
+
+	if e.retryInfo != nil {
+		out.RetryInfo = &jsonRetryInfo{
+			RetryTime:         e.retryInfo.retryTime,
+			MaxAttempts:       e.retryInfo.maxAttempts,
+			BackoffMultiplier: e.retryInfo.backoffMultiplier,
+		}
+		if e.retryInfo.retryOffset != nil {
+			out.RetryInfo.RetryOffset = formatJSONDuration(*e.retryInfo.retryOffset)
+		}
+		if e.retryInfo.maxDelay != 0 {
+			out.RetryInfo.MaxDelay = formatJSONDuration(e.retryInfo.maxDelay)
+		}
+	}
+
+	if e.rateLimitInfo != nil {
+		out.RateLimitInfo = &jsonRateLimitInfo{
+			Limit:     e.rateLimitInfo.limit,
+			Remaining: e.rateLimitInfo.remaining,
+			ResetTime: e.rateLimitInfo.resetTime,
+		}
+		if e.rateLimitInfo.window != 0 {
+			out.RateLimitInfo.Window = formatJSONDuration(e.rateLimitInfo.window)
+		}
+	}
+
+	if e.authInfo != nil {
+		out.AuthInfo = &jsonAuthInfo{
+			Scheme:         e.authInfo.scheme,
+			RequiredScopes: e.authInfo.requiredScopes,
+			RequiredRoles:  e.authInfo.requiredRoles,
+		}
+		if policy.AllowsDebugInfo() {
+			out.AuthInfo.PrincipalClass = e.authInfo.principalClass
+		}
+	}
+
+	if e.deadlineInfo != nil && policy.AllowsDebugInfo() {
+		out.DeadlineInfo = &jsonDeadlineInfo{
+			Deadline: e.deadlineInfo.deadline,
+			Elapsed:  formatJSONDuration(e.deadlineInfo.elapsed),
+		}
+		for _, stage := range e.deadlineInfo.stages {
+			out.DeadlineInfo.Stages = append(out.DeadlineInfo.Stages, jsonDeadlineStage{Name: stage.name, Duration: formatJSONDuration(stage.duration)})
+		}
+	}
+
+	if e.wrappedErr != nil && policy.AllowsDebugInfo() {
+		out.WrappedError = e.wrappedErr.Error()
+	}
+
+	return out
+}
+
+func formatJSONDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// UnmarshalJSON decodes an error encoded by MarshalJSON from any specification version.
+// The compatibility policy keeps errors flowing during rolling upgrades:
+//   - unknown fields are ignored, so newer producers can add fields;
+//   - snake_case field names ("source_id", "retry_info.retry_offset") are accepted as their
+//     camelCase equivalents, as in the protobuf JSON mapping;
+//   - unknown code names decode as CodeUnknown and unknown visibilities as VisibilityInternal;
+//   - a missing specversion is read as version 1.
+//
+// SpecVersion reports the version the error was encoded with. Stack entries decode into frames
+// carrying only file, line and function, and the wrapped error text into a plain error.
+func (e *TrogonError) UnmarshalJSON(data []byte) error {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	normalized, err := json.Marshal(camelCaseKeys(raw, ""))
+	if err != nil {
+		return err
+	}
+
+	return e.UnmarshalWith(func(v any) error { return json.Unmarshal(normalized, v) })
+}
+
+// UnmarshalWith decodes an error encoded by MarshalWith, calling unmarshal with a pointer to the
+// wire representation. Unknown codes and visibilities follow the compatibility policy of UnmarshalJSON.
+func (e *TrogonError) UnmarshalWith(unmarshal func(any) error) error {
+	var decoded jsonError
+	if err := unmarshal(&decoded); err != nil {
+		return err
+	}
+
+	trogonErr, err := fromJSON(decoded)
+	if err != nil {
+		return err
+	}
+	*e = *trogonErr
+	return nil
+}
+
+// camelCaseKeys renames snake_case object keys to camelCase, leaving user-defined keys untouched
+func camelCaseKeys(value any, parentKey string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		userKeys := parentKey == "metadata" || parentKey == "env"
+		renamed := make(map[string]any, len(v))
+		for key, child := range v {
+			name := key
+			if !userKeys {
+				name = snakeToCamel(key)
+			}
+			if _, exists := renamed[name]; exists && name != key {
+				continue // the canonical name wins over its alias
+			}
+			renamed[name] = camelCaseKeys(child, name)
+		}
+		return renamed
+	case []any:
+		for i, child := range v {
+			v[i] = camelCaseKeys(child, "")
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func snakeToCamel(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func fromJSON(j jsonError) (*TrogonError, error) {
+	code, err := ParseCode(j.Code)
+	if err != nil {
+		code = CodeUnknown
+	}
+	// Unknown visibilities decode as internal, the most restrictive one
+	visibility, _ := ParseVisibility(j.Visibility)
+
+	e := &TrogonError{
+		specVersion:   j.SpecVersion,
+		code:          code,
+		domain:        j.Domain,
+		reason:        j.Reason,
+		visibility:    visibility,
+		subject:       j.Subject,
+		id:            j.ID,
+		time:          j.Time,
+		sourceID:      j.SourceID,
+		publicMessage: j.PublicMessage,
+		owner:         j.Owner,
+		transient:     j.Transient,
+		idempotency:   parseIdempotency(j.Idempotency),
+		tags:          addTags(nil, j.Tags),
+	}
+	for _, operation := range slices.Backward(j.Operations) {
+		e.operations = appendOperation(e.operations, operation)
+	}
+	if e.specVersion == 0 {
+		e.specVersion = 1
+	}
+	if j.Message != e.code.Message() {
+		e.message = j.Message
+	}
+
+	if len(j.Metadata) > 0 {
+		e.metadata = make(Metadata, len(j.Metadata))
+		for k, v := range j.Metadata {
+			visibility, _ := ParseVisibility(v.Visibility)
+			e.metadata[k] = MetadataValue{value: v.Value, visibility: visibility}
+		}
+	}
+
+	for _, cause := range j.Causes {
+		decoded, err := fromJSON(cause)
+		if err != nil {
+			return nil, err
+		}
+		e.causes = append(e.causes, decoded)
+	}
+
+	if j.Help != nil && len(j.Help.Links) > 0 {
+		e.help = &Help{links: make([]HelpLink, len(j.Help.Links))}
+		for i, link := range j.Help.Links {
+			e.help.links[i] = HelpLink{kind: parseHelpLinkKind(link.Kind), description: link.Description, url: link.URL}
+		}
+	}
+
+	if j.DebugInfo != nil {
+		e.debugInfo = &DebugInfo{
+			detail:        j.DebugInfo.Detail,
+			goroutines:    j.DebugInfo.Goroutines,
+			sourceSnippet: j.DebugInfo.Source,
+		}
+		for _, entry := range j.DebugInfo.StackEntries {
+			e.debugInfo.stackFrames = append(e.debugInfo.stackFrames, parseStackEntry(entry))
+		}
+		if r := j.DebugInfo.Runtime; r != nil {
+			e.debugInfo.runtimeInfo = &RuntimeInfo{
+				goos:       r.GOOS,
+				goarch:     r.GOARCH,
+				goVersion:  r.GoVersion,
+				gomaxprocs: r.GOMAXPROCS,
+				env:        r.Env,
+			}
+		}
+	}
+
+	if j.LocalizedMessage != nil {
+		e.localizedMessage = &LocalizedMessage{locale: j.LocalizedMessage.Locale, message: j.LocalizedMessage.Message}
+	}
+	if len(j.Translations) > 0 {
+		translations := make(map[string]string, len(j.Translations))
+		for _, translation := range j.Translations {
+			translations[translation.Locale] = translation.Message
+		}
+		e.translations = mergeTranslations(nil, translations)
+	}
+
+	if j.RetryInfo != nil {
+		e.retryInfo = &RetryInfo{
+			retryTime:         j.RetryInfo.RetryTime,
+			maxAttempts:       j.RetryInfo.MaxAttempts,
+			backoffMultiplier: j.RetryInfo.BackoffMultiplier,
+		}
+		if j.RetryInfo.RetryOffset != "" {
+			offset, err := parseJSONDuration("retryInfo.retryOffset", j.RetryInfo.RetryOffset)
+			if err != nil {
+				return nil, err
+			}
+			e.retryInfo.retryOffset = &offset
+		}
+		if j.RetryInfo.MaxDelay != "" {
+			maxDelay, err := parseJSONDuration("retryInfo.maxDelay", j.RetryInfo.MaxDelay)
+			if err != nil {
+				return nil, err
+			}
+			e.retryInfo.maxDelay = maxDelay
+		}
+	}
+
+	if j.RateLimitInfo != nil {
+		e.rateLimitInfo = &RateLimitInfo{
+			limit:     j.RateLimitInfo.Limit,
+			remaining: j.RateLimitInfo.Remaining,
+			resetTime: j.RateLimitInfo.ResetTime,
+		}
+		if j.RateLimitInfo.Window != "" {
+			window, err := parseJSONDuration("rateLimitInfo.window", j.RateLimitInfo.Window)
+			if err != nil {
+				return nil, err
+			}
+			e.rateLimitInfo.window = window
+		}
+	}
+
+	if j.AuthInfo != nil {
+		e.authInfo = &AuthInfo{
+			scheme:         j.AuthInfo.Scheme,
+			requiredScopes: j.AuthInfo.RequiredScopes,
+			requiredRoles:  j.AuthInfo.RequiredRoles,
+			principalClass: j.AuthInfo.PrincipalClass,
+		}
+	}
+
+	if j.DeadlineInfo != nil {
+		elapsed, err := parseJSONDuration("deadlineInfo.elapsed", j.DeadlineInfo.Elapsed)
+		if err != nil {
+			return nil, err
+		}
+		e.deadlineInfo = &DeadlineInfo{deadline: j.DeadlineInfo.Deadline, elapsed: elapsed}
+		for _, stage := range j.DeadlineInfo.Stages {
+			duration, err := parseJSONDuration("deadlineInfo.stages.duration", stage.Duration)
+			if err != nil {
+				return nil, err
+			}
+			e.deadlineInfo.stages = append(e.deadlineInfo.stages, DeadlineStage{name: stage.Name, duration: duration})
+		}
+	}
+
+	if j.WrappedError != "" {
+		e.wrappedErr = errors.New(j.WrappedError)
+	}
+
+	return e, nil
+}
+
+// parseStackEntry reverses the "file:line function" layout of DebugInfo.StackEntries
+func parseStackEntry(entry string) runtime.Frame {
+	var frame runtime.Frame
+	location := entry
+	if space := strings.LastIndexByte(entry, ' '); space >= 0 {
+		location, frame.Function = entry[:space], entry[space+1:]
+	}
+	frame.File = location
+	if colon := strings.LastIndexByte(location, ':'); colon >= 0 {
+		if line, err := strconv.Atoi(location[colon+1:]); err == nil {
+			frame.File, frame.Line = location[:colon], line
+		}
+	}
+	return frame
+}
+
+// parseJSONDuration parses a protobuf JSON duration such as "1.5s", naming field in the error
+func parseJSONDuration(field, value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(strings.TrimSuffix(value, "s"), 64)
+	if err != nil || !strings.HasSuffix(value, "s") {
+		return 0, fmt.Errorf("trogonerror: invalid %s %q", field, value)
+	}
+	return time.Duration(math.Round(seconds * float64(time.Second))), nil
+}
@@ -0,0 +1,222 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	t.Run("Minimal error", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.core", "SYSTEM_ERROR")
+
+		data, marshalErr := json.Marshal(err)
+
+		assert.NoError(t, marshalErr)
+		assert.JSONEq(t, `{"specversion":1,"code":"UNKNOWN","message":"unknown error","domain":"shopify.core","reason":"SYSTEM_ERROR","visibility":"INTERNAL"}`, string(data))
+	})
+
+	t.Run("All fields", func(t *testing.T) {
+		timestamp := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+		cause := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+		err := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithMessage("Payment declined"),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithSubject("/payment/amount"),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithTime(timestamp),
+			trogonerror.WithSourceID("payment-service"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+			trogonerror.WithHelpLink("Contact Support", "https://admin.shopify.com/support"),
+			trogonerror.WithDebugDetail("gateway returned 502"),
+			trogonerror.WithLocalizedMessage("es-ES", "Pago rechazado"),
+			trogonerror.WithRetryInfoDuration(1500*time.Millisecond),
+			trogonerror.WithCause(cause),
+			trogonerror.WithWrap(errors.New("stripe: card_declined")))
+
+		data, marshalErr := err.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+
+		assert.NoError(t, marshalErr)
+		assert.JSONEq(t, `{
+			"specversion": 1,
+			"code": "INTERNAL",
+			"message": "Payment declined",
+			"domain": "shopify.payments",
+			"reason": "PAYMENT_DECLINED",
+			"metadata": {"orderId": {"value": "gid://shopify/Order/1", "visibility": "PUBLIC"}},
+			"causes": [{"specversion":1,"code":"UNAVAILABLE","message":"service unavailable","domain":"shopify.database","reason":"CONNECTION_FAILED","visibility":"INTERNAL"}],
+			"visibility": "PUBLIC",
+			"subject": "/payment/amount",
+			"id": "err_123",
+			"time": "2024-01-15T14:30:45Z",
+			"help": {"links": [{"description": "Contact Support", "url": "https://admin.shopify.com/support"}]},
+			"debugInfo": {"detail": "gateway returned 502"},
+			"localizedMessage": {"locale": "es-ES", "message": "Pago rechazado"},
+			"retryInfo": {"retryOffset": "1.5s"},
+			"sourceId": "payment-service",
+			"wrappedError": "stripe: card_declined"
+		}`, string(data))
+	})
+
+	t.Run("Retry time", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.maintenance", "SERVICE_UNAVAILABLE",
+			trogonerror.WithRetryTime(time.Date(2024, 1, 15, 14, 35, 45, 0, time.UTC)))
+
+		data, marshalErr := json.Marshal(err)
+
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(data), `"retryInfo":{"retryTime":"2024-01-15T14:35:45Z"}`)
+	})
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	internal := trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal)
+
+	t.Run("Round-trips MarshalJSON", func(t *testing.T) {
+		timestamp := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+		original := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithMessage("Payment declined"),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithSubject("/payment/amount"),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithTime(timestamp),
+			trogonerror.WithSourceID("payment-service"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "order_id", "gid://shopify/Order/1"),
+			trogonerror.WithHelpLink("Contact Support", "https://admin.shopify.com/support"),
+			trogonerror.WithDebugDetail("gateway returned 502"),
+			trogonerror.WithLocalizedMessage("es-ES", "Pago rechazado"),
+			trogonerror.WithRetryInfoDuration(1500*time.Millisecond),
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+				trogonerror.WithCode(trogonerror.CodeUnavailable))))
+
+		data, err := original.MarshalJSONFor(internal)
+		assert.NoError(t, err)
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, original, &decoded)
+
+		again, err := decoded.MarshalJSONFor(internal)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(again))
+	})
+
+	t.Run("Decodes stack entries and wrapped error text", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithStackTrace(),
+			trogonerror.WithWrap(errors.New("connection reset")))
+
+		data, err := original.MarshalJSONFor(internal)
+		assert.NoError(t, err)
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, original.DebugInfo().StackEntries(), decoded.DebugInfo().StackEntries())
+		assert.EqualError(t, decoded.Unwrap(), "connection reset")
+	})
+
+	t.Run("Accepts newer versions and ignores unknown fields", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		err := json.Unmarshal([]byte(`{
+			"specversion": 3,
+			"code": "QUOTA_DEPLETED",
+			"message": "quota depleted",
+			"domain": "shopify.billing",
+			"reason": "QUOTA_DEPLETED",
+			"visibility": "PARTNER",
+			"severity": "high",
+			"retryInfo": {"retryOffset": "2s", "jitter": "0.5s"}
+		}`), &decoded)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, decoded.SpecVersion())
+		assert.Equal(t, trogonerror.CodeUnknown, decoded.Code())
+		assert.Equal(t, trogonerror.VisibilityInternal, decoded.Visibility())
+		assert.Equal(t, "quota depleted", decoded.Message())
+		assert.Equal(t, 2*time.Second, *decoded.RetryInfo().RetryOffset())
+	})
+
+	t.Run("Accepts snake_case field names", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		err := json.Unmarshal([]byte(`{
+			"spec_version": 1,
+			"code": "NOT_FOUND",
+			"domain": "shopify.orders",
+			"reason": "ORDER_NOT_FOUND",
+			"visibility": "PUBLIC",
+			"source_id": "orders-7f9c",
+			"metadata": {"order_id": {"value": "1", "visibility": "PUBLIC"}},
+			"retry_info": {"retry_offset": "0.25s"},
+			"localized_message": {"locale": "fr-CA", "message": "Commande introuvable"}
+		}`), &decoded)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "orders-7f9c", decoded.SourceID())
+		assert.Equal(t, "1", decoded.Metadata()["order_id"].Value())
+		assert.Equal(t, 250*time.Millisecond, *decoded.RetryInfo().RetryOffset())
+		assert.Equal(t, "fr-CA", decoded.LocalizedMessage().Locale())
+	})
+
+	t.Run("Reads a missing specversion as version 1", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal([]byte(`{"code":"INTERNAL","domain":"shopify.core","reason":"SYSTEM_ERROR"}`), &decoded))
+
+		assert.Equal(t, 1, decoded.SpecVersion())
+		assert.Equal(t, "internal error", decoded.Message())
+	})
+
+	t.Run("Rejects malformed retry offsets", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		err := json.Unmarshal([]byte(`{"code":"UNAVAILABLE","domain":"shopify.core","reason":"DOWN","retryInfo":{"retryOffset":"soon"}}`), &decoded)
+
+		assert.EqualError(t, err, `trogonerror: invalid retryInfo.retryOffset "soon"`)
+	})
+
+	t.Run("Names the malformed duration field", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		err := json.Unmarshal([]byte(`{"code":"RESOURCE_EXHAUSTED","domain":"shopify.core","reason":"THROTTLED","rateLimitInfo":{"window":"1m"}}`), &decoded)
+
+		assert.EqualError(t, err, `trogonerror: invalid rateLimitInfo.window "1m"`)
+	})
+
+	t.Run("Decodes unknown codes and visibilities leniently", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal([]byte(`{"code":"TEAPOT","domain":"shopify.core","reason":"SYSTEM_ERROR","visibility":"SECRET","metadata":{"shopId":{"value":"42","visibility":"SECRET"}}}`), &decoded))
+
+		assert.Equal(t, trogonerror.CodeUnknown, decoded.Code())
+		assert.Equal(t, trogonerror.VisibilityInternal, decoded.Visibility())
+		assert.Equal(t, trogonerror.VisibilityInternal, decoded.Metadata()["shopId"].Visibility())
+	})
+}
+
+func TestMarshalWith(t *testing.T) {
+	t.Run("Other codecs encode the wire representation", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithDebugDetail("gateway returned 502"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1234"))
+
+		data, err := original.MarshalWith(json.Marshal, trogonerror.DefaultSerializationPolicy())
+		assert.NoError(t, err)
+		expected, _ := json.Marshal(original)
+		assert.JSONEq(t, string(expected), string(data))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, decoded.UnmarshalWith(func(v any) error { return json.Unmarshal(data, v) }))
+		assert.Equal(t, "1234", decoded.Metadata()["orderId"].Value())
+		assert.Nil(t, decoded.DebugInfo())
+	})
+
+	t.Run("Decoding errors are returned", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		assert.ErrorIs(t, decoded.UnmarshalWith(func(any) error { return errors.ErrUnsupported }), errors.ErrUnsupported)
+	})
+}
@@ -0,0 +1,17 @@
+package trogonerror
+
+// ErrorKey identifies a kind of error by its domain and reason. It is comparable,
+// so it can key routing tables, metric label pairs and registries directly.
+type ErrorKey struct {
+	Domain string
+	Reason string
+}
+
+// String formats the key as "domain/reason", as errors render it
+func (k ErrorKey) String() string { return k.Domain + "/" + k.Reason }
+
+// Key returns the domain and reason identifying the error
+func (e TrogonError) Key() ErrorKey { return ErrorKey{Domain: e.domain, Reason: e.reason} }
+
+// Key returns the domain and reason identifying errors created from the template
+func (et *ErrorTemplate) Key() ErrorKey { return ErrorKey{Domain: et.domain, Reason: et.reason} }
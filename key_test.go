@@ -0,0 +1,21 @@
+package trogonerror_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorKey(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.users", "NOT_FOUND",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+	err := template.NewError(trogonerror.WithMessage("user 42 not found"))
+
+	assert.Equal(t, trogonerror.ErrorKey{Domain: "shopify.users", Reason: "NOT_FOUND"}, err.Key())
+	assert.Equal(t, template.Key(), err.Key())
+	assert.Equal(t, "shopify.users/NOT_FOUND", err.Key().String())
+
+	routes := map[trogonerror.ErrorKey]string{template.Key(): "not-found"}
+	assert.Equal(t, "not-found", routes[err.Key()])
+}
@@ -0,0 +1,148 @@
+package trogonerror
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// LocaleFallback returns the locales to look up for a requested locale, most specific first
+type LocaleFallback func(locale string) []string
+
+var (
+	localeFallback atomic.Pointer[LocaleFallback]
+	defaultLocale  atomic.Pointer[string]
+)
+
+// SetLocaleFallback replaces the fallback chain used by MessageFor.
+// Passing nil restores the default, ParentLocales.
+func SetLocaleFallback(fallback LocaleFallback) {
+	if fallback == nil {
+		localeFallback.Store(nil)
+		return
+	}
+	localeFallback.Store(&fallback)
+}
+
+// SetDefaultLocale sets the locale MessageFor tries after the requested locale's fallback chain,
+// before falling back to the error message. Passing an empty string disables it.
+func SetDefaultLocale(locale string) {
+	defaultLocale.Store(&locale)
+}
+
+// ParentLocales is the default LocaleFallback, implementing the RFC 4647 lookup truncation:
+// "zh-Hant-TW" yields zh-Hant-TW, zh-Hant and zh. Single-letter extension subtags are dropped with what follows them.
+func ParentLocales(locale string) []string {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	var locales []string
+	for locale != "" {
+		locales = append(locales, locale)
+
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+		if j := strings.LastIndexByte(locale, '-'); j >= 0 && j == len(locale)-2 {
+			locale = locale[:j]
+		}
+	}
+	return locales
+}
+
+// LocaleChain returns the locales MessageFor tries for locale, most specific first:
+// the configured fallback chain followed by the default locale, if any
+func LocaleChain(locale string) []string {
+	fallback := LocaleFallback(ParentLocales)
+	if configured := localeFallback.Load(); configured != nil {
+		fallback = *configured
+	}
+
+	chain := fallback(locale)
+	if configured := defaultLocale.Load(); configured != nil && *configured != "" {
+		chain = append(chain, *configured)
+	}
+	return chain
+}
+
+// MessageFor returns the localized message best matching locale, walking the configured fallback chain
+// (for example fr-CA, fr, then the default locale) across the attached localized messages.
+// Locales match case-insensitively. Without a match it returns Message, which defaults to the code message.
+func (e TrogonError) MessageFor(locale string) string {
+	messages := e.localizedMessages()
+	for _, candidate := range LocaleChain(locale) {
+		i := slices.IndexFunc(messages, func(m LocalizedMessage) bool {
+			return strings.EqualFold(m.locale, candidate)
+		})
+		if i >= 0 {
+			return messages[i].message
+		}
+	}
+	return e.Message()
+}
+
+func (e TrogonError) localizedMessages() []LocalizedMessage {
+	if e.localizedMessage == nil {
+		return e.translations
+	}
+	return append([]LocalizedMessage{*e.localizedMessage}, e.translations...)
+}
+
+// LocalizedMessages returns the translation set attached with WithLocalizedMessages, sorted by locale.
+// The message set with WithLocalizedMessage is returned by LocalizedMessage and takes precedence in MessageFor.
+func (e TrogonError) LocalizedMessages() []LocalizedMessage { return slices.Clone(e.translations) }
+
+// WithLocalizedMessages attaches a whole translation set, keyed by BCP 47 locale, replacing
+// previously attached messages for the same locales
+func WithLocalizedMessages(messages map[string]string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.translations = mergeTranslations(e.translations, messages)
+	})
+}
+
+// WithChangeLocalizedMessages attaches a translation set to the copy, replacing messages for the same locales
+func WithChangeLocalizedMessages(messages map[string]string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.translations = mergeTranslations(e.translations, messages)
+	})
+}
+
+// TemplateWithLocalizedMessages attaches a translation set to every error created from the template
+func TemplateWithLocalizedMessages(messages map[string]string) TemplateOption {
+	return func(t *ErrorTemplate) {
+		t.translations = mergeTranslations(t.translations, messages)
+	}
+}
+
+// LocalizedMessages returns the translation set attached with TemplateWithLocalizedMessages, sorted by locale
+func (et *ErrorTemplate) LocalizedMessages() []LocalizedMessage { return slices.Clone(et.translations) }
+
+// mergeLocalizedMessageSets unions two translation sets; the first wins for shared locales unless preferSecond
+func mergeLocalizedMessageSets(first, second []LocalizedMessage, preferSecond bool) []LocalizedMessage {
+	if len(second) == 0 {
+		return first
+	}
+	messages := make(map[string]string, len(second))
+	for _, translation := range second {
+		if preferSecond || !slices.ContainsFunc(first, func(m LocalizedMessage) bool { return m.locale == translation.locale }) {
+			messages[translation.locale] = translation.message
+		}
+	}
+	return mergeTranslations(first, messages)
+}
+
+// mergeTranslations returns a new slice sorted by locale, so slices shared with templates
+// and copies are never modified in place
+func mergeTranslations(existing []LocalizedMessage, messages map[string]string) []LocalizedMessage {
+	merged := make([]LocalizedMessage, 0, len(existing)+len(messages))
+	for _, translation := range existing {
+		if _, replaced := messages[translation.locale]; !replaced {
+			merged = append(merged, translation)
+		}
+	}
+	for locale, message := range messages {
+		merged = append(merged, LocalizedMessage{locale: locale, message: message})
+	}
+	slices.SortFunc(merged, func(a, b LocalizedMessage) int { return strings.Compare(a.locale, b.locale) })
+	return merged
+}
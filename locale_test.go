@@ -0,0 +1,94 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentLocales(t *testing.T) {
+	assert.Equal(t, []string{"fr-CA", "fr"}, trogonerror.ParentLocales("fr-CA"))
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh"}, trogonerror.ParentLocales("zh-Hant-TW"))
+	assert.Equal(t, []string{"en-US-x-twain", "en-US", "en"}, trogonerror.ParentLocales("en-US-x-twain"))
+	assert.Equal(t, []string{"pt-BR", "pt"}, trogonerror.ParentLocales("pt_BR"))
+	assert.Empty(t, trogonerror.ParentLocales(""))
+}
+
+func TestMessageFor(t *testing.T) {
+	err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithLocalizedMessage("fr", "Utilisateur introuvable"))
+
+	t.Run("Falls back to the parent locale", func(t *testing.T) {
+		assert.Equal(t, "Utilisateur introuvable", err.MessageFor("fr-CA"))
+		assert.Equal(t, "Utilisateur introuvable", err.MessageFor("FR"))
+	})
+
+	t.Run("Falls back to the code message", func(t *testing.T) {
+		assert.Equal(t, "resource not found", err.MessageFor("de-DE"))
+	})
+
+	t.Run("Falls back to the default locale", func(t *testing.T) {
+		trogonerror.SetDefaultLocale("fr")
+		t.Cleanup(func() { trogonerror.SetDefaultLocale("") })
+
+		assert.Equal(t, "Utilisateur introuvable", err.MessageFor("de-DE"))
+	})
+
+	t.Run("Uses the configured fallback chain", func(t *testing.T) {
+		trogonerror.SetLocaleFallback(func(locale string) []string {
+			return []string{locale, "fr"}
+		})
+		t.Cleanup(func() { trogonerror.SetLocaleFallback(nil) })
+
+		assert.Equal(t, "Utilisateur introuvable", err.MessageFor("it"))
+	})
+}
+
+func TestWithLocalizedMessages(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.users", "NOT_FOUND",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound),
+		trogonerror.TemplateWithLocalizedMessages(map[string]string{
+			"es": "Usuario no encontrado",
+			"fr": "Utilisateur introuvable",
+		}))
+
+	t.Run("MessageFor walks the translation set", func(t *testing.T) {
+		err := template.NewError(trogonerror.WithLocalizedMessages(map[string]string{"de": "Benutzer nicht gefunden"}))
+
+		assert.Equal(t, "Utilisateur introuvable", err.MessageFor("fr-CA"))
+		assert.Equal(t, "Benutzer nicht gefunden", err.MessageFor("de-AT"))
+		assert.Len(t, err.LocalizedMessages(), 3)
+		assert.Equal(t, "de", err.LocalizedMessages()[0].Locale())
+	})
+
+	t.Run("The single localized message takes precedence", func(t *testing.T) {
+		err := template.NewError(trogonerror.WithLocalizedMessage("es", "No existe el usuario"))
+
+		assert.Equal(t, "No existe el usuario", err.MessageFor("es-MX"))
+	})
+
+	t.Run("Change variant replaces the same locales on the copy only", func(t *testing.T) {
+		original := template.NewError()
+		modified := original.WithChanges(trogonerror.WithChangeLocalizedMessages(map[string]string{"fr": "Client introuvable"}))
+
+		assert.Equal(t, "Utilisateur introuvable", original.MessageFor("fr"))
+		assert.Equal(t, "Client introuvable", modified.MessageFor("fr"))
+		assert.Equal(t, "Utilisateur introuvable", template.NewError().MessageFor("fr"))
+	})
+
+	t.Run("Translations survive a JSON round-trip", func(t *testing.T) {
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal)
+		err := template.NewError()
+
+		data, marshalErr := err.MarshalJSONFor(policy)
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, trogonerror.ValidatePayload(data))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, "", trogonerror.Diff(err, &decoded))
+	})
+}
@@ -0,0 +1,63 @@
+package trogonerror
+
+import "errors"
+
+// IsInChain reports whether any TrogonError reachable from err matches target, which can be a
+// TrogonError or an ErrorTemplate. Unlike errors.Is, it also inspects the causes attached with
+// WithCause, recursively, for services that attach upstream errors as causes rather than wrapping them.
+func IsInChain(err error, target trogonError) bool {
+	_, ok := AsInChain(err, target)
+	return ok
+}
+
+// AsInChain returns the first TrogonError reachable from err matching target, searching err's chain
+// depth first and, for every TrogonError in it, its causes before the error it wraps
+func AsInChain(err error, target trogonError) (*TrogonError, bool) {
+	var found *TrogonError
+	walkChain(err, func(e *TrogonError) bool {
+		if target.Is(e) {
+			found = e
+			return true
+		}
+		return false
+	})
+	return found, found != nil
+}
+
+// walkChain calls visit for every TrogonError reachable from err through wrapping, joined errors and causes,
+// stopping as soon as visit returns true
+func walkChain(err error, visit func(*TrogonError) bool) bool {
+	for err != nil {
+		var trogonErr *TrogonError
+		switch e := err.(type) {
+		case *TrogonError:
+			if e == nil {
+				return false
+			}
+			trogonErr = e
+		case TrogonError:
+			trogonErr = &e
+		}
+		if trogonErr != nil {
+			if visit(trogonErr) {
+				return true
+			}
+			for _, cause := range trogonErr.causes {
+				if cause != nil && walkChain(cause, visit) {
+					return true
+				}
+			}
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, inner := range joined.Unwrap() {
+				if walkChain(inner, visit) {
+					return true
+				}
+			}
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
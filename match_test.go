@@ -0,0 +1,41 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInChain(t *testing.T) {
+	connectionReset := trogonerror.NewErrorTemplate("shopify.network", "CONNECTION_RESET")
+	upstream := connectionReset.NewError(trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "host", "payments.internal"))
+	err := fmt.Errorf("checkout: %w", trogonerror.NewError("shopify.payments", "CHARGE_FAILED",
+		trogonerror.WithCause(trogonerror.NewError("shopify.payments", "GATEWAY_ERROR", trogonerror.WithCause(upstream)))))
+
+	t.Run("Matches nested causes", func(t *testing.T) {
+		assert.False(t, errors.Is(err, upstream))
+		assert.True(t, trogonerror.IsInChain(err, upstream))
+		assert.True(t, trogonerror.IsInChain(err, connectionReset))
+
+		found, ok := trogonerror.AsInChain(err, connectionReset)
+		assert.True(t, ok)
+		assert.Same(t, upstream, found)
+	})
+
+	t.Run("Joined and wrapped errors", func(t *testing.T) {
+		joined := errors.Join(io.EOF, trogonerror.NewError("shopify.orders", "SYNC_FAILED",
+			trogonerror.WithWrap(fmt.Errorf("sync: %w", upstream))))
+
+		assert.True(t, trogonerror.IsInChain(joined, connectionReset))
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		assert.False(t, trogonerror.IsInChain(err, trogonerror.NewErrorTemplate("shopify.orders", "ORDER_NOT_FOUND")))
+		assert.False(t, trogonerror.IsInChain(nil, connectionReset))
+		assert.False(t, trogonerror.IsInChain((*trogonerror.TrogonError)(nil), connectionReset))
+	})
+}
@@ -0,0 +1,116 @@
+package trogonerror
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrMergeConflict reports metadata set to different values by both errors given to Merge with MergeReject
+var ErrMergeConflict = errors.New("trogonerror: merge conflict")
+
+// MergePolicy decides how Merge resolves a metadata key set to different values, or with different
+// visibilities, by both errors, and which of two set optional fields is kept
+type MergePolicy int
+
+const (
+	// MergeKeepFirst keeps the value of the first error
+	MergeKeepFirst MergePolicy = iota
+	// MergeKeepSecond keeps the value of the second error, such as the most recent attempt
+	MergeKeepSecond
+	// MergeReject fails the merge with ErrMergeConflict on the first conflicting metadata key
+	MergeReject
+)
+
+// Merge combines the context of two errors describing the same failure, such as the original
+// attempt and its retry. The result is a copy of a, keeping its code, message, domain, reason, ID
+// and visibility, with:
+//   - the metadata of both errors, conflicts being resolved by policy;
+//   - the help links of a followed by those of b not already present;
+//   - the causes of a followed by those of b not already present;
+//   - the tags and operations of both;
+//   - the subject, owner, retry info, localized message and debug info of b where a has none,
+//     or where both are set and policy is MergeKeepSecond.
+//
+// Neither error is modified. When one of them is nil, the other is returned.
+func Merge(a, b *TrogonError, policy MergePolicy) (*TrogonError, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b == nil {
+		return a, nil
+	}
+
+	merged := a.copy()
+
+	for key, value := range b.metadata {
+		existing, ok := merged.metadata[key]
+		if ok && existing != value {
+			switch policy {
+			case MergeKeepFirst:
+				continue
+			case MergeReject:
+				return nil, fmt.Errorf("%w: metadata %q is %q (%s) and %q (%s)", ErrMergeConflict, key,
+					existing.value, existing.visibility, value.value, value.visibility)
+			}
+		}
+		if merged.metadata == nil {
+			merged.metadata = make(Metadata, len(b.metadata))
+		}
+		merged.metadata[key] = value
+	}
+
+	if b.help != nil {
+		if merged.help == nil {
+			merged.help = &Help{}
+		}
+		for _, link := range b.help.links {
+			if !slices.Contains(merged.help.links, link) {
+				merged.help.links = append(merged.help.links, link)
+			}
+		}
+	}
+
+	for _, cause := range b.causes {
+		if !slices.Contains(merged.causes, cause) {
+			merged.causes = append(merged.causes, cause)
+		}
+	}
+
+	merged.tags = addTags(merged.tags, b.tags)
+	for _, operation := range b.operations {
+		if !slices.Contains(merged.operations, operation) {
+			merged.operations = appendOperation(merged.operations, operation)
+		}
+	}
+
+	preferSecond := policy == MergeKeepSecond
+	if b.subject != "" && (merged.subject == "" || preferSecond) {
+		merged.subject = b.subject
+	}
+	if b.owner != "" && (merged.owner == "" || preferSecond) {
+		merged.owner = b.owner
+	}
+	if b.retryInfo != nil && (merged.retryInfo == nil || preferSecond) {
+		merged.retryInfo = b.retryInfo
+	}
+	if b.rateLimitInfo != nil && (merged.rateLimitInfo == nil || preferSecond) {
+		merged.rateLimitInfo = b.rateLimitInfo
+	}
+	if b.deadlineInfo != nil && (merged.deadlineInfo == nil || preferSecond) {
+		merged.deadlineInfo = b.deadlineInfo
+	}
+	if b.authInfo != nil && (merged.authInfo == nil || preferSecond) {
+		merged.authInfo = b.authInfo
+	}
+	if b.localizedMessage != nil && (merged.localizedMessage == nil || preferSecond) {
+		merged.localizedMessage = b.localizedMessage
+	}
+	merged.translations = mergeLocalizedMessageSets(merged.translations, b.translations, preferSecond)
+	if b.debugInfo != nil && (merged.debugInfo == nil || preferSecond) {
+		debugInfo := b.debugInfo.copy()
+		merged.debugInfo = &debugInfo
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,71 @@
+package trogonerror_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	original := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT",
+		trogonerror.WithCode(trogonerror.CodeUnavailable),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "gateway", "stripe"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "attempt", "1"),
+		trogonerror.WithHelpLink("Status page", "https://status.shopify.com"),
+		trogonerror.WithTags("payments"))
+	retried := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT",
+		trogonerror.WithCode(trogonerror.CodeUnavailable),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "attempt", "2"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "region", "us-east-1"),
+		trogonerror.WithHelpLink("Status page", "https://status.shopify.com"),
+		trogonerror.WithRunbookLink("Gateway runbook", "https://runbooks.shopify.com/gateway"),
+		trogonerror.WithRetryInfoDuration(5*time.Second),
+		trogonerror.WithTags("gateway"))
+
+	t.Run("Keep first", func(t *testing.T) {
+		merged, err := trogonerror.Merge(original, retried, trogonerror.MergeKeepFirst)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1", merged.Metadata()["attempt"].Value())
+		assert.Equal(t, "us-east-1", merged.Metadata()["region"].Value())
+		assert.Equal(t, "stripe", merged.Metadata()["gateway"].Value())
+		assert.Len(t, merged.Help().Links(), 2)
+		assert.Equal(t, []string{"gateway", "payments"}, merged.Tags())
+		assert.NotNil(t, merged.RetryInfo())
+		assert.Len(t, original.Metadata(), 2)
+	})
+
+	t.Run("Keep second", func(t *testing.T) {
+		merged, err := trogonerror.Merge(original, retried, trogonerror.MergeKeepSecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2", merged.Metadata()["attempt"].Value())
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		merged, err := trogonerror.Merge(original, retried, trogonerror.MergeReject)
+
+		assert.ErrorIs(t, err, trogonerror.ErrMergeConflict)
+		assert.Nil(t, merged)
+	})
+
+	t.Run("Causes are not duplicated", func(t *testing.T) {
+		cause := trogonerror.NewError("shopify.network", "CONNECTION_RESET")
+		a := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT", trogonerror.WithCause(cause))
+		b := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT", trogonerror.WithCause(cause))
+
+		merged, err := trogonerror.Merge(a, b, trogonerror.MergeReject)
+
+		assert.NoError(t, err)
+		assert.Len(t, merged.Causes(), 1)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		merged, err := trogonerror.Merge(nil, retried, trogonerror.MergeKeepFirst)
+
+		assert.NoError(t, err)
+		assert.Same(t, retried, merged)
+	})
+}
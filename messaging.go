@@ -0,0 +1,82 @@
+package trogonerror
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Message header names written by EncodeHeaders. Only HeaderError is needed to decode the error;
+// the others carry the identifying fields as plain strings so brokers, stream processors and
+// dead-letter tooling can filter and route without parsing JSON.
+const (
+	HeaderError  = "trogon-error"
+	HeaderDomain = "trogon-error-domain"
+	HeaderReason = "trogon-error-reason"
+	HeaderCode   = "trogon-error-code"
+	HeaderID     = "trogon-error-id"
+)
+
+// ErrNoErrorHeaders is returned by DecodeHeaders when the headers carry no error
+var ErrNoErrorHeaders = errors.New("trogonerror: no error headers")
+
+// EncodePayload serializes the error into a compact JSON payload for async pipelines such as
+// Kafka or NATS, applying policy (DefaultSerializationPolicy when nil)
+func EncodePayload(err *TrogonError, policy *SerializationPolicy) ([]byte, error) {
+	if policy == nil {
+		policy = DefaultSerializationPolicy()
+	}
+	return json.Marshal(err.toJSON(policy))
+}
+
+// DecodePayload reconstructs an error serialized by EncodePayload
+func DecodePayload(data []byte) (*TrogonError, error) {
+	var err TrogonError
+	if decodeErr := json.Unmarshal(data, &err); decodeErr != nil {
+		return nil, decodeErr
+	}
+	return &err, nil
+}
+
+// EncodeHeaders serializes the error into message headers, e.g. when dead-lettering a message.
+// Header values are strings; Kafka clients take them as []byte and NATS as nats.Header values.
+//
+//	headers, _ := trogonerror.EncodeHeaders(err, nil)
+//	for k, v := range headers {
+//		msg.Headers = append(msg.Headers, kgo.RecordHeader{Key: k, Value: []byte(v)})
+//	}
+func EncodeHeaders(err *TrogonError, policy *SerializationPolicy) (map[string]string, error) {
+	payload, encodeErr := EncodePayload(err, policy)
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+
+	headers := map[string]string{
+		HeaderError:  string(payload),
+		HeaderDomain: err.domain,
+		HeaderReason: err.reason,
+		HeaderCode:   err.code.String(),
+	}
+	if err.id != "" {
+		headers[HeaderID] = err.id
+	}
+	return headers, nil
+}
+
+// DecodeHeaders reconstructs an error from headers written by EncodeHeaders,
+// returning ErrNoErrorHeaders when the headers carry no error.
+// Without HeaderError, e.g. when a transport dropped it for size, the error is rebuilt
+// from the routing headers alone.
+func DecodeHeaders(headers map[string]string) (*TrogonError, error) {
+	if payload, ok := headers[HeaderError]; ok {
+		return DecodePayload([]byte(payload))
+	}
+	if headers[HeaderDomain] == "" || headers[HeaderReason] == "" {
+		return nil, ErrNoErrorHeaders
+	}
+	return fromJSON(jsonError{
+		Domain: headers[HeaderDomain],
+		Reason: headers[HeaderReason],
+		Code:   headers[HeaderCode],
+		ID:     headers[HeaderID],
+	})
+}
@@ -0,0 +1,78 @@
+package trogonerror_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeHeaders(t *testing.T) {
+	original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithID("err_123"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+		trogonerror.WithDebugDetail("lookup failed"))
+
+	t.Run("Routing headers", func(t *testing.T) {
+		headers, err := trogonerror.EncodeHeaders(original, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "shopify.orders", headers[trogonerror.HeaderDomain])
+		assert.Equal(t, "ORDER_NOT_FOUND", headers[trogonerror.HeaderReason])
+		assert.Equal(t, "NOT_FOUND", headers[trogonerror.HeaderCode])
+		assert.Equal(t, "err_123", headers[trogonerror.HeaderID])
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		headers, err := trogonerror.EncodeHeaders(original, trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, err)
+
+		decoded, err := trogonerror.DecodeHeaders(headers)
+
+		assert.NoError(t, err)
+		assert.True(t, trogonerror.Equal(original, decoded, trogonerror.CompareIgnoringStackTrace()))
+		assert.Equal(t, "lookup failed", decoded.DebugInfo().Detail())
+	})
+
+	t.Run("Policy strips debug info", func(t *testing.T) {
+		headers, _ := trogonerror.EncodeHeaders(original, nil)
+
+		decoded, err := trogonerror.DecodeHeaders(headers)
+
+		assert.NoError(t, err)
+		assert.Nil(t, decoded.DebugInfo())
+	})
+
+	t.Run("Routing headers only", func(t *testing.T) {
+		decoded, err := trogonerror.DecodeHeaders(map[string]string{
+			trogonerror.HeaderDomain: "shopify.orders",
+			trogonerror.HeaderReason: "ORDER_NOT_FOUND",
+			trogonerror.HeaderCode:   "NOT_FOUND",
+			trogonerror.HeaderID:     "err_123",
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, trogonerror.Equal(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound), trogonerror.WithID("err_123")), decoded, trogonerror.CompareIgnoringTime()))
+	})
+
+	t.Run("Missing headers", func(t *testing.T) {
+		_, err := trogonerror.DecodeHeaders(map[string]string{"content-type": "application/json"})
+		assert.ErrorIs(t, err, trogonerror.ErrNoErrorHeaders)
+	})
+}
+
+func TestEncodePayload(t *testing.T) {
+	original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithCode(trogonerror.CodeNotFound))
+
+	payload, err := trogonerror.EncodePayload(original, nil)
+	assert.NoError(t, err)
+
+	decoded, err := trogonerror.DecodePayload(payload)
+	assert.NoError(t, err)
+	assert.True(t, trogonerror.Equal(original, decoded))
+
+	_, err = trogonerror.DecodePayload([]byte("not json"))
+	assert.Error(t, err)
+}
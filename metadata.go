@@ -0,0 +1,141 @@
+package trogonerror
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxMetadataValueLength bounds the length in bytes of a single metadata value
+	DefaultMaxMetadataValueLength = 8 << 10
+	// DefaultMaxMetadataSize bounds the combined length in bytes of all metadata keys and values
+	DefaultMaxMetadataSize = 64 << 10
+
+	// ReservedMetadataPrefix namespaces metadata keys populated by the library itself.
+	// Options given by application code cannot set keys under it.
+	ReservedMetadataPrefix = "trogon."
+
+	// MetadataTruncatedKey lists, comma separated, the metadata keys whose values were truncated
+	MetadataTruncatedKey = ReservedMetadataPrefix + "truncated"
+	// MetadataDeadlineRemainingKey holds the time left before the context deadline, recorded by WithContext
+	MetadataDeadlineRemainingKey = ReservedMetadataPrefix + "deadlineRemaining"
+	// MetadataRequestIDKey holds the ID of the request the error was created in, recorded by WithContext
+	MetadataRequestIDKey = ReservedMetadataPrefix + "requestId"
+	// MetadataCausesOmittedKey holds the number of causes left out by the serialization policy's limits,
+	// visible to the policy's audience but never more visible than the error
+	MetadataCausesOmittedKey = ReservedMetadataPrefix + "causesOmitted"
+
+	truncationMarker = "…"
+)
+
+var (
+	maxMetadataValueLength atomic.Int64
+	maxMetadataSize        atomic.Int64
+)
+
+func init() {
+	SetMetadataLimits(DefaultMaxMetadataValueLength, DefaultMaxMetadataSize)
+}
+
+// SetMetadataLimits bounds the length of each metadata value and the combined size of all
+// metadata, in bytes. Values over a limit are truncated with an ellipsis and their keys listed
+// under the internal MetadataTruncatedKey entry. A limit of zero or less disables it.
+func SetMetadataLimits(maxValueLength, maxTotalSize int) {
+	maxMetadataValueLength.Store(int64(maxValueLength))
+	maxMetadataSize.Store(int64(maxTotalSize))
+}
+
+// IsReservedMetadataKey reports whether key belongs to the library's reserved namespace
+func IsReservedMetadataKey(key string) bool {
+	return strings.HasPrefix(key, ReservedMetadataPrefix)
+}
+
+// addMetadataValue sets a metadata entry on behalf of application code, ignoring reserved keys
+func addMetadataValue(e *TrogonError, visibility Visibility, key, value string) {
+	if IsReservedMetadataKey(key) {
+		return
+	}
+	setMetadataValue(e, visibility, key, value)
+}
+
+// setMetadataValue sets a metadata entry on behalf of the library
+func setMetadataValue(e *TrogonError, visibility Visibility, key, value string) {
+	if len(e.metadata) == 0 {
+		e.metadata = make(Metadata)
+	}
+	e.metadata[key] = MetadataValue{value: value, visibility: visibility}
+}
+
+// copyUserMetadata copies the entries of src outside the reserved namespace into dst
+func copyUserMetadata(dst, src Metadata) {
+	for key, value := range src {
+		if !IsReservedMetadataKey(key) {
+			dst[key] = value
+		}
+	}
+}
+
+func applyMetadataLimits(e *TrogonError) {
+	if len(e.metadata) == 0 {
+		return
+	}
+
+	maxValue, maxTotal := int(maxMetadataValueLength.Load()), int(maxMetadataSize.Load())
+	var truncated []string
+
+	total := 0
+	for key, value := range e.metadata {
+		if key == MetadataTruncatedKey {
+			continue
+		}
+		if maxValue > 0 && len(value.value) > maxValue {
+			value.value = truncateValue(value.value, maxValue)
+			e.metadata[key] = value
+			truncated = append(truncated, key)
+		}
+		total += len(key) + len(value.value)
+	}
+
+	if maxTotal > 0 && total > maxTotal {
+		// Walk keys in a stable order so the same input always keeps the same values
+		remaining := maxTotal
+		for _, key := range slices.Sorted(maps.Keys(e.metadata)) {
+			if key == MetadataTruncatedKey {
+				continue
+			}
+			value := e.metadata[key]
+			budget := max(remaining-len(key), 0)
+			if len(value.value) > budget {
+				value.value = truncateValue(value.value, budget)
+				e.metadata[key] = value
+				if !slices.Contains(truncated, key) {
+					truncated = append(truncated, key)
+				}
+			}
+			remaining = max(remaining-len(key)-len(value.value), 0)
+		}
+	}
+
+	if len(truncated) > 0 {
+		if previous, ok := e.metadata[MetadataTruncatedKey]; ok && previous.value != "" {
+			truncated = append(truncated, strings.Split(previous.value, ",")...)
+		}
+		slices.Sort(truncated)
+		e.metadata[MetadataTruncatedKey] = MetadataValue{
+			value:      strings.Join(slices.Compact(truncated), ","),
+			visibility: VisibilityInternal,
+		}
+	}
+}
+
+// truncateValue cuts value to at most limit bytes, marker included, without splitting a rune
+func truncateValue(value string, limit int) string {
+	cut := max(limit-len(truncationMarker), 0)
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + truncationMarker
+}
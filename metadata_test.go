@@ -0,0 +1,163 @@
+package trogonerror_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataLimits(t *testing.T) {
+	t.Run("Default limits truncate oversized values", func(t *testing.T) {
+		statement := "SELECT " + strings.Repeat("x", 2<<20)
+
+		err := trogonerror.NewError("shopify.database", "QUERY_FAILED",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "statement", statement))
+
+		value := err.Metadata()["statement"].Value()
+		assert.Len(t, value, trogonerror.DefaultMaxMetadataValueLength)
+		assert.True(t, strings.HasSuffix(value, "…"))
+		assert.Equal(t, "statement", err.Metadata()[trogonerror.MetadataTruncatedKey].Value())
+		assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()[trogonerror.MetadataTruncatedKey].Visibility())
+	})
+
+	t.Run("Values within limits are untouched", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"))
+
+		assert.Equal(t, "gid://shopify/Order/1", err.Metadata()["orderId"].Value())
+		assert.NotContains(t, err.Metadata(), trogonerror.MetadataTruncatedKey)
+	})
+
+	t.Run("Total size truncates in key order", func(t *testing.T) {
+		trogonerror.SetMetadataLimits(0, 20)
+		t.Cleanup(func() {
+			trogonerror.SetMetadataLimits(trogonerror.DefaultMaxMetadataValueLength, trogonerror.DefaultMaxMetadataSize)
+		})
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_INVALID",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "a", "0123456789"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "b", "0123456789"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "c", "0123456789"))
+
+		assert.Equal(t, "0123456789", err.Metadata()["a"].Value())
+		assert.Equal(t, "01234…", err.Metadata()["b"].Value())
+		assert.Equal(t, "…", err.Metadata()["c"].Value())
+		assert.Equal(t, "b,c", err.Metadata()[trogonerror.MetadataTruncatedKey].Value())
+	})
+
+	t.Run("Truncation never splits a rune", func(t *testing.T) {
+		trogonerror.SetMetadataLimits(8, 0)
+		t.Cleanup(func() {
+			trogonerror.SetMetadataLimits(trogonerror.DefaultMaxMetadataValueLength, trogonerror.DefaultMaxMetadataSize)
+		})
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_INVALID",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "note", "ééééé"))
+
+		assert.Equal(t, "éé…", err.Metadata()["note"].Value())
+	})
+
+	t.Run("Applies to WithChanges and accumulates truncated keys", func(t *testing.T) {
+		trogonerror.SetMetadataLimits(4, 0)
+		t.Cleanup(func() {
+			trogonerror.SetMetadataLimits(trogonerror.DefaultMaxMetadataValueLength, trogonerror.DefaultMaxMetadataSize)
+		})
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_INVALID",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "first", "long value"))
+		changed := err.WithChanges(trogonerror.WithChangeMetadataValue(trogonerror.VisibilityPublic, "second", "long value"))
+
+		assert.Equal(t, "l…", changed.Metadata()["second"].Value())
+		assert.Equal(t, "first,second", changed.Metadata()[trogonerror.MetadataTruncatedKey].Value())
+	})
+
+	t.Run("Non-positive limits disable truncation", func(t *testing.T) {
+		trogonerror.SetMetadataLimits(0, 0)
+		t.Cleanup(func() {
+			trogonerror.SetMetadataLimits(trogonerror.DefaultMaxMetadataValueLength, trogonerror.DefaultMaxMetadataSize)
+		})
+
+		statement := strings.Repeat("x", 2<<20)
+		err := trogonerror.NewError("shopify.database", "QUERY_FAILED",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "statement", statement))
+
+		assert.Equal(t, statement, err.Metadata()["statement"].Value())
+	})
+}
+
+func TestReservedMetadataKeys(t *testing.T) {
+	t.Run("User options cannot set reserved keys", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_INVALID",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "trogon.hops", "0"),
+			trogonerror.WithMetadataValuef(trogonerror.VisibilityPublic, "trogon.traceId", "%s", "abc"),
+			trogonerror.WithMetadata(trogonerror.Metadata{
+				"trogon.truncated": trogonerror.MetadataValue{},
+			}),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"))
+
+		assert.Len(t, err.Metadata(), 1)
+		assert.Equal(t, "1", err.Metadata()["orderId"].Value())
+	})
+
+	t.Run("Library-managed keys survive metadata replacement", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_INVALID",
+			trogonerror.WithContext(ctx),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"))
+
+		changed := err.WithChanges(
+			trogonerror.WithChangeMetadata(trogonerror.Metadata{}),
+			trogonerror.WithChangeMetadataValue(trogonerror.VisibilityPublic, trogonerror.MetadataDeadlineRemainingKey, "0s"))
+
+		assert.NotContains(t, changed.Metadata(), "orderId")
+		assert.Equal(t, err.Metadata()[trogonerror.MetadataDeadlineRemainingKey], changed.Metadata()[trogonerror.MetadataDeadlineRemainingKey])
+	})
+
+	t.Run("IsReservedMetadataKey", func(t *testing.T) {
+		assert.True(t, trogonerror.IsReservedMetadataKey("trogon.truncated"))
+		assert.False(t, trogonerror.IsReservedMetadataKey("trogonId"))
+	})
+}
+
+func TestMetadataAccessors(t *testing.T) {
+	err := trogonerror.NewError("shopify.orders", "ORDER_INVALID",
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shopId", "2"))
+
+	t.Run("Metadata returns a copy", func(t *testing.T) {
+		metadata := err.Metadata()
+		metadata["orderId"] = trogonerror.MetadataValue{}
+		delete(metadata, "shopId")
+
+		assert.Equal(t, "1", err.Metadata()["orderId"].Value())
+		assert.Len(t, err.Metadata(), 2)
+	})
+
+	t.Run("AllMetadata iterates without copying", func(t *testing.T) {
+		seen := map[string]string{}
+		for key, value := range err.AllMetadata() {
+			seen[key] = value.Value()
+		}
+
+		assert.Equal(t, map[string]string{"orderId": "1", "shopId": "2"}, seen)
+		assert.Zero(t, testing.AllocsPerRun(100, func() {
+			for range err.AllMetadata() {
+			}
+		}))
+	})
+
+	t.Run("LookupMetadata", func(t *testing.T) {
+		value, ok := err.LookupMetadata("shopId")
+		assert.True(t, ok)
+		assert.Equal(t, trogonerror.VisibilityInternal, value.Visibility())
+
+		_, ok = err.LookupMetadata("missing")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,50 @@
+package trogonerror
+
+import (
+	"errors"
+	"slices"
+)
+
+// WithOperation records the logical operation that failed, e.g. "users.GetUser".
+// Each layer adds its own operation as the error travels up the stack, building a stable,
+// human-meaningful call path that complements stack traces:
+//
+//	return err.WithChanges(trogonerror.WithChangeOperation("api.HandleGetUser"))
+func WithOperation(operation string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.operations = appendOperation(e.operations, operation)
+	})
+}
+
+// WithChangeOperation records the operation of the layer returning the copy
+func WithChangeOperation(operation string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.operations = appendOperation(e.operations, operation)
+	})
+}
+
+// Operations returns the logical call path, outermost operation first.
+// Operations of a TrogonError wrapped with WithWrap follow the error's own.
+func (e TrogonError) Operations() []string {
+	operations := make([]string, 0, len(e.operations))
+	for _, operation := range slices.Backward(e.operations) {
+		operations = append(operations, operation)
+	}
+
+	var wrapped *TrogonError
+	if e.wrappedErr != nil && errors.As(e.wrappedErr, &wrapped) {
+		operations = append(operations, wrapped.Operations()...)
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+	return operations
+}
+
+// appendOperation never writes into a slice shared with a copy of the error
+func appendOperation(operations []string, operation string) []string {
+	if operation == "" {
+		return operations
+	}
+	return append(slices.Clip(operations), operation)
+}
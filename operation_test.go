@@ -0,0 +1,61 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperations(t *testing.T) {
+	getUser := func() *trogonerror.TrogonError {
+		return trogonerror.NewError("shopify.users", "USER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithOperation("db.QueryUser"))
+	}
+
+	t.Run("Accumulates the call path outermost first", func(t *testing.T) {
+		inner := getUser().WithChanges(trogonerror.WithChangeOperation("users.GetUser"))
+		outer := inner.WithChanges(trogonerror.WithChangeOperation("api.HandleGetUser"))
+
+		assert.Equal(t, []string{"users.GetUser", "db.QueryUser"}, inner.Operations())
+		assert.Equal(t, []string{"api.HandleGetUser", "users.GetUser", "db.QueryUser"}, outer.Operations())
+	})
+
+	t.Run("Copies never share their path", func(t *testing.T) {
+		base := getUser().WithChanges(trogonerror.WithChangeOperation("users.GetUser"))
+
+		first := base.WithChanges(trogonerror.WithChangeOperation("api.HandleGetUser"))
+		second := base.WithChanges(trogonerror.WithChangeOperation("jobs.SyncUser"))
+
+		assert.Equal(t, "api.HandleGetUser", first.Operations()[0])
+		assert.Equal(t, "jobs.SyncUser", second.Operations()[0])
+	})
+
+	t.Run("Includes wrapped errors", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.profiles", "PROFILE_UNAVAILABLE",
+			trogonerror.WithOperation("profiles.Render"),
+			trogonerror.WithWrap(getUser()))
+
+		assert.Equal(t, []string{"profiles.Render", "db.QueryUser"}, err.Operations())
+		assert.Contains(t, err.Error(), "\n  operations: profiles.Render > db.QueryUser")
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		err := getUser().WithChanges(trogonerror.WithChangeOperation("users.GetUser"))
+
+		data, marshalErr := json.Marshal(err)
+		var decoded trogonerror.TrogonError
+		unmarshalErr := json.Unmarshal(data, &decoded)
+
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, unmarshalErr)
+		assert.Contains(t, string(data), `"operations":["users.GetUser","db.QueryUser"]`)
+		assert.Equal(t, err.Operations(), decoded.Operations())
+	})
+
+	t.Run("None", func(t *testing.T) {
+		assert.Nil(t, trogonerror.NewError("shopify.users", "USER_NOT_FOUND").Operations())
+	})
+}
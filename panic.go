@@ -0,0 +1,81 @@
+package trogonerror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ErrPanic is the template used by FromPanic for recovered panics
+var ErrPanic = NewErrorTemplate("trogon.runtime", "PANIC",
+	TemplateWithCode(CodeInternal))
+
+// FromPanic converts a value returned by recover() into a CodeInternal error.
+// The panic value is recorded as debug detail, a stack trace is captured at the recovery point,
+// and recovered errors are wrapped so errors.Is keeps working.
+//
+// Example usage:
+//
+//	defer func() {
+//	    if r := recover(); r != nil {
+//	        err = trogonerror.FromPanic(r)
+//	    }
+//	}()
+func FromPanic(recovered any, options ...ErrorOption) *TrogonError {
+	return fromPanic(recovered, 4, options)
+}
+
+// RecoverTo recovers from a panic and stores the converted error in errp.
+// It must be deferred directly, which makes it usable from gRPC interceptors and worker loops:
+//
+//	func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+//	    defer trogonerror.RecoverTo(&err)
+//	    return handler(ctx, req)
+//	}
+func RecoverTo(errp *error) {
+	if recovered := recover(); recovered != nil {
+		*errp = fromPanic(recovered, 4, nil)
+	}
+}
+
+// PanicHandler renders the error produced from a recovered HTTP handler panic
+type PanicHandler func(w http.ResponseWriter, r *http.Request, err *TrogonError)
+
+// RecoverHTTP returns middleware that converts handler panics into errors rendered by handle.
+// A nil handle writes the error message with the error's StatusCode.
+// The error carries the request ID of the request context, as WithContext records it.
+// http.ErrAbortHandler is re-panicked so the server can abort the response as usual.
+func RecoverHTTP(next http.Handler, handle PanicHandler) http.Handler {
+	if handle == nil {
+		handle = func(w http.ResponseWriter, _ *http.Request, err *TrogonError) {
+			http.Error(w, err.Message(), err.StatusCode())
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			handle(w, r, fromPanic(recovered, 4, []ErrorOption{withRequestID(r.Context())}))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func fromPanic(recovered any, skip int, options []ErrorOption) *TrogonError {
+	baseOptions := []ErrorOption{
+		WithDebugDetail(fmt.Sprintf("panic: %v", recovered)),
+	}
+	if err, ok := recovered.(error); ok {
+		baseOptions = append(baseOptions, WithWrap(err))
+	}
+
+	trogonErr := ErrPanic.NewError(append(baseOptions, options...)...)
+	setStackFrames(trogonErr, captureStackTrace(skip, 32))
+	return trogonErr
+}
@@ -0,0 +1,117 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func panickingOrderLookup() {
+	panic("nil order repository")
+}
+
+func TestFromPanic(t *testing.T) {
+	t.Run("FromPanic produces an internal error with detail and stack", func(t *testing.T) {
+		var err *trogonerror.TrogonError
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = trogonerror.FromPanic(r)
+				}
+			}()
+			panickingOrderLookup()
+		}()
+
+		assert.NotNil(t, err)
+		assert.Equal(t, trogonerror.CodeInternal, err.Code())
+		assert.True(t, trogonerror.ErrPanic.Is(err))
+		assert.Equal(t, "panic: nil order repository", err.DebugInfo().Detail())
+		assert.True(t, stackContains(err, "panickingOrderLookup"), "stack should include the panicking function")
+	})
+
+	t.Run("FromPanic wraps recovered errors", func(t *testing.T) {
+		cause := errors.New("index out of range")
+
+		err := trogonerror.FromPanic(cause,
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "worker", "billing-7"))
+
+		assert.True(t, errors.Is(err, cause))
+		assert.Equal(t, "billing-7", err.Metadata()["worker"].Value())
+	})
+
+	t.Run("RecoverTo stores the converted panic", func(t *testing.T) {
+		call := func() (err error) {
+			defer trogonerror.RecoverTo(&err)
+			panickingOrderLookup()
+			return nil
+		}
+
+		err := call()
+
+		trogonErr, ok := trogonerror.As(err, trogonerror.ErrPanic)
+		assert.True(t, ok)
+		assert.True(t, stackContains(trogonErr, "panickingOrderLookup"))
+	})
+
+	t.Run("RecoverTo leaves the error untouched without a panic", func(t *testing.T) {
+		expected := errors.New("regular failure")
+		call := func() (err error) {
+			defer trogonerror.RecoverTo(&err)
+			return expected
+		}
+
+		assert.Same(t, expected, call())
+	})
+}
+
+func TestRecoverHTTP(t *testing.T) {
+	panicking := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panickingOrderLookup()
+	})
+
+	t.Run("Default handler writes the status derived from the code", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		trogonerror.RecoverHTTP(panicking, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "internal error\n", rec.Body.String())
+	})
+
+	t.Run("Custom handler receives the converted error", func(t *testing.T) {
+		var captured *trogonerror.TrogonError
+		handler := trogonerror.RecoverHTTP(panicking, func(w http.ResponseWriter, r *http.Request, err *trogonerror.TrogonError) {
+			captured = err
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "panic: nil order repository", captured.DebugInfo().Detail())
+	})
+
+	t.Run("http.ErrAbortHandler is re-panicked", func(t *testing.T) {
+		aborting := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			trogonerror.RecoverHTTP(aborting, nil).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+}
+
+func stackContains(err *trogonerror.TrogonError, function string) bool {
+	for _, entry := range err.DebugInfo().StackEntries() {
+		if strings.Contains(entry, function) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package trogonerror
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+)
+
+// SerializationPolicy decides which parts of an error may leave the process for a given audience.
+// Debug info (stack traces, goroutine dumps, source snippets) and wrapped error text are only
+// kept for an internal audience or over a channel explicitly marked as trusted.
+// Encoders share a policy instead of each exposing their own flags.
+type SerializationPolicy struct {
+	audience         Visibility
+	trusted          bool
+	filterVisibility bool
+	maxCauses        int
+	maxCauseDepth    int
+}
+
+// SerializationPolicyOption represents options for serialization policy construction
+type SerializationPolicyOption func(*SerializationPolicy)
+
+// NewSerializationPolicy creates a policy for serializing errors destined to audience
+func NewSerializationPolicy(audience Visibility, options ...SerializationPolicyOption) *SerializationPolicy {
+	policy := &SerializationPolicy{audience: audience}
+
+	for _, option := range options {
+		option(policy)
+	}
+
+	return policy
+}
+
+// SerializationPolicyWithTrustedChannel keeps debug info regardless of the audience,
+// for channels such as an internal log pipeline or an admin-only endpoint
+func SerializationPolicyWithTrustedChannel() SerializationPolicyOption {
+	return func(p *SerializationPolicy) {
+		p.trusted = true
+	}
+}
+
+// SerializationPolicyWithVisibilityFiltering drops metadata entries less visible than the audience,
+// for transports whose consumers sit outside the producer's trust boundary
+func SerializationPolicyWithVisibilityFiltering() SerializationPolicyOption {
+	return func(p *SerializationPolicy) {
+		p.filterVisibility = true
+	}
+}
+
+// SerializationPolicyWithMaxCauses keeps at most n causes per error. The omitted causes, counting
+// their own causes, are recorded under MetadataCausesOmittedKey. Zero or less keeps every cause.
+func SerializationPolicyWithMaxCauses(n int) SerializationPolicyOption {
+	return func(p *SerializationPolicy) {
+		p.maxCauses = n
+	}
+}
+
+// SerializationPolicyWithMaxCauseDepth keeps causes nested at most depth levels below the top-level
+// error: a depth of 1 keeps only direct causes. Deeper causes are omitted and counted under
+// MetadataCausesOmittedKey on their parent. Zero or less keeps every level.
+func SerializationPolicyWithMaxCauseDepth(depth int) SerializationPolicyOption {
+	return func(p *SerializationPolicy) {
+		p.maxCauseDepth = depth
+	}
+}
+
+// Audience returns the audience the policy serializes for
+func (p *SerializationPolicy) Audience() Visibility { return p.audience }
+
+// AllowsDebugInfo reports whether debug info, deadline info, the principal class of auth info
+// and wrapped error text may be serialized
+func (p *SerializationPolicy) AllowsDebugInfo() bool {
+	return p.trusted || p.audience == VisibilityInternal
+}
+
+// AllowsMetadata reports whether a metadata entry with the given visibility may be serialized
+func (p *SerializationPolicy) AllowsMetadata(visibility Visibility) bool {
+	return !p.filterVisibility || visibility >= p.audience
+}
+
+// Apply returns e itself when the policy allows everything, otherwise a copy without
+// debug info and wrapped error, without filtered metadata, or with causes over the limits omitted,
+// applied recursively to its causes
+func (p *SerializationPolicy) Apply(e *TrogonError) *TrogonError {
+	return p.apply(e, 1)
+}
+
+func (p *SerializationPolicy) apply(e *TrogonError, depth int) *TrogonError {
+	if e == nil || (p.AllowsDebugInfo() && !p.filterVisibility && !p.limitsCauses()) {
+		return e
+	}
+
+	stripped := e.copy()
+	if !p.AllowsDebugInfo() {
+		stripped.debugInfo = nil
+		stripped.deadlineInfo = nil
+		stripped.authInfo = publicAuthInfo(stripped.authInfo)
+		stripped.wrappedErr = nil
+	}
+	for key, value := range stripped.metadata {
+		if !p.AllowsMetadata(value.visibility) {
+			delete(stripped.metadata, key)
+		}
+	}
+	kept, omitted := p.keptCauses(stripped.causes, depth)
+	stripped.causes = kept
+	if omitted > 0 {
+		setMetadataValue(stripped, min(p.audience, stripped.visibility), MetadataCausesOmittedKey, strconv.Itoa(omitted))
+	}
+	for i, cause := range stripped.causes {
+		stripped.causes[i] = p.apply(cause, depth+1)
+	}
+	return stripped
+}
+
+func (p *SerializationPolicy) limitsCauses() bool {
+	return p.maxCauses > 0 || p.maxCauseDepth > 0
+}
+
+// keptCauses returns the causes of an error nested depth levels deep that the policy keeps,
+// and the number of errors omitted with the others
+func (p *SerializationPolicy) keptCauses(causes []*TrogonError, depth int) ([]*TrogonError, int) {
+	switch {
+	case p.maxCauseDepth > 0 && depth > p.maxCauseDepth:
+		return nil, countErrors(causes)
+	case p.maxCauses > 0 && len(causes) > p.maxCauses:
+		return causes[:p.maxCauses:p.maxCauses], countErrors(causes[p.maxCauses:])
+	default:
+		return causes, 0
+	}
+}
+
+func countErrors(errs []*TrogonError) int {
+	count := 0
+	for _, err := range errs {
+		if err != nil {
+			count += 1 + countErrors(err.causes)
+		}
+	}
+	return count
+}
+
+var publicSerializationPolicy = NewSerializationPolicy(VisibilityPublic)
+
+var serializationPolicy atomic.Pointer[SerializationPolicy]
+
+// SetSerializationPolicy sets the policy used by MarshalJSON and the JSONFormatter.
+// Passing nil restores the default, which serializes for a public audience.
+func SetSerializationPolicy(p *SerializationPolicy) {
+	serializationPolicy.Store(p)
+}
+
+// DefaultSerializationPolicy returns the policy set with SetSerializationPolicy
+func DefaultSerializationPolicy() *SerializationPolicy {
+	if p := serializationPolicy.Load(); p != nil {
+		return p
+	}
+	return publicSerializationPolicy
+}
+
+// MarshalJSONFor encodes the error like MarshalJSON, applying policy instead of the default one
+func (e TrogonError) MarshalJSONFor(policy *SerializationPolicy) ([]byte, error) {
+	return json.Marshal(e.toJSON(policy))
+}
+
+// MarshalWith encodes the wire representation MarshalJSONFor encodes with another codec's marshal function,
+// applying policy. Its fields are tagged with json and with cbor integer keys, so codecs such as
+// trogoncbor can add compact encodings without the core module depending on them.
+func (e TrogonError) MarshalWith(marshal func(any) ([]byte, error), policy *SerializationPolicy) ([]byte, error) {
+	return marshal(e.toJSON(policy))
+}
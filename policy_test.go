@@ -0,0 +1,174 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDebuggableError() *trogonerror.TrogonError {
+	cause := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+		trogonerror.WithCode(trogonerror.CodeUnavailable),
+		trogonerror.WithDebugDetail("dial tcp 10.0.0.7:5432: connection refused"))
+
+	return trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+		trogonerror.WithCode(trogonerror.CodeInternal),
+		trogonerror.WithStackTrace(),
+		trogonerror.WithDebugDetail("gateway returned 502"),
+		trogonerror.WithCause(cause),
+		trogonerror.WithWrap(errors.New("stripe: card_declined")))
+}
+
+func TestSerializationPolicy(t *testing.T) {
+	t.Run("Default policy strips debug info", func(t *testing.T) {
+		data, err := json.Marshal(newDebuggableError())
+
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "debugInfo")
+		assert.NotContains(t, string(data), "wrappedError")
+		assert.NotContains(t, string(data), "connection refused")
+		assert.Contains(t, string(data), "CONNECTION_FAILED")
+	})
+
+	t.Run("Internal audience keeps debug info", func(t *testing.T) {
+		data, err := newDebuggableError().MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"detail":"gateway returned 502"`)
+		assert.Contains(t, string(data), `"detail":"dial tcp 10.0.0.7:5432: connection refused"`)
+		assert.Contains(t, string(data), `"wrappedError":"stripe: card_declined"`)
+	})
+
+	t.Run("Trusted channel keeps debug info for any audience", func(t *testing.T) {
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPrivate,
+			trogonerror.SerializationPolicyWithTrustedChannel())
+
+		data, err := newDebuggableError().MarshalJSONFor(policy)
+
+		assert.NoError(t, err)
+		assert.True(t, policy.AllowsDebugInfo())
+		assert.Contains(t, string(data), "debugInfo")
+	})
+
+	t.Run("Visibility filtering drops metadata less visible than the audience", func(t *testing.T) {
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPrivate,
+			trogonerror.SerializationPolicyWithVisibilityFiltering())
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPrivate, "customerId", "2"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "3"),
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "QUERY_FAILED",
+				trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "query", "SELECT 1"))))
+
+		data, marshalErr := err.MarshalJSONFor(policy)
+		applied := policy.Apply(err)
+
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(data), "orderId")
+		assert.Contains(t, string(data), "customerId")
+		assert.NotContains(t, string(data), "shard")
+		assert.NotContains(t, string(data), "SELECT 1")
+		assert.Len(t, applied.Metadata(), 2)
+		assert.Empty(t, applied.Causes()[0].Metadata())
+		assert.Len(t, err.Metadata(), 3)
+	})
+
+	t.Run("SetSerializationPolicy applies to MarshalJSON and JSONFormatter", func(t *testing.T) {
+		trogonerror.SetSerializationPolicy(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		t.Cleanup(func() { trogonerror.SetSerializationPolicy(nil) })
+
+		err := newDebuggableError()
+		data, marshalErr := json.Marshal(err)
+
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(data), "debugInfo")
+		assert.Contains(t, trogonerror.JSONFormatter.Format(err), "debugInfo")
+	})
+
+	t.Run("Apply strips a copy recursively", func(t *testing.T) {
+		err := newDebuggableError()
+
+		stripped := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic).Apply(err)
+
+		assert.Nil(t, stripped.DebugInfo())
+		assert.Nil(t, stripped.Unwrap())
+		assert.Nil(t, stripped.Causes()[0].DebugInfo())
+		assert.NotNil(t, err.DebugInfo())
+		assert.NotNil(t, err.Causes()[0].DebugInfo())
+		assert.Equal(t, err.Reason(), stripped.Reason())
+	})
+
+	t.Run("Apply returns the error itself when debug info is allowed", func(t *testing.T) {
+		err := newDebuggableError()
+
+		assert.Same(t, err, trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal).Apply(err))
+	})
+}
+
+func TestSerializationPolicyCauseLimits(t *testing.T) {
+	attempt := func(n string) *trogonerror.TrogonError {
+		return trogonerror.NewError("shopify.payments", "ATTEMPT_FAILED",
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "CONNECTION_FAILED")),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "attempt", n))
+	}
+	err := trogonerror.NewError("shopify.payments", "RETRIES_EXHAUSTED",
+		trogonerror.WithCause(attempt("1"), attempt("2"), attempt("3")))
+
+	t.Run("MaxCauses omits the remaining causes with a marker", func(t *testing.T) {
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic,
+			trogonerror.SerializationPolicyWithVisibilityFiltering(),
+			trogonerror.SerializationPolicyWithMaxCauses(1))
+
+		data, marshalErr := err.MarshalJSONFor(policy)
+		assert.NoError(t, marshalErr)
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Len(t, decoded.Causes(), 1)
+		assert.Equal(t, "1", decoded.Causes()[0].Metadata()["attempt"].Value())
+		assert.Equal(t, "4", decoded.Metadata()[trogonerror.MetadataCausesOmittedKey].Value())
+	})
+
+	t.Run("MaxCauseDepth keeps only shallow causes", func(t *testing.T) {
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal,
+			trogonerror.SerializationPolicyWithMaxCauseDepth(1))
+
+		applied := policy.Apply(err)
+
+		assert.Len(t, applied.Causes(), 3)
+		for _, cause := range applied.Causes() {
+			assert.Empty(t, cause.Causes())
+			assert.Equal(t, "1", cause.Metadata()[trogonerror.MetadataCausesOmittedKey].Value())
+		}
+		assert.Len(t, err.Causes()[0].Causes(), 1)
+	})
+
+	t.Run("The marker is never more visible than its error", func(t *testing.T) {
+		internal := trogonerror.NewError("shopify.payments", "RETRIES_EXHAUSTED",
+			trogonerror.WithCause(
+				trogonerror.NewError("shopify.database", "CONNECTION_FAILED"),
+				trogonerror.NewError("shopify.database", "CONNECTION_FAILED")))
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic,
+			trogonerror.SerializationPolicyWithMaxCauses(1))
+
+		applied := policy.Apply(internal)
+
+		assert.Equal(t, trogonerror.VisibilityInternal, applied.Metadata()[trogonerror.MetadataCausesOmittedKey].Visibility())
+		assert.NoError(t, applied.ValidateStrict())
+
+		data, marshalErr := internal.MarshalJSONFor(policy)
+		assert.NoError(t, marshalErr)
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.NoError(t, decoded.ValidateStrict())
+	})
+
+	t.Run("Without limits every cause is kept", func(t *testing.T) {
+		policy := trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal)
+
+		assert.Same(t, err, policy.Apply(err))
+	})
+}
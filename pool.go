@@ -0,0 +1,25 @@
+package trogonerror
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize keeps unusually large renders (deep stacks, huge metadata) from pinning memory in the pool
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
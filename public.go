@@ -0,0 +1,109 @@
+package trogonerror
+
+// WithPublicMessage sets a sanitized message for external users, so the error can carry a
+// detailed internal message without exposing infrastructure details to public audiences
+func WithPublicMessage(message string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.publicMessage = message
+	})
+}
+
+// WithChangePublicMessage sets the sanitized message for external users
+func WithChangePublicMessage(message string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.publicMessage = message
+	})
+}
+
+// TemplateWithPublicMessage sets the sanitized message for external users of every error created from the template
+func TemplateWithPublicMessage(message string) TemplateOption {
+	return func(t *ErrorTemplate) {
+		t.publicMessage = message
+	}
+}
+
+// PublicMessage returns the message set with WithPublicMessage, if any
+func (e TrogonError) PublicMessage() string { return e.publicMessage }
+
+// MessageForAudience returns the message to show to audience: the public message for a public
+// audience when one is set, and Message otherwise
+func (e TrogonError) MessageForAudience(audience Visibility) string {
+	if audience == VisibilityPublic && e.publicMessage != "" {
+		return e.publicMessage
+	}
+	return e.Message()
+}
+
+// MaskedDomain is the domain of errors produced by MaskForPublic from non-public errors
+const MaskedDomain = "trogon.masked"
+
+// MaskForPublic converts err into an error safe to return across a trust boundary, such as
+// an API gateway response. Errors that are not TrogonErrors are converted with Translate first.
+//
+// Public errors keep their domain, reason, subject, help links, tags and localized messages, but lose
+// debug info, wrapped errors, source ID and non-public metadata.
+// Other errors are replaced by a generic public error: MaskedDomain, the code name as reason,
+// the public message or the code's default message, and only the ID (for correlation), time,
+// retry and rate limit guidance, auth requirements and public metadata of the original. Their localized
+// messages translate the internal message, so they are dropped too.
+// Causes are never kept. Returns nil for a nil err.
+func MaskForPublic(err error) *TrogonError {
+	original := Translate(err)
+	if original == nil {
+		return nil
+	}
+
+	masked := &TrogonError{
+		specVersion:      original.specVersion,
+		code:             original.code,
+		domain:           original.domain,
+		reason:           original.reason,
+		message:          original.MessageForAudience(VisibilityPublic),
+		visibility:       VisibilityPublic,
+		subject:          original.subject,
+		id:               original.id,
+		time:             original.time,
+		retryInfo:        original.retryInfo,
+		rateLimitInfo:    original.rateLimitInfo,
+		authInfo:         publicAuthInfo(original.authInfo),
+		localizedMessage: original.localizedMessage,
+		translations:     original.translations,
+		transient:        original.transient,
+		idempotency:      original.idempotency,
+		httpStatusCode:   original.httpStatusCode,
+		grpcCode:         original.grpcCode,
+	}
+	if original.visibility != VisibilityPublic {
+		masked.domain = MaskedDomain
+		masked.reason = original.code.String()
+		masked.subject = ""
+		masked.localizedMessage = nil
+		masked.translations = nil
+		if original.publicMessage == "" {
+			masked.message = ""
+		}
+	} else {
+		masked.help = original.helpFor(func(visibility Visibility) bool { return visibility == VisibilityPublic })
+		masked.tags = original.tags
+	}
+
+	for key, value := range original.metadata {
+		if value.visibility == VisibilityPublic {
+			if masked.metadata == nil {
+				masked.metadata = make(Metadata)
+			}
+			masked.metadata[key] = MetadataValue{value: RedactMetadataValue(key, value.value), visibility: value.visibility}
+		}
+	}
+	return masked
+}
+
+// publicAuthInfo returns auth without its internal principal class
+func publicAuthInfo(auth *AuthInfo) *AuthInfo {
+	if auth == nil || auth.principalClass == "" {
+		return auth
+	}
+	public := *auth
+	public.principalClass = ""
+	return &public
+}
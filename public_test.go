@@ -0,0 +1,150 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicMessage(t *testing.T) {
+	newError := func() *trogonerror.TrogonError {
+		return trogonerror.NewError("shopify.payments", "GATEWAY_UNREACHABLE",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithMessage("dial tcp 10.0.3.7:443: connect: connection refused"),
+			trogonerror.WithPublicMessage("Payments are temporarily unavailable"))
+	}
+
+	t.Run("MessageForAudience", func(t *testing.T) {
+		err := newError()
+
+		assert.Equal(t, "Payments are temporarily unavailable", err.MessageForAudience(trogonerror.VisibilityPublic))
+		assert.Equal(t, "dial tcp 10.0.3.7:443: connect: connection refused", err.MessageForAudience(trogonerror.VisibilityPrivate))
+		assert.Equal(t, "dial tcp 10.0.3.7:443: connect: connection refused", err.MessageForAudience(trogonerror.VisibilityInternal))
+		assert.Equal(t, "service unavailable",
+			trogonerror.NewError("shopify.payments", "GATEWAY_UNREACHABLE", trogonerror.WithCode(trogonerror.CodeUnavailable)).
+				MessageForAudience(trogonerror.VisibilityPublic))
+	})
+
+	t.Run("JSON picks the message for the policy audience", func(t *testing.T) {
+		public, _ := json.Marshal(newError())
+		internal, _ := newError().MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+
+		assert.Contains(t, string(public), `"message":"Payments are temporarily unavailable"`)
+		assert.NotContains(t, string(public), "connection refused")
+		assert.Contains(t, string(internal), `"message":"dial tcp 10.0.3.7:443: connect: connection refused"`)
+		assert.Contains(t, string(internal), `"publicMessage":"Payments are temporarily unavailable"`)
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(internal, &decoded))
+		assert.Empty(t, trogonerror.Diff(newError(), &decoded, trogonerror.CompareIgnoringTime()))
+	})
+
+	t.Run("Templates and changes", func(t *testing.T) {
+		template := trogonerror.NewErrorTemplate("shopify.payments", "GATEWAY_UNREACHABLE",
+			trogonerror.TemplateWithPublicMessage("Payments are temporarily unavailable"))
+
+		err := template.NewError()
+		changed := err.WithChanges(trogonerror.WithChangePublicMessage("Try again in a few minutes"))
+
+		assert.Equal(t, "Payments are temporarily unavailable", err.PublicMessage())
+		assert.Equal(t, "Try again in a few minutes", changed.PublicMessage())
+		assert.Contains(t, err.Error(), "\n  publicMessage: Payments are temporarily unavailable")
+	})
+
+}
+
+func TestMaskForPublic(t *testing.T) {
+	t.Run("Internal errors become generic public errors", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "LEDGER_WRITE_FAILED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithMessage("insert into ledger_entries: deadlock detected"),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithSubject("/ledger/entries"),
+			trogonerror.WithSourceID("ledger-writer-7"),
+			trogonerror.WithRetryInfoDuration(30*time.Second),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPrivate, "shard", "ledger-3"),
+			trogonerror.WithDebugDetail("lock wait timeout"),
+			trogonerror.WithWrap(errors.New("pq: deadlock detected")),
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "DEADLOCK")))
+
+		masked := trogonerror.MaskForPublic(fmt.Errorf("charge: %w", err))
+
+		assert.Equal(t, trogonerror.MaskedDomain, masked.Domain())
+		assert.Equal(t, "UNAVAILABLE", masked.Reason())
+		assert.Equal(t, trogonerror.CodeUnavailable, masked.Code())
+		assert.Equal(t, trogonerror.VisibilityPublic, masked.Visibility())
+		assert.Equal(t, "service unavailable", masked.Message())
+		assert.Equal(t, "err_123", masked.ID())
+		assert.Equal(t, err.Time(), masked.Time())
+		assert.Equal(t, 30*time.Second, *masked.RetryInfo().RetryOffset())
+		assert.Equal(t, []string{"orderId"}, slices.Collect(maps.Keys(masked.Metadata())))
+		assert.Empty(t, masked.Subject())
+		assert.Empty(t, masked.SourceID())
+		assert.Nil(t, masked.DebugInfo())
+		assert.Nil(t, masked.Unwrap())
+		assert.Empty(t, masked.Causes())
+	})
+
+	t.Run("Uses the public message", func(t *testing.T) {
+		masked := trogonerror.MaskForPublic(trogonerror.NewError("shopify.payments", "LEDGER_WRITE_FAILED",
+			trogonerror.WithMessage("insert into ledger_entries: deadlock detected"),
+			trogonerror.WithPublicMessage("Your payment is being processed")))
+
+		assert.Equal(t, "Your payment is being processed", masked.Message())
+	})
+
+	t.Run("Drops the translations of the internal message", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "LEDGER_WRITE_FAILED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithMessage("insert into ledger_entries: deadlock detected"),
+			trogonerror.WithLocalizedMessage("es-ES", "insertar en ledger_entries: interbloqueo detectado"),
+			trogonerror.WithLocalizedMessages(map[string]string{"fr-FR": "insertion dans ledger_entries : interblocage détecté"}))
+
+		masked := trogonerror.MaskForPublic(err)
+
+		assert.Nil(t, masked.LocalizedMessage())
+		assert.Empty(t, masked.LocalizedMessages())
+		assert.Equal(t, "service unavailable", masked.MessageFor("es-ES"))
+		data, _ := json.Marshal(masked)
+		assert.NotContains(t, string(data), "ledger_entries")
+
+		public := trogonerror.MaskForPublic(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithLocalizedMessage("es-ES", "Pedido no encontrado"),
+			trogonerror.WithLocalizedMessages(map[string]string{"fr-FR": "Commande introuvable"})))
+		assert.Equal(t, "es-ES", public.LocalizedMessage().Locale())
+		assert.Len(t, public.LocalizedMessages(), 1)
+	})
+
+	t.Run("Public errors keep their identity", func(t *testing.T) {
+		masked := trogonerror.MaskForPublic(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithSubject("/orderId"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "table", "orders"),
+			trogonerror.WithStackTrace()))
+
+		assert.Equal(t, "shopify.orders", masked.Domain())
+		assert.Equal(t, "ORDER_NOT_FOUND", masked.Reason())
+		assert.Equal(t, "/orderId", masked.Subject())
+		assert.Empty(t, masked.Metadata())
+		assert.Nil(t, masked.DebugInfo())
+	})
+
+	t.Run("Third-party errors", func(t *testing.T) {
+		masked := trogonerror.MaskForPublic(errors.New("redis: connection pool exhausted"))
+
+		assert.Equal(t, trogonerror.MaskedDomain, masked.Domain())
+		assert.Equal(t, "unknown error", masked.Message())
+		assert.NotContains(t, masked.Error(), "redis")
+		assert.Nil(t, trogonerror.MaskForPublic(nil))
+	})
+}
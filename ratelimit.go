@@ -0,0 +1,80 @@
+package trogonerror
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitInfo describes the quota a ResourceExhausted error was rejected by: how many requests
+// the limit allows per window, how many are left, and when the quota resets
+type RateLimitInfo struct {
+	limit     int
+	remaining int
+	window    time.Duration
+	resetTime *time.Time
+}
+
+func (r RateLimitInfo) Limit() int            { return r.limit }
+func (r RateLimitInfo) Remaining() int        { return r.remaining }
+func (r RateLimitInfo) Window() time.Duration { return r.window }
+func (r RateLimitInfo) ResetTime() *time.Time { return r.resetTime }
+
+// ResetAfter returns how long from now until the quota resets, zero when the reset time
+// is unknown or in the past
+func (r RateLimitInfo) ResetAfter(now time.Time) time.Duration {
+	if r.resetTime == nil {
+		return 0
+	}
+	return max(r.resetTime.Sub(now), 0)
+}
+
+// WithRateLimitInfo attaches the quota the request was rejected by, typically to a ResourceExhausted error.
+// A zero window or reset time leaves it unset. HTTP writers render it as X-RateLimit-* headers,
+// and as Retry-After when the error has no RetryInfo.
+//
+// Example usage:
+//
+//	err := ErrRateLimited.NewError(
+//	    trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+func WithRateLimitInfo(limit, remaining int, window time.Duration, resetTime time.Time) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.rateLimitInfo = newRateLimitInfo(limit, remaining, window, resetTime)
+	})
+}
+
+// WithChangeRateLimitInfo sets the quota the request was rejected by (replaces existing rate limit info)
+func WithChangeRateLimitInfo(limit, remaining int, window time.Duration, resetTime time.Time) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.rateLimitInfo = newRateLimitInfo(limit, remaining, window, resetTime)
+	})
+}
+
+// RateLimitInfo returns the quota the request was rejected by, nil when none was attached
+func (e TrogonError) RateLimitInfo() *RateLimitInfo { return e.rateLimitInfo }
+
+func newRateLimitInfo(limit, remaining int, window time.Duration, resetTime time.Time) *RateLimitInfo {
+	info := &RateLimitInfo{limit: limit, remaining: remaining, window: window}
+	if !resetTime.IsZero() {
+		info.resetTime = &resetTime
+	}
+	return info
+}
+
+// SetRateLimitHeaders sets X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (Unix seconds,
+// rounded up) from the error's rate limit info, if any
+func SetRateLimitHeaders(header http.Header, err *TrogonError) {
+	if err == nil || err.rateLimitInfo == nil {
+		return
+	}
+	info := err.rateLimitInfo
+	header.Set("X-RateLimit-Limit", strconv.Itoa(info.limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(max(info.remaining, 0)))
+	if info.resetTime != nil {
+		reset := info.resetTime.Unix()
+		if info.resetTime.Nanosecond() > 0 {
+			reset++
+		}
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	}
+}
@@ -0,0 +1,135 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitInfo(t *testing.T) {
+	resetTime := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+
+	t.Run("Carries the quota", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+
+		info := err.RateLimitInfo()
+		assert.Equal(t, 1000, info.Limit())
+		assert.Equal(t, 0, info.Remaining())
+		assert.Equal(t, time.Hour, info.Window())
+		assert.Equal(t, resetTime, *info.ResetTime())
+		assert.Equal(t, 10*time.Minute, info.ResetAfter(resetTime.Add(-10*time.Minute)))
+		assert.Equal(t, time.Duration(0), info.ResetAfter(resetTime.Add(time.Minute)))
+	})
+
+	t.Run("A zero reset time is unset", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRateLimitInfo(100, 5, 0, time.Time{}))
+
+		assert.Nil(t, err.RateLimitInfo().ResetTime())
+		assert.Nil(t, trogonerror.NewError("shopify.users", "NOT_FOUND").RateLimitInfo())
+	})
+
+	t.Run("WithChangeRateLimitInfo replaces the quota on the copy only", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRateLimitInfo(100, 0, time.Minute, resetTime))
+
+		modified := original.WithChanges(trogonerror.WithChangeRateLimitInfo(100, 0, time.Minute, resetTime.Add(time.Minute)))
+
+		assert.Equal(t, resetTime, *original.RateLimitInfo().ResetTime())
+		assert.Equal(t, resetTime.Add(time.Minute), *modified.RateLimitInfo().ResetTime())
+	})
+
+	t.Run("Survives serialization and public masking", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+
+		data, marshalErr := json.Marshal(err)
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(data), `"rateLimitInfo":{"limit":1000,"remaining":0,"window":"3600s","resetTime":"2024-01-15T15:00:00Z"}`)
+		assert.NoError(t, trogonerror.ValidatePayload(data))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Empty(t, trogonerror.Diff(err, &decoded))
+
+		assert.Equal(t, err.RateLimitInfo(), trogonerror.MaskForPublic(err).RateLimitInfo())
+	})
+
+	t.Run("Validate rejects an inconsistent quota", func(t *testing.T) {
+		exceeded := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRateLimitInfo(10, 20, time.Minute, time.Time{}))
+		negative := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRateLimitInfo(-1, 0, time.Minute, time.Time{}))
+
+		assert.ErrorIs(t, exceeded.Validate(), trogonerror.ErrInvalidRateLimitInfo)
+		assert.ErrorIs(t, negative.Validate(), trogonerror.ErrInvalidRateLimitInfo)
+	})
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	resetTime := time.Date(2024, 1, 15, 15, 0, 0, 500, time.UTC)
+
+	t.Run("Writes the X-RateLimit headers", func(t *testing.T) {
+		header := http.Header{}
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+
+		trogonerror.SetRateLimitHeaders(header, err)
+
+		assert.Equal(t, "1000", header.Get("X-RateLimit-Limit"))
+		assert.Equal(t, "0", header.Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "1705330801", header.Get("X-RateLimit-Reset"))
+	})
+
+	t.Run("Skips errors without rate limit info", func(t *testing.T) {
+		header := http.Header{}
+
+		trogonerror.SetRateLimitHeaders(header, trogonerror.NewError("shopify.users", "NOT_FOUND"))
+		trogonerror.SetRateLimitHeaders(header, nil)
+
+		assert.Empty(t, header)
+	})
+
+	t.Run("The reset time backs Retry-After without retry info", func(t *testing.T) {
+		header := http.Header{}
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+
+		trogonerror.SetRetryAfter(header, err)
+
+		assert.Equal(t, "Mon, 15 Jan 2024 15:00:00 GMT", header.Get("Retry-After"))
+	})
+
+	t.Run("Retry info wins over the reset time", func(t *testing.T) {
+		header := http.Header{}
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(30*time.Second),
+			trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+
+		trogonerror.SetRetryAfter(header, err)
+
+		assert.Equal(t, "30", header.Get("Retry-After"))
+	})
+
+	t.Run("Respond renders the headers", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithRateLimitInfo(1000, 0, time.Hour, resetTime))
+		recorder := httptest.NewRecorder()
+
+		assert.NoError(t, trogonerror.Respond(recorder, httptest.NewRequest(http.MethodGet, "/", nil), err))
+
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "1000", recorder.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "Mon, 15 Jan 2024 15:00:00 GMT", recorder.Header().Get("Retry-After"))
+	})
+}
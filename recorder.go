@@ -0,0 +1,127 @@
+package trogonerror
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// DefaultRecorderCapacity is the number of errors kept by a Recorder unless changed with RecorderWithCapacity
+const DefaultRecorderCapacity = 256
+
+// Recorder keeps the most recent errors in a bounded ring buffer, so support engineers can pull
+// the latest structured failures from a running process during an incident.
+// Errors are redacted for the recorder's audience before being stored. It is safe for concurrent use.
+//
+// Example usage:
+//
+//	recorder := trogonerror.NewRecorder(trogonerror.RecorderWithCapacity(512))
+//	trogonerror.RegisterCreateHook("recorder", recorder.Record)
+type Recorder struct {
+	mu       sync.Mutex
+	capacity int
+	audience Visibility
+	policy   *SerializationPolicy
+	errors   []*TrogonError
+	next     int
+	full     bool
+}
+
+// RecorderOption represents options for recorder construction
+type RecorderOption func(*Recorder)
+
+// RecorderWithCapacity sets how many errors are kept; values below one are ignored
+func RecorderWithCapacity(capacity int) RecorderOption {
+	return func(r *Recorder) {
+		if capacity > 0 {
+			r.capacity = capacity
+		}
+	}
+}
+
+// RecorderWithAudience sets the visibility recorded errors are redacted to: debug info and wrapped
+// errors are dropped below an internal audience, and metadata less visible than the audience is
+// dropped. Defaults to VisibilityPrivate.
+func RecorderWithAudience(audience Visibility) RecorderOption {
+	return func(r *Recorder) {
+		r.audience = audience
+	}
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder(options ...RecorderOption) *Recorder {
+	recorder := &Recorder{capacity: DefaultRecorderCapacity, audience: VisibilityPrivate}
+
+	for _, option := range options {
+		option(recorder)
+	}
+
+	recorder.policy = NewSerializationPolicy(recorder.audience, SerializationPolicyWithVisibilityFiltering())
+	recorder.errors = make([]*TrogonError, recorder.capacity)
+	return recorder
+}
+
+// Record stores err, evicting the oldest error once the recorder is full. It has the CreateHook signature.
+func (r *Recorder) Record(err *TrogonError) {
+	if err == nil {
+		return
+	}
+	redacted := r.policy.Apply(err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors[r.next] = redacted
+	r.next = (r.next + 1) % len(r.errors)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Len returns the number of recorded errors
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.full {
+		return len(r.errors)
+	}
+	return r.next
+}
+
+// Snapshot returns the recorded errors, most recent first
+func (r *Recorder) Snapshot() []*TrogonError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.errors)
+	}
+	snapshot := make([]*TrogonError, count)
+	for i := range snapshot {
+		snapshot[i] = r.errors[(r.next-1-i+len(r.errors))%len(r.errors)]
+	}
+	return snapshot
+}
+
+// Reset discards all recorded errors
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.errors)
+	r.next = 0
+	r.full = false
+}
+
+// Dump writes the recorded errors to w as JSON lines, most recent first, serialized for the recorder's audience
+func (r *Recorder) Dump(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, err := range r.Snapshot() {
+		if encodeErr := encoder.Encode(err.toJSON(r.policy)); encodeErr != nil {
+			return encodeErr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package trogonerror_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("Keeps the most recent errors", func(t *testing.T) {
+		recorder := trogonerror.NewRecorder(trogonerror.RecorderWithCapacity(2))
+
+		for _, reason := range []string{"FIRST", "SECOND", "THIRD"} {
+			recorder.Record(trogonerror.NewError("shopify.orders", reason))
+		}
+
+		snapshot := recorder.Snapshot()
+		assert.Equal(t, 2, recorder.Len())
+		assert.Equal(t, "THIRD", snapshot[0].Reason())
+		assert.Equal(t, "SECOND", snapshot[1].Reason())
+	})
+
+	t.Run("Redacts to the audience", func(t *testing.T) {
+		recorder := trogonerror.NewRecorder(trogonerror.RecorderWithAudience(trogonerror.VisibilityPublic))
+
+		recorder.Record(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "orders-7"),
+			trogonerror.WithStackTrace()))
+
+		recorded := recorder.Snapshot()[0]
+		assert.Equal(t, "gid://shopify/Order/1", recorded.Metadata()["orderId"].Value())
+		assert.NotContains(t, recorded.Metadata(), "shard")
+		assert.Nil(t, recorded.DebugInfo())
+	})
+
+	t.Run("Dump", func(t *testing.T) {
+		recorder := trogonerror.NewRecorder()
+		recorder.Record(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND"))
+		recorder.Record(trogonerror.NewError("shopify.payments", "CARD_DECLINED"))
+
+		var buf bytes.Buffer
+		assert.NoError(t, recorder.Dump(&buf))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"reason":"CARD_DECLINED"`)
+		assert.Contains(t, lines[1], `"reason":"ORDER_NOT_FOUND"`)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		recorder := trogonerror.NewRecorder()
+		recorder.Record(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND"))
+
+		recorder.Reset()
+
+		assert.Empty(t, recorder.Snapshot())
+	})
+}
@@ -0,0 +1,123 @@
+package trogonerror
+
+import (
+	"regexp"
+	"sync/atomic"
+)
+
+// RedactedValue replaces metadata values, or parts of them, masked by the built-in redactors
+const RedactedValue = "[REDACTED]"
+
+// Redactor masks sensitive data in a metadata value, returning the value to serialize in its place.
+// Redactors must return value unchanged when it has nothing to mask.
+type Redactor func(key, value string) string
+
+var (
+	redactors        atomic.Pointer[[]Redactor]
+	redactOnCreation atomic.Bool
+)
+
+// SetRedactors sets the redactors applied, in order, to metadata values whenever an error is
+// serialized: JSON, the built-in formatters and Error(). Calling it without redactors removes them.
+// It is intended to be called during program initialization by whoever owns compliance policy.
+func SetRedactors(r ...Redactor) {
+	if len(r) == 0 {
+		redactors.Store(nil)
+		return
+	}
+	r = append([]Redactor(nil), r...)
+	redactors.Store(&r)
+}
+
+// SetRedactOnCreation additionally applies the redactors when errors are created or changed,
+// so the unredacted values are never retained in memory
+func SetRedactOnCreation(enabled bool) {
+	redactOnCreation.Store(enabled)
+}
+
+// RedactMetadataValue applies the redactors set with SetRedactors to a metadata value.
+// Encoders living outside this package use it to honor the same policy.
+func RedactMetadataValue(key, value string) string {
+	r := redactors.Load()
+	if r == nil {
+		return value
+	}
+	for _, redact := range *r {
+		value = redact(key, value)
+	}
+	return value
+}
+
+// RedactKeys masks the whole value of the given metadata keys
+func RedactKeys(keys ...string) Redactor {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return func(key, value string) string {
+		if _, ok := set[key]; ok {
+			return RedactedValue
+		}
+		return value
+	}
+}
+
+// RedactPattern masks every match of pattern in any metadata value
+func RedactPattern(pattern *regexp.Regexp) Redactor {
+	return func(_, value string) string {
+		return pattern.ReplaceAllLiteralString(value, RedactedValue)
+	}
+}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// RedactEmails masks email addresses in any metadata value
+func RedactEmails() Redactor {
+	return RedactPattern(emailPattern)
+}
+
+// RedactCardNumbers masks payment card numbers in any metadata value.
+// Only digit runs of 13 to 19 digits, optionally separated by spaces or dashes, passing the Luhn check are masked.
+func RedactCardNumbers() Redactor {
+	return func(_, value string) string {
+		return cardNumberPattern.ReplaceAllStringFunc(value, func(match string) string {
+			if luhnValid(match) {
+				return RedactedValue
+			}
+			return match
+		})
+	}
+}
+
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func applyRedactionOnCreation(e *TrogonError) {
+	if len(e.metadata) == 0 || !redactOnCreation.Load() || redactors.Load() == nil {
+		return
+	}
+	for key, value := range e.metadata {
+		value.value = RedactMetadataValue(key, value.value)
+		e.metadata[key] = value
+	}
+}
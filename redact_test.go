@@ -0,0 +1,77 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCustomerError() *trogonerror.TrogonError {
+	return trogonerror.NewError("shopify.customers", "CUSTOMER_UPDATE_FAILED",
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "email", "jane@example.com"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "note", "contact jane@example.com, card 4111 1111 1111 1111"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "apiToken", "shpat_123"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderNumber", "1234567890123"))
+}
+
+func TestRedactors(t *testing.T) {
+	trogonerror.SetRedactors(
+		trogonerror.RedactKeys("apiToken"),
+		trogonerror.RedactEmails(),
+		trogonerror.RedactCardNumbers())
+	t.Cleanup(func() { trogonerror.SetRedactors() })
+
+	t.Run("Applied on JSON serialization", func(t *testing.T) {
+		err := newCustomerError()
+
+		data, marshalErr := json.Marshal(err)
+
+		assert.NoError(t, marshalErr)
+		assert.NotContains(t, string(data), "jane@example.com")
+		assert.NotContains(t, string(data), "4111")
+		assert.NotContains(t, string(data), "shpat_123")
+		assert.Contains(t, string(data), `"contact [REDACTED], card [REDACTED]"`)
+		assert.Contains(t, string(data), `"1234567890123"`, "digit runs failing the Luhn check are kept")
+	})
+
+	t.Run("Applied by the formatters", func(t *testing.T) {
+		err := newCustomerError()
+
+		for _, formatter := range []trogonerror.Formatter{trogonerror.TextFormatter, trogonerror.KeyValueFormatter} {
+			out := formatter.Format(err)
+			assert.NotContains(t, out, "jane@example.com")
+			assert.NotContains(t, out, "shpat_123")
+		}
+	})
+
+	t.Run("Retained in memory by default", func(t *testing.T) {
+		err := newCustomerError()
+
+		assert.Equal(t, "jane@example.com", err.Metadata()["email"].Value())
+	})
+
+	t.Run("Applied on creation when enabled", func(t *testing.T) {
+		trogonerror.SetRedactOnCreation(true)
+		t.Cleanup(func() { trogonerror.SetRedactOnCreation(false) })
+
+		err := newCustomerError()
+		changed := err.WithChanges(trogonerror.WithChangeMetadataValue(trogonerror.VisibilityInternal, "email", "john@example.com"))
+
+		assert.Equal(t, trogonerror.RedactedValue, err.Metadata()["email"].Value())
+		assert.Equal(t, trogonerror.RedactedValue, changed.Metadata()["email"].Value())
+	})
+}
+
+func TestRedactPattern(t *testing.T) {
+	redact := trogonerror.RedactPattern(regexp.MustCompile(`\d{3}-\d{2}-\d{4}`))
+
+	assert.Equal(t, "ssn [REDACTED]", redact("note", "ssn 078-05-1120"))
+	assert.Equal(t, "nothing here", redact("note", "nothing here"))
+}
+
+func TestRedactMetadataValue_WithoutRedactors(t *testing.T) {
+	assert.Equal(t, "jane@example.com", trogonerror.RedactMetadataValue("email", "jane@example.com"))
+}
@@ -0,0 +1,45 @@
+package trogonerror
+
+import "sync"
+
+var (
+	templatesMu    sync.RWMutex
+	templates      []*ErrorTemplate
+	templatesIndex = make(map[ErrorKey]int)
+)
+
+// RegisterTemplate makes the template discoverable by its domain and reason, for tooling such as
+// catalog loaders, code generators and documentation endpoints.
+// Registering a template with the same domain and reason again replaces the previous one.
+func RegisterTemplate(template *ErrorTemplate) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+
+	key := template.Key()
+	if i, ok := templatesIndex[key]; ok {
+		templates[i] = template
+		return
+	}
+	templatesIndex[key] = len(templates)
+	templates = append(templates, template)
+}
+
+// LookupTemplate returns the registered template with the given domain and reason
+func LookupTemplate(domain, reason string) (*ErrorTemplate, bool) {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+
+	i, ok := templatesIndex[ErrorKey{Domain: domain, Reason: reason}]
+	if !ok {
+		return nil, false
+	}
+	return templates[i], true
+}
+
+// Templates returns the registered templates in registration order
+func Templates() []*ErrorTemplate {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+
+	return append([]*ErrorTemplate(nil), templates...)
+}
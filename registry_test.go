@@ -0,0 +1,44 @@
+package trogonerror_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRegistry(t *testing.T) {
+	first := trogonerror.NewErrorTemplate("shopify.registry", "FIRST")
+	trogonerror.RegisterTemplate(first)
+
+	found, ok := trogonerror.LookupTemplate("shopify.registry", "FIRST")
+	assert.True(t, ok)
+	assert.Same(t, first, found)
+	assert.Contains(t, trogonerror.Templates(), first)
+
+	replacement := trogonerror.NewErrorTemplate("shopify.registry", "FIRST", trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+	trogonerror.RegisterTemplate(replacement)
+
+	found, _ = trogonerror.LookupTemplate("shopify.registry", "FIRST")
+	assert.Same(t, replacement, found)
+	assert.NotContains(t, trogonerror.Templates(), first)
+
+	_, ok = trogonerror.LookupTemplate("shopify.registry", "MISSING")
+	assert.False(t, ok)
+}
+
+func TestParseCode(t *testing.T) {
+	code, err := trogonerror.ParseCode("NOT_FOUND")
+	assert.NoError(t, err)
+	assert.Equal(t, trogonerror.CodeNotFound, code)
+
+	_, err = trogonerror.ParseCode("MISSING")
+	assert.ErrorIs(t, err, trogonerror.ErrInvalidCode)
+
+	visibility, err := trogonerror.ParseVisibility("PRIVATE")
+	assert.NoError(t, err)
+	assert.Equal(t, trogonerror.VisibilityPrivate, visibility)
+
+	_, err = trogonerror.ParseVisibility("SECRET")
+	assert.ErrorIs(t, err, trogonerror.ErrInvalidVisibility)
+}
@@ -0,0 +1,77 @@
+package trogonerror
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+// DefaultRequestIDHeader is the header RequestIDMiddleware reads when none is given
+const DefaultRequestIDHeader = "X-Request-Id"
+
+var requestIDExtractor atomic.Pointer[ContextExtractor]
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID recorded by WithContext
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx by ContextWithRequestID, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// SetRequestIDExtractor replaces how WithContext finds the request ID, for applications whose
+// middleware already stores it under their own context key (see ContextKeyExtractor).
+// Passing nil restores the default, RequestIDFromContext.
+func SetRequestIDExtractor(extractor ContextExtractor) {
+	if extractor == nil {
+		requestIDExtractor.Store(nil)
+		return
+	}
+	requestIDExtractor.Store(&extractor)
+}
+
+func requestID(ctx context.Context) (string, bool) {
+	if extractor := requestIDExtractor.Load(); extractor != nil {
+		return (*extractor)(ctx)
+	}
+	return RequestIDFromContext(ctx)
+}
+
+func withRequestID(ctx context.Context) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if id, ok := requestID(ctx); ok {
+			setMetadataValue(e, VisibilityPrivate, MetadataRequestIDKey, id)
+		}
+	})
+}
+
+// applyRequestIDVisibility keeps the request ID no more visible than the error. It runs once all options
+// have been applied, so the result does not depend on whether WithVisibility comes before WithContext.
+func applyRequestIDVisibility(e *TrogonError) {
+	if value, ok := e.metadata[MetadataRequestIDKey]; ok && value.visibility > e.visibility {
+		e.metadata[MetadataRequestIDKey] = MetadataValue{value: value.value, visibility: e.visibility}
+	}
+}
+
+// RequestIDMiddleware stores the request ID found in the given header (DefaultRequestIDHeader when empty)
+// in the request context, so errors created with WithContext(r.Context()) are correlated with the request.
+// Requests without the header get a generated ID, which is echoed in the response header.
+func RequestIDMiddleware(next http.Handler, header string) http.Handler {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if id == "" {
+			id = generateID()
+			w.Header().Set(header, id)
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,107 @@
+package trogonerror_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+type correlationIDKey struct{}
+
+func TestRequestID(t *testing.T) {
+	t.Run("WithContext records the request ID as private metadata", func(t *testing.T) {
+		ctx := trogonerror.ContextWithRequestID(context.Background(), "req-123")
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithContext(ctx))
+
+		assert.Equal(t, "req-123", err.Metadata()[trogonerror.MetadataRequestIDKey].Value())
+		assert.Equal(t, trogonerror.VisibilityPrivate, err.Metadata()[trogonerror.MetadataRequestIDKey].Visibility())
+		assert.NoError(t, err.ValidateStrict())
+	})
+
+	t.Run("The request ID is never more visible than the error", func(t *testing.T) {
+		ctx := trogonerror.ContextWithRequestID(context.Background(), "req-123")
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(ctx))
+
+		assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()[trogonerror.MetadataRequestIDKey].Visibility())
+		assert.NoError(t, err.ValidateStrict())
+	})
+
+	t.Run("The request ID visibility does not depend on the option order", func(t *testing.T) {
+		ctx := trogonerror.ContextWithRequestID(context.Background(), "req-123")
+
+		publicErr := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(ctx),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic))
+		internalErr := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithContext(ctx),
+			trogonerror.WithVisibility(trogonerror.VisibilityInternal))
+
+		assert.Equal(t, trogonerror.VisibilityPrivate, publicErr.Metadata()[trogonerror.MetadataRequestIDKey].Visibility())
+		assert.Equal(t, trogonerror.VisibilityInternal, internalErr.Metadata()[trogonerror.MetadataRequestIDKey].Visibility())
+		assert.NoError(t, internalErr.ValidateStrict())
+	})
+
+	t.Run("SetRequestIDExtractor reads the ID from an application context key", func(t *testing.T) {
+		trogonerror.SetRequestIDExtractor(trogonerror.ContextKeyExtractor(correlationIDKey{}))
+		t.Cleanup(func() { trogonerror.SetRequestIDExtractor(nil) })
+
+		ctx := context.WithValue(context.Background(), correlationIDKey{}, "corr-456")
+		err := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+			trogonerror.WithContext(ctx))
+
+		assert.Equal(t, "corr-456", err.Metadata()[trogonerror.MetadataRequestIDKey].Value())
+	})
+
+	t.Run("RequestIDMiddleware stores the header value in the request context", func(t *testing.T) {
+		var err *trogonerror.TrogonError
+		handler := trogonerror.RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			err = trogonerror.NewError("shopify.orders", "ORDER_FAILED", trogonerror.WithContext(r.Context()))
+		}), "X-Correlation-Id")
+
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.Header.Set("X-Correlation-Id", "corr-789")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "corr-789", err.Metadata()[trogonerror.MetadataRequestIDKey].Value())
+	})
+
+	t.Run("RequestIDMiddleware generates and echoes a missing ID", func(t *testing.T) {
+		trogonerror.SetIDGenerator(func() string { return "generated-id" })
+		t.Cleanup(func() { trogonerror.SetIDGenerator(nil) })
+
+		var id string
+		handler := trogonerror.RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			id, _ = trogonerror.RequestIDFromContext(r.Context())
+		}), "")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+		assert.Equal(t, "generated-id", id)
+		assert.Equal(t, "generated-id", rec.Header().Get(trogonerror.DefaultRequestIDHeader))
+	})
+
+	t.Run("RecoverHTTP records the request ID on panic errors", func(t *testing.T) {
+		var err *trogonerror.TrogonError
+		handler := trogonerror.RecoverHTTP(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("boom")
+		}), func(_ http.ResponseWriter, _ *http.Request, recovered *trogonerror.TrogonError) {
+			err = recovered
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(trogonerror.ContextWithRequestID(req.Context(), "req-123")))
+
+		assert.Equal(t, "req-123", err.Metadata()[trogonerror.MetadataRequestIDKey].Value())
+	})
+}
@@ -0,0 +1,234 @@
+package trogonerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Media types rendered by Respond
+const (
+	MediaTypeJSON        = "application/json"
+	MediaTypeProblemJSON = "application/problem+json"
+	MediaTypeText        = "text/plain"
+	MediaTypeHTML        = "text/html"
+)
+
+var respondMediaTypes = []string{MediaTypeJSON, MediaTypeProblemJSON, MediaTypeText, MediaTypeHTML}
+
+type responder struct {
+	policy *SerializationPolicy
+	html   *HTMLRenderer
+}
+
+// RespondOption represents options for Respond
+type RespondOption func(*responder)
+
+// RespondWithPolicy sets the serialization policy applied to the response instead of the default one
+func RespondWithPolicy(policy *SerializationPolicy) RespondOption {
+	return func(r *responder) {
+		r.policy = policy
+	}
+}
+
+// RespondWithHTMLRenderer sets the renderer of text/html responses instead of one
+// rendering for the policy's audience
+func RespondWithHTMLRenderer(renderer *HTMLRenderer) RespondOption {
+	return func(r *responder) {
+		r.html = renderer
+	}
+}
+
+// Respond writes err as the HTTP response, in the media type the request's Accept header prefers
+// among application/json, application/problem+json (RFC 9457), text/plain and text/html.
+// Requests without an acceptable media type get application/json.
+// Errors that are not TrogonErrors are converted with Translate, and errors are masked with
+// MaskForPublic when the policy's audience is public. The status code, Retry-After, X-RateLimit-*
+// and WWW-Authenticate headers come from the error.
+// Respond writes nothing for a nil err.
+func Respond(w http.ResponseWriter, r *http.Request, err error, options ...RespondOption) error {
+	responder := responder{policy: DefaultSerializationPolicy()}
+	for _, option := range options {
+		option(&responder)
+	}
+
+	trogonErr := Translate(err)
+	if trogonErr == nil {
+		return nil
+	}
+	if responder.policy.Audience() == VisibilityPublic {
+		trogonErr = MaskForPublic(trogonErr)
+	}
+	trogonErr = responder.policy.Apply(trogonErr)
+
+	mediaType := NegotiateMediaType(r.Header.Get("Accept"), respondMediaTypes...)
+	if mediaType == "" {
+		mediaType = MediaTypeJSON
+	}
+
+	var (
+		body      []byte
+		renderErr error
+	)
+	switch mediaType {
+	case MediaTypeProblemJSON:
+		body, renderErr = json.Marshal(problemDetails(trogonErr, responder.policy))
+	case MediaTypeText:
+		body = []byte(plainText(trogonErr, responder.policy.Audience()))
+	case MediaTypeHTML:
+		renderer := responder.html
+		if renderer == nil {
+			renderer = NewHTMLRenderer(HTMLRendererWithAudience(responder.policy.Audience()))
+		}
+		var html bytes.Buffer
+		renderErr = renderer.Render(&html, trogonErr)
+		body = html.Bytes()
+	default:
+		body, renderErr = trogonErr.MarshalJSONFor(responder.policy)
+	}
+	if renderErr != nil {
+		return renderErr
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Accept")
+	if strings.HasPrefix(mediaType, "text/") {
+		header.Set("Content-Type", mediaType+"; charset=utf-8")
+	} else {
+		header.Set("Content-Type", mediaType)
+	}
+	header.Set("X-Content-Type-Options", "nosniff")
+	SetRetryAfter(header, trogonErr)
+	SetRateLimitHeaders(header, trogonErr)
+	SetWWWAuthenticate(header, trogonErr)
+	w.WriteHeader(trogonErr.StatusCode())
+	_, renderErr = w.Write(body)
+	return renderErr
+}
+
+// NegotiateMediaType returns the offer the Accept header value prefers, honoring quality values and
+// the specificity of media ranges. Ties go to the earlier offer, an empty Accept accepts the first offer,
+// and an empty string is returned when no offer is acceptable.
+func NegotiateMediaType(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		if quality := acceptQuality(ranges, offer); quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = min(max(q, 0), 1)
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality of the most specific range matching offer
+func acceptQuality(ranges []mediaRange, offer string) float64 {
+	offerType, _, _ := strings.Cut(offer, "/")
+	quality, specificity := 0.0, -1
+	for _, r := range ranges {
+		var match int
+		switch {
+		case r.mediaType == offer:
+			match = 2
+		case r.mediaType == offerType+"/*":
+			match = 1
+		case r.mediaType == "*/*":
+			match = 0
+		default:
+			continue
+		}
+		if match > specificity {
+			quality, specificity = r.quality, match
+		}
+	}
+	return quality
+}
+
+type problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Code     string            `json:"code"`
+	Domain   string            `json:"domain"`
+	Reason   string            `json:"reason"`
+	ID       string            `json:"id,omitempty"`
+	Subject  string            `json:"subject,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// problemDetails builds an RFC 9457 problem, typed by the error's documentation link when it has one
+func problemDetails(e *TrogonError, policy *SerializationPolicy) problem {
+	p := problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(e.StatusCode()),
+		Status:  e.StatusCode(),
+		Detail:  e.MessageForAudience(policy.Audience()),
+		Code:    e.code.String(),
+		Domain:  e.domain,
+		Reason:  e.reason,
+		ID:      e.id,
+		Subject: e.subject,
+	}
+	if help := e.helpFor(policy.AllowsMetadata); help != nil {
+		if link, ok := help.Link(HelpLinkDocumentation); ok {
+			p.Type = link.url
+		}
+	}
+	for key, value := range e.metadata {
+		if !policy.AllowsMetadata(value.visibility) {
+			continue
+		}
+		if p.Metadata == nil {
+			p.Metadata = make(map[string]string)
+		}
+		p.Metadata[key] = RedactMetadataValue(key, value.value)
+	}
+	return p
+}
+
+func plainText(e *TrogonError, audience Visibility) string {
+	var b strings.Builder
+	b.WriteString(e.MessageForAudience(audience))
+	b.WriteByte('\n')
+	if e.id != "" {
+		b.WriteString("Error ID: " + e.id + "\n")
+	}
+	return b.String()
+}
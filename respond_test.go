@@ -0,0 +1,108 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	offers := []string{"application/json", "application/problem+json", "text/plain", "text/html"}
+
+	assert.Equal(t, "application/json", trogonerror.NegotiateMediaType("", offers...))
+	assert.Equal(t, "application/json", trogonerror.NegotiateMediaType("*/*", offers...))
+	assert.Equal(t, "text/html", trogonerror.NegotiateMediaType("text/html,application/xhtml+xml,*/*;q=0.8", offers...))
+	assert.Equal(t, "application/problem+json", trogonerror.NegotiateMediaType("application/problem+json, application/json;q=0.9", offers...))
+	assert.Equal(t, "text/plain", trogonerror.NegotiateMediaType("text/*, text/html;q=0.1", offers...))
+	assert.Equal(t, "", trogonerror.NegotiateMediaType("image/png", offers...))
+	assert.Equal(t, "", trogonerror.NegotiateMediaType("*/*;q=0", offers...))
+}
+
+func TestRespond(t *testing.T) {
+	err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.WithID("err-123"),
+		trogonerror.WithMessage("user <b>not</b> found"),
+		trogonerror.WithHelpLink("User docs", "https://docs.example.com/users"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "42"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "7"))
+
+	respond := func(accept string, err error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		assert.NoError(t, trogonerror.Respond(rec, req, err))
+		return rec
+	}
+
+	t.Run("Renders JSON by default", func(t *testing.T) {
+		rec := respond("", err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+		assert.Equal(t, "NOT_FOUND", decoded.Reason())
+		assert.NotContains(t, decoded.Metadata(), "shard")
+	})
+
+	t.Run("Renders problem details", func(t *testing.T) {
+		rec := respond("application/problem+json", err)
+
+		assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		var problem map[string]any
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "https://docs.example.com/users", problem["type"])
+		assert.Equal(t, "Not Found", problem["title"])
+		assert.Equal(t, float64(404), problem["status"])
+		assert.Equal(t, "user <b>not</b> found", problem["detail"])
+		assert.Equal(t, "err-123", problem["id"])
+		assert.Equal(t, map[string]any{"userId": "42"}, problem["metadata"])
+	})
+
+	t.Run("Problem details redact metadata", func(t *testing.T) {
+		trogonerror.SetRedactors(trogonerror.RedactKeys("userId"), trogonerror.RedactEmails())
+		t.Cleanup(func() { trogonerror.SetRedactors() })
+		contact := err.WithChanges(
+			trogonerror.WithChangeMetadataValue(trogonerror.VisibilityPublic, "contact", "write to jane@example.com"))
+
+		rec := respond("application/problem+json", contact)
+
+		assert.NotContains(t, rec.Body.String(), "jane@example.com")
+		var problem map[string]any
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, map[string]any{"userId": trogonerror.RedactedValue, "contact": "write to " + trogonerror.RedactedValue}, problem["metadata"])
+	})
+
+	t.Run("Renders plain text", func(t *testing.T) {
+		rec := respond("text/plain", err)
+
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "user <b>not</b> found\nError ID: err-123\n", rec.Body.String())
+	})
+
+	t.Run("Renders escaped HTML", func(t *testing.T) {
+		rec := respond("text/html", err)
+
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "user &lt;b&gt;not&lt;/b&gt; found")
+		assert.Contains(t, rec.Body.String(), "err-123")
+	})
+
+	t.Run("Masks non-public errors", func(t *testing.T) {
+		rec := respond("text/plain", errors.New("connection refused to 10.0.0.1"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "10.0.0.1")
+	})
+}
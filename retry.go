@@ -0,0 +1,348 @@
+package trogonerror
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// IsRetryable reports whether errors with this code are transient by nature:
+// Unavailable, ResourceExhausted, Aborted and DeadlineExceeded
+func (c Code) IsRetryable() bool {
+	switch c {
+	case CodeUnavailable, CodeResourceExhausted, CodeAborted, CodeDeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether the failed operation should be retried.
+// An explicit WithRetryable override wins, then the producer's WithTransient classification;
+// otherwise errors carrying RetryInfo are retryable, and the remaining ones are classified by their code.
+func (e TrogonError) IsRetryable() bool {
+	if e.retryable != nil {
+		return *e.retryable
+	}
+	if e.transient != nil {
+		return *e.transient
+	}
+	if e.retryInfo != nil {
+		return true
+	}
+	return e.code.IsRetryable()
+}
+
+// Temporary reports whether the error is transient, mirroring IsRetryable for net-style error checks
+func (e TrogonError) Temporary() bool {
+	return e.IsRetryable()
+}
+
+// Timeout reports whether the error represents an exceeded deadline, either by code or by a wrapped timeout error
+func (e TrogonError) Timeout() bool {
+	if e.code == CodeDeadlineExceeded {
+		return true
+	}
+	var timeout interface{ Timeout() bool }
+	return errors.As(e.wrappedErr, &timeout) && timeout.Timeout()
+}
+
+// IsRetryable reports whether err should be retried, using the first TrogonError in its chain.
+// Errors without a TrogonError in their chain are not considered retryable.
+func IsRetryable(err error) bool {
+	var trogonErr *TrogonError
+	if !errors.As(err, &trogonErr) {
+		return false
+	}
+	return trogonErr.IsRetryable()
+}
+
+// WithRetryable overrides the code-based retry classification
+func WithRetryable(retryable bool) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.retryable = &retryable
+	})
+}
+
+// WithChangeRetryable overrides the code-based retry classification
+func WithChangeRetryable(retryable bool) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.retryable = &retryable
+	})
+}
+
+// WithTransient marks the error as transient (safe to retry) or permanent, independently of RetryInfo.
+// Producers use it when they know a retry may succeed but cannot estimate a delay, or to tell
+// clients to stop retrying an error whose code would otherwise be retried. Unlike WithRetryable,
+// the classification is serialized and reaches remote clients.
+func WithTransient(transient bool) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.transient = &transient
+	})
+}
+
+// WithChangeTransient marks the copy as transient or permanent
+func WithChangeTransient(transient bool) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.transient = &transient
+	})
+}
+
+// IsTransient reports whether the producer explicitly marked the error as transient with WithTransient
+func (e TrogonError) IsTransient() bool {
+	return e.transient != nil && *e.transient
+}
+
+// IsPermanent reports whether the producer explicitly marked the error as permanent with WithTransient(false)
+func (e TrogonError) IsPermanent() bool {
+	return e.transient != nil && !*e.transient
+}
+
+// Delay returns how long to wait from now before retrying, normalizing retry offsets and retry times.
+// Retry times in the past yield zero.
+func (r RetryInfo) Delay(now time.Time) time.Duration {
+	var delay time.Duration
+	switch {
+	case r.retryOffset != nil:
+		delay = *r.retryOffset
+	case r.retryTime != nil:
+		delay = r.retryTime.Sub(now)
+	}
+	return max(delay, 0)
+}
+
+// AttemptDelay returns how long to wait from now before the given retry attempt, starting at 1, following
+// the retry contract: the first retry waits Delay, and every later one multiplies the previous wait by the
+// backoff multiplier, capped at the max delay. It returns false once the max attempts are used up.
+func (r RetryInfo) AttemptDelay(attempt int, now time.Time) (time.Duration, bool) {
+	attempt = max(attempt, 1)
+	if !r.allowsAttempt(attempt) {
+		return 0, false
+	}
+
+	delay := float64(r.Delay(now))
+	if r.backoffMultiplier > 0 {
+		delay *= math.Pow(r.backoffMultiplier, float64(attempt-1))
+	}
+	if r.maxDelay > 0 {
+		delay = min(delay, float64(r.maxDelay))
+	}
+	return durationOf(delay), true
+}
+
+// durationOf converts a backoff computed in floating point to a duration, saturating at the longest
+// duration since the exponential growth overflows it, and to +Inf, after enough attempts
+func durationOf(delay float64) time.Duration {
+	switch {
+	case math.IsNaN(delay):
+		return 0
+	case delay >= math.MaxInt64:
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+// allowsAttempt reports whether the given retry attempt fits the max attempts, which count the
+// original request as in gRPC retry policies
+func (r RetryInfo) allowsAttempt(attempt int) bool {
+	return r.maxAttempts <= 0 || attempt < r.maxAttempts
+}
+
+// WithRetryBackoff sets the retry contract: the total number of attempts including the original request,
+// the factor each retry multiplies the previous wait by, and the cap on that wait. Zero values leave
+// the corresponding limit unset. The first wait still comes from WithRetryInfoDuration or WithRetryTime,
+// in either order.
+//
+// Example usage:
+//
+//	err := ErrRateLimited.NewError(
+//	    trogonerror.WithRetryInfoDuration(time.Second),
+//	    trogonerror.WithRetryBackoff(5, 2, 30*time.Second))
+func WithRetryBackoff(maxAttempts int, multiplier float64, maxDelay time.Duration) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		setRetryBackoff(e, maxAttempts, multiplier, maxDelay)
+	})
+}
+
+// WithChangeRetryBackoff sets the retry contract, keeping the retry offset or time
+func WithChangeRetryBackoff(maxAttempts int, multiplier float64, maxDelay time.Duration) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		setRetryBackoff(e, maxAttempts, multiplier, maxDelay)
+	})
+}
+
+// setRetryWait replaces the retry offset or time, keeping the retry contract
+func setRetryWait(e *TrogonError, retryOffset *time.Duration, retryTime *time.Time) {
+	info := RetryInfo{retryOffset: retryOffset, retryTime: retryTime}
+	if e.retryInfo != nil {
+		info.maxAttempts = e.retryInfo.maxAttempts
+		info.backoffMultiplier = e.retryInfo.backoffMultiplier
+		info.maxDelay = e.retryInfo.maxDelay
+	}
+	e.retryInfo = &info
+}
+
+// setRetryBackoff replaces the retry contract on a copy of the retry info, which may be shared with a template
+func setRetryBackoff(e *TrogonError, maxAttempts int, multiplier float64, maxDelay time.Duration) {
+	var info RetryInfo
+	if e.retryInfo != nil {
+		info = *e.retryInfo
+	}
+	info.maxAttempts = maxAttempts
+	info.backoffMultiplier = multiplier
+	info.maxDelay = maxDelay
+	e.retryInfo = &info
+}
+
+// ShouldRetry reports whether err is retryable and the given retry attempt, starting at 1,
+// is within the max attempts of its retry contract
+func ShouldRetry(err error, attempt int) bool {
+	var trogonErr *TrogonError
+	if !errors.As(err, &trogonErr) || !trogonErr.IsRetryable() {
+		return false
+	}
+	return trogonErr.retryInfo == nil || trogonErr.retryInfo.allowsAttempt(max(attempt, 1))
+}
+
+// Wait blocks for the retry delay requested by err, so retry loops can honor server guidance in one call.
+// It returns nil once the caller may retry, err itself when err is not retryable,
+// and ctx.Err() if the context is done before the delay elapses.
+// Errors without RetryInfo return at once, so retry loops should bound their attempts with ShouldRetry
+// and back off with a RetryPolicy when the server gives no guidance.
+//
+// Example usage:
+//
+//	policy := trogonerror.NewRetryPolicy()
+//	for attempt := 1; ; attempt++ {
+//	    err := client.CreateOrder(ctx, req)
+//	    if err == nil || !trogonerror.ShouldRetry(err, attempt) {
+//	        return err
+//	    }
+//	    var trogonErr *trogonerror.TrogonError
+//	    if errors.As(err, &trogonErr) && trogonErr.RetryInfo() == nil {
+//	        select {
+//	        case <-ctx.Done():
+//	            return ctx.Err()
+//	        case <-time.After(policy.NextDelay(trogonErr, attempt)):
+//	        }
+//	        continue
+//	    }
+//	    if waitErr := trogonerror.Wait(ctx, err); waitErr != nil {
+//	        return waitErr
+//	    }
+//	}
+func Wait(ctx context.Context, err error) error {
+	var trogonErr *TrogonError
+	if !errors.As(err, &trogonErr) || !trogonErr.IsRetryable() {
+		return err
+	}
+
+	var delay time.Duration
+	if trogonErr.retryInfo != nil {
+		delay = trogonErr.retryInfo.Delay(now())
+	}
+
+	return sleep(ctx, delay)
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RetryPolicy computes client backoff delays using exponential growth with jitter,
+// never waiting less than the error's RetryInfo
+type RetryPolicy struct {
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	multiplier float64
+	jitter     float64
+	random     func() float64
+}
+
+// RetryPolicyOption represents options for retry policy construction
+type RetryPolicyOption func(*RetryPolicy)
+
+// NewRetryPolicy creates a retry policy. Defaults: 100ms base delay, 30s max delay, multiplier 2 and 20% jitter.
+func NewRetryPolicy(options ...RetryPolicyOption) *RetryPolicy {
+	policy := &RetryPolicy{
+		baseDelay:  100 * time.Millisecond,
+		maxDelay:   30 * time.Second,
+		multiplier: 2,
+		jitter:     0.2,
+		random:     rand.Float64,
+	}
+
+	for _, option := range options {
+		option(policy)
+	}
+
+	return policy
+}
+
+// RetryPolicyWithBaseDelay sets the delay used for the first retry
+func RetryPolicyWithBaseDelay(delay time.Duration) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.baseDelay = delay
+	}
+}
+
+// RetryPolicyWithMaxDelay caps the exponential backoff; server-provided RetryInfo may still exceed it
+func RetryPolicyWithMaxDelay(delay time.Duration) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.maxDelay = delay
+	}
+}
+
+// RetryPolicyWithMultiplier sets the growth factor applied on every attempt
+func RetryPolicyWithMultiplier(multiplier float64) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.multiplier = multiplier
+	}
+}
+
+// RetryPolicyWithJitter sets the fraction (0 to 1) of the backoff that is randomized away
+func RetryPolicyWithJitter(jitter float64) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.jitter = min(max(jitter, 0), 1)
+	}
+}
+
+// RetryPolicyWithRandom sets the source of jitter randomness, returning values in [0, 1)
+func RetryPolicyWithRandom(random func() float64) RetryPolicyOption {
+	return func(p *RetryPolicy) {
+		p.random = random
+	}
+}
+
+// NextDelay returns the delay before the given retry attempt, starting at 1.
+// The exponential backoff is reduced by up to the jitter fraction and never drops below the delay
+// the error's RetryInfo requests for that attempt, see RetryInfo.AttemptDelay.
+func (p *RetryPolicy) NextDelay(err *TrogonError, attempt int) time.Duration {
+	backoff := float64(p.baseDelay) * math.Pow(p.multiplier, float64(max(attempt, 1)-1))
+	if p.maxDelay > 0 {
+		backoff = min(backoff, float64(p.maxDelay))
+	}
+	backoff = min(backoff, math.MaxInt64)
+	backoff -= backoff * p.jitter * p.random()
+
+	delay := durationOf(backoff)
+	if err != nil && err.retryInfo != nil {
+		floor, _ := err.retryInfo.AttemptDelay(attempt, now())
+		delay = max(delay, floor)
+	}
+	return delay
+}
@@ -0,0 +1,458 @@
+package trogonerror_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	t.Run("Code classification", func(t *testing.T) {
+		tests := []struct {
+			code      trogonerror.Code
+			retryable bool
+		}{
+			{trogonerror.CodeCancelled, false},
+			{trogonerror.CodeUnknown, false},
+			{trogonerror.CodeInvalidArgument, false},
+			{trogonerror.CodeDeadlineExceeded, true},
+			{trogonerror.CodeNotFound, false},
+			{trogonerror.CodeAlreadyExists, false},
+			{trogonerror.CodePermissionDenied, false},
+			{trogonerror.CodeUnauthenticated, false},
+			{trogonerror.CodeResourceExhausted, true},
+			{trogonerror.CodeFailedPrecondition, false},
+			{trogonerror.CodeAborted, true},
+			{trogonerror.CodeOutOfRange, false},
+			{trogonerror.CodeUnimplemented, false},
+			{trogonerror.CodeInternal, false},
+			{trogonerror.CodeUnavailable, true},
+			{trogonerror.CodeDataLoss, false},
+		}
+
+		for _, tt := range tests {
+			assert.Equal(t, tt.retryable, tt.code.IsRetryable(), tt.code.String())
+
+			err := trogonerror.NewError("shopify.api", "REQUEST_FAILED", trogonerror.WithCode(tt.code))
+			assert.Equal(t, tt.retryable, err.IsRetryable(), tt.code.String())
+		}
+	})
+
+	t.Run("RetryInfo makes an error retryable", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.inventory", "SYNC_IN_PROGRESS",
+			trogonerror.WithCode(trogonerror.CodeFailedPrecondition),
+			trogonerror.WithRetryInfoDuration(5*time.Second))
+
+		assert.True(t, err.IsRetryable())
+	})
+
+	t.Run("WithRetryable overrides the classification", func(t *testing.T) {
+		permanent := trogonerror.NewError("shopify.payments", "CARD_DECLINED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithRetryable(false))
+		assert.False(t, permanent.IsRetryable())
+
+		transient := trogonerror.NewError("shopify.payments", "GATEWAY_GLITCH",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithRetryable(true))
+		assert.True(t, transient.IsRetryable())
+	})
+
+	t.Run("WithChangeRetryable overrides the copy only", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+		modified := original.WithChanges(trogonerror.WithChangeRetryable(false))
+
+		assert.True(t, original.IsRetryable())
+		assert.False(t, modified.IsRetryable())
+	})
+
+	t.Run("Package-level IsRetryable unwraps the chain", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+		assert.True(t, trogonerror.IsRetryable(fmt.Errorf("charge card: %w", err)))
+		assert.False(t, trogonerror.IsRetryable(errors.New("connection refused")))
+		assert.False(t, trogonerror.IsRetryable(nil))
+	})
+}
+
+func TestTransient(t *testing.T) {
+	t.Run("Transient without retry info", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.search", "INDEX_REBUILDING",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithTransient(true))
+
+		assert.True(t, err.IsTransient())
+		assert.False(t, err.IsPermanent())
+		assert.True(t, err.IsRetryable())
+		assert.Nil(t, err.RetryInfo())
+	})
+
+	t.Run("Permanent overrides a retryable code", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "ACCOUNT_CLOSED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithTransient(false))
+
+		assert.True(t, err.IsPermanent())
+		assert.False(t, err.IsRetryable())
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "GATEWAY_TIMEOUT", trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+		assert.False(t, err.IsTransient())
+		assert.False(t, err.IsPermanent())
+		assert.True(t, err.IsRetryable())
+	})
+
+	t.Run("Serialized for remote clients", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "ACCOUNT_CLOSED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable)).
+			WithChanges(trogonerror.WithChangeTransient(false))
+
+		data, marshalErr := json.Marshal(err)
+		var decoded trogonerror.TrogonError
+		unmarshalErr := json.Unmarshal(data, &decoded)
+
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, unmarshalErr)
+		assert.Contains(t, string(data), `"transient":false`)
+		assert.True(t, decoded.IsPermanent())
+		assert.False(t, decoded.IsRetryable())
+	})
+}
+
+func TestRetryInfoDelay(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+
+	t.Run("Retry offset is returned as is", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(30*time.Second))
+
+		assert.Equal(t, 30*time.Second, err.RetryInfo().Delay(now))
+	})
+
+	t.Run("Retry time is converted relative to now", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.maintenance", "SERVICE_UNAVAILABLE",
+			trogonerror.WithRetryTime(now.Add(5*time.Minute)))
+
+		assert.Equal(t, 5*time.Minute, err.RetryInfo().Delay(now))
+	})
+
+	t.Run("Negative delays are clamped to zero", func(t *testing.T) {
+		past := trogonerror.NewError("shopify.maintenance", "SERVICE_UNAVAILABLE",
+			trogonerror.WithRetryTime(now.Add(-time.Minute)))
+		assert.Equal(t, time.Duration(0), past.RetryInfo().Delay(now))
+
+		negative := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(-time.Second))
+		assert.Equal(t, time.Duration(0), negative.RetryInfo().Delay(now))
+	})
+
+	t.Run("Empty retry info has no delay", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), trogonerror.RetryInfo{}.Delay(now))
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+
+	t.Run("Later attempts back off from the first wait", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithRetryBackoff(0, 2, 5*time.Second))
+
+		for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+			delay, ok := err.RetryInfo().AttemptDelay(attempt, now)
+			assert.True(t, ok)
+			assert.Equal(t, want, delay, "attempt %d", attempt)
+		}
+	})
+
+	t.Run("Max attempts include the original request", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithRetryBackoff(3, 0, 0),
+			trogonerror.WithRetryInfoDuration(time.Second))
+
+		delay, ok := err.RetryInfo().AttemptDelay(2, now)
+		assert.True(t, ok)
+		assert.Equal(t, time.Second, delay)
+
+		_, ok = err.RetryInfo().AttemptDelay(3, now)
+		assert.False(t, ok)
+
+		assert.True(t, trogonerror.ShouldRetry(err, 2))
+		assert.False(t, trogonerror.ShouldRetry(err, 3))
+	})
+
+	t.Run("ShouldRetry follows retryability without a contract", func(t *testing.T) {
+		unavailable := trogonerror.NewError("shopify.api", "BACKEND_UNAVAILABLE",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+		notFound := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound))
+
+		assert.True(t, trogonerror.ShouldRetry(fmt.Errorf("charge card: %w", unavailable), 100))
+		assert.False(t, trogonerror.ShouldRetry(notFound, 1))
+		assert.False(t, trogonerror.ShouldRetry(errors.New("connection refused"), 1))
+	})
+
+	t.Run("Changing the wait keeps the contract", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithRetryBackoff(5, 1.5, time.Minute))
+
+		modified := original.WithChanges(trogonerror.WithChangeRetryTime(now.Add(time.Minute)))
+
+		assert.Equal(t, 5, modified.RetryInfo().MaxAttempts())
+		assert.Equal(t, 1.5, modified.RetryInfo().BackoffMultiplier())
+		assert.Equal(t, time.Minute, modified.RetryInfo().MaxDelay())
+		assert.NotNil(t, original.RetryInfo().RetryOffset())
+	})
+
+	t.Run("Changing the contract leaves the original untouched", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second))
+
+		modified := original.WithChanges(trogonerror.WithChangeRetryBackoff(4, 2, 0))
+
+		assert.Equal(t, 0, original.RetryInfo().MaxAttempts())
+		assert.Equal(t, 4, modified.RetryInfo().MaxAttempts())
+		assert.Equal(t, time.Second, *modified.RetryInfo().RetryOffset())
+	})
+
+	t.Run("The contract survives serialization", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithRetryBackoff(5, 2, 30*time.Second))
+
+		data, marshalErr := json.Marshal(err)
+		assert.NoError(t, marshalErr)
+		assert.Contains(t, string(data), `"maxAttempts":5,"backoffMultiplier":2,"maxDelay":"30s"`)
+		assert.NoError(t, trogonerror.ValidatePayload(data))
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Empty(t, trogonerror.Diff(err, &decoded))
+	})
+
+	t.Run("Validate rejects a broken contract", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithRetryBackoff(-1, 0.5, 0))
+
+		assert.ErrorIs(t, err.Validate(), trogonerror.ErrInvalidRetryInfo)
+	})
+
+	t.Run("Strict construction accepts the contract before the wait", func(t *testing.T) {
+		_, err := trogonerror.NewErrorE("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryBackoff(3, 2, 0),
+			trogonerror.WithRetryInfoDuration(time.Second))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("NextDelay honors the contract as a floor", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy(trogonerror.RetryPolicyWithJitter(0))
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithRetryBackoff(0, 3, 0))
+
+		assert.Equal(t, 9*time.Second, policy.NextDelay(err, 3))
+	})
+
+	t.Run("Large attempts saturate instead of overflowing", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithRetryBackoff(0, 2, 0))
+
+		for _, attempt := range []int{64, 2000} {
+			delay, ok := err.RetryInfo().AttemptDelay(attempt, now)
+			assert.True(t, ok)
+			assert.Equal(t, time.Duration(math.MaxInt64), delay, "attempt %d", attempt)
+		}
+
+		policy := trogonerror.NewRetryPolicy(
+			trogonerror.RetryPolicyWithMaxDelay(0),
+			trogonerror.RetryPolicyWithRandom(func() float64 { return 0.5 }))
+		assert.Equal(t, time.Duration(math.MaxInt64), policy.NextDelay(err, 2000))
+		assert.Positive(t, policy.NextDelay(nil, 2000))
+	})
+}
+
+func TestWait(t *testing.T) {
+	t.Run("Waits for the retry offset", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithRetryInfoDuration(20*time.Millisecond))
+
+		start := time.Now()
+		assert.NoError(t, trogonerror.Wait(context.Background(), err))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("Returns immediately for retryable errors without retry info", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "BACKEND_UNAVAILABLE",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+		assert.NoError(t, trogonerror.Wait(context.Background(), err))
+	})
+
+	t.Run("Returns the error itself when it is not retryable", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound))
+		assert.Same(t, err, trogonerror.Wait(context.Background(), err))
+
+		plain := errors.New("connection refused")
+		assert.Same(t, plain, trogonerror.Wait(context.Background(), plain))
+	})
+
+	t.Run("Returns the context error when cancelled", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithRetryInfoDuration(time.Hour))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, trogonerror.Wait(ctx, err), context.DeadlineExceeded)
+	})
+
+	t.Run("Returns the context error when already done", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.api", "BACKEND_UNAVAILABLE",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, trogonerror.Wait(ctx, err), context.Canceled)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	noJitter := trogonerror.RetryPolicyWithJitter(0)
+
+	t.Run("Backoff grows exponentially", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy(noJitter)
+
+		assert.Equal(t, 100*time.Millisecond, policy.NextDelay(nil, 1))
+		assert.Equal(t, 200*time.Millisecond, policy.NextDelay(nil, 2))
+		assert.Equal(t, 400*time.Millisecond, policy.NextDelay(nil, 3))
+	})
+
+	t.Run("Backoff is capped at the max delay", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy(noJitter,
+			trogonerror.RetryPolicyWithBaseDelay(time.Second),
+			trogonerror.RetryPolicyWithMultiplier(3),
+			trogonerror.RetryPolicyWithMaxDelay(5*time.Second))
+
+		assert.Equal(t, 3*time.Second, policy.NextDelay(nil, 2))
+		assert.Equal(t, 5*time.Second, policy.NextDelay(nil, 3))
+		assert.Equal(t, 5*time.Second, policy.NextDelay(nil, 50))
+	})
+
+	t.Run("RetryInfo acts as a floor", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy(noJitter,
+			trogonerror.RetryPolicyWithMaxDelay(time.Second))
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithCode(trogonerror.CodeResourceExhausted),
+			trogonerror.WithRetryInfoDuration(10*time.Second))
+
+		assert.Equal(t, 10*time.Second, policy.NextDelay(err, 1))
+	})
+
+	t.Run("Backoff wins when larger than RetryInfo", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy(noJitter,
+			trogonerror.RetryPolicyWithBaseDelay(time.Second))
+		err := trogonerror.NewError("shopify.api", "RATE_LIMIT_EXCEEDED",
+			trogonerror.WithRetryInfoDuration(time.Second))
+
+		assert.Equal(t, 4*time.Second, policy.NextDelay(err, 3))
+	})
+
+	t.Run("Jitter randomizes part of the backoff", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy(
+			trogonerror.RetryPolicyWithBaseDelay(time.Second),
+			trogonerror.RetryPolicyWithJitter(0.5),
+			trogonerror.RetryPolicyWithRandom(func() float64 { return 0.5 }))
+
+		assert.Equal(t, 750*time.Millisecond, policy.NextDelay(nil, 1))
+	})
+
+	t.Run("Default jitter stays within bounds", func(t *testing.T) {
+		policy := trogonerror.NewRetryPolicy()
+
+		for range 100 {
+			delay := policy.NextDelay(nil, 2)
+			assert.GreaterOrEqual(t, delay, 160*time.Millisecond)
+			assert.LessOrEqual(t, delay, 200*time.Millisecond)
+		}
+	})
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestTemporaryAndTimeout(t *testing.T) {
+	t.Run("Temporary follows retryability", func(t *testing.T) {
+		unavailable := trogonerror.NewError("shopify.api", "BACKEND_UNAVAILABLE",
+			trogonerror.WithCode(trogonerror.CodeUnavailable))
+		notFound := trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound))
+		overridden := trogonerror.NewError("shopify.api", "BACKEND_UNAVAILABLE",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithRetryable(false))
+
+		assert.True(t, unavailable.Temporary())
+		assert.False(t, notFound.Temporary())
+		assert.False(t, overridden.Temporary())
+	})
+
+	t.Run("Timeout is true for DeadlineExceeded", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.database", "QUERY_TIMEOUT",
+			trogonerror.WithCode(trogonerror.CodeDeadlineExceeded))
+
+		assert.True(t, err.Timeout())
+	})
+
+	t.Run("Timeout detects wrapped timeout errors", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithWrap(fmt.Errorf("dial tcp: %w", timeoutError{})))
+
+		assert.True(t, err.Timeout())
+	})
+
+	t.Run("Timeout is false otherwise", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.database", "CONNECTION_FAILED",
+			trogonerror.WithCode(trogonerror.CodeUnavailable),
+			trogonerror.WithWrap(errors.New("connection refused")))
+
+		assert.False(t, err.Timeout())
+	})
+
+	t.Run("Satisfies net-style interfaces", func(t *testing.T) {
+		var err error = trogonerror.NewError("shopify.database", "QUERY_TIMEOUT",
+			trogonerror.WithCode(trogonerror.CodeDeadlineExceeded))
+
+		var netStyle interface {
+			Timeout() bool
+			Temporary() bool
+		}
+		assert.True(t, errors.As(err, &netStyle))
+		assert.True(t, netStyle.Timeout())
+		assert.True(t, netStyle.Temporary())
+	})
+}
@@ -0,0 +1,281 @@
+package trogonerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPayload reports a serialized error that does not conform to the JSON Schema
+var ErrInvalidPayload = errors.New("trogonerror: invalid payload")
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) needed to describe the wire format.
+// AdditionalProperties is either false or a *jsonSchema.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+
+	compiled *regexp.Regexp
+}
+
+func schemaString() *jsonSchema { return &jsonSchema{Type: "string"} }
+
+func schemaObject(required []string, properties map[string]*jsonSchema) *jsonSchema {
+	return &jsonSchema{Type: "object", Properties: properties, Required: required, AdditionalProperties: false}
+}
+
+func schemaArray(items *jsonSchema) *jsonSchema { return &jsonSchema{Type: "array", Items: items} }
+
+func schemaMap(values *jsonSchema) *jsonSchema {
+	return &jsonSchema{Type: "object", AdditionalProperties: values}
+}
+
+func schemaPattern(pattern *regexp.Regexp) *jsonSchema {
+	return &jsonSchema{Type: "string", Pattern: pattern.String(), compiled: pattern}
+}
+
+func schemaRef(name string) *jsonSchema { return &jsonSchema{Ref: "#/$defs/" + name} }
+
+var retryOffsetPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?s$`)
+
+// wireSchema is generated from the same definitions the encoder and Validate use:
+// code and visibility names, and the domain and reason patterns
+var wireSchema = func() *jsonSchema {
+	var codes []string
+	for code := CodeCancelled; code <= CodeUnauthenticated; code++ {
+		codes = append(codes, code.String())
+	}
+	visibilities := []string{VisibilityInternal.String(), VisibilityPrivate.String(), VisibilityPublic.String()}
+	idempotencies := []string{IdempotencySafe.String(), IdempotencyRequiresKey.String(), IdempotencyUnsafe.String()}
+	helpLinkKinds := []string{HelpLinkRunbook.String(), HelpLinkDashboard.String(), HelpLinkSupport.String()}
+	dateTime := &jsonSchema{Type: "string", Format: "date-time"}
+	minSpecVersion, minAttempts, minCount := 1, 1, 0
+
+	return &jsonSchema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		Title:       "TrogonError",
+		Description: "A TrogonError serialized by MarshalJSON",
+		Ref:         "#/$defs/error",
+		Defs: map[string]*jsonSchema{
+			"error": schemaObject([]string{"specversion", "code", "message", "domain", "reason", "visibility"}, map[string]*jsonSchema{
+				"specversion":       {Type: "integer", Minimum: &minSpecVersion},
+				"code":              {Type: "string", Enum: codes},
+				"message":           schemaString(),
+				"domain":            schemaPattern(domainPattern),
+				"reason":            schemaPattern(reasonPattern),
+				"metadata":          schemaMap(schemaRef("metadataValue")),
+				"causes":            schemaArray(schemaRef("error")),
+				"visibility":        schemaRef("visibility"),
+				"subject":           schemaString(),
+				"id":                schemaString(),
+				"time":              dateTime,
+				"help":              schemaObject([]string{"links"}, map[string]*jsonSchema{"links": schemaArray(schemaRef("helpLink"))}),
+				"debugInfo":         schemaRef("debugInfo"),
+				"localizedMessage":  schemaRef("localizedMessage"),
+				"localizedMessages": schemaArray(schemaRef("localizedMessage")),
+				"retryInfo": schemaObject(nil, map[string]*jsonSchema{
+					"retryOffset":       schemaPattern(retryOffsetPattern),
+					"retryTime":         dateTime,
+					"maxAttempts":       {Type: "integer", Minimum: &minAttempts},
+					"backoffMultiplier": {Type: "number"},
+					"maxDelay":          schemaPattern(retryOffsetPattern),
+				}),
+				"rateLimitInfo": schemaObject([]string{"limit", "remaining"}, map[string]*jsonSchema{
+					"limit":     {Type: "integer", Minimum: &minCount},
+					"remaining": {Type: "integer", Minimum: &minCount},
+					"window":    schemaPattern(retryOffsetPattern),
+					"resetTime": dateTime,
+				}),
+				"deadlineInfo": schemaObject([]string{"deadline", "elapsed"}, map[string]*jsonSchema{
+					"deadline": dateTime,
+					"elapsed":  schemaPattern(retryOffsetPattern),
+					"stages": schemaArray(schemaObject([]string{"name", "duration"}, map[string]*jsonSchema{
+						"name":     schemaString(),
+						"duration": schemaPattern(retryOffsetPattern),
+					})),
+				}),
+				"authInfo": schemaObject(nil, map[string]*jsonSchema{
+					"scheme":         schemaString(),
+					"requiredScopes": schemaArray(schemaString()),
+					"requiredRoles":  schemaArray(schemaString()),
+					"principalClass": schemaString(),
+				}),
+				"sourceId":      schemaString(),
+				"wrappedError":  schemaString(),
+				"tags":          schemaArray(schemaString()),
+				"operations":    schemaArray(schemaString()),
+				"owner":         schemaString(),
+				"publicMessage": schemaString(),
+				"transient":     {Type: "boolean"},
+				"idempotency":   {Type: "string", Enum: idempotencies},
+			}),
+			"visibility":       {Type: "string", Enum: visibilities},
+			"localizedMessage": schemaObject([]string{"locale", "message"}, map[string]*jsonSchema{"locale": schemaString(), "message": schemaString()}),
+			"metadataValue":    schemaObject([]string{"value", "visibility"}, map[string]*jsonSchema{"value": schemaString(), "visibility": schemaRef("visibility")}),
+			"helpLink": schemaObject([]string{"description", "url"}, map[string]*jsonSchema{
+				"description": schemaString(),
+				"url":         schemaString(),
+				"kind":        {Type: "string", Enum: helpLinkKinds},
+			}),
+			"debugInfo": schemaObject(nil, map[string]*jsonSchema{
+				"stackEntries": schemaArray(schemaString()),
+				"detail":       schemaString(),
+				"goroutines":   schemaString(),
+				"source":       schemaString(),
+				"runtime": schemaObject([]string{"goos", "goarch", "goVersion", "gomaxprocs"}, map[string]*jsonSchema{
+					"goos":       schemaString(),
+					"goarch":     schemaString(),
+					"goVersion":  schemaString(),
+					"gomaxprocs": {Type: "integer"},
+					"env":        schemaMap(schemaString()),
+				}),
+			}),
+		},
+	}
+}()
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of the payloads produced by MarshalJSON,
+// for consumers in other languages. The same document is shipped as trogonerror.schema.json.
+func JSONSchema() []byte {
+	data, err := json.MarshalIndent(wireSchema, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return append(data, '\n')
+}
+
+// ValidatePayload checks that data is a serialized error conforming to JSONSchema.
+// All violations are reported together, each wrapping ErrInvalidPayload with the JSON path.
+func ValidatePayload(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	return errors.Join(wireSchema.validate(payload, "$", wireSchema.Defs)...)
+}
+
+func (s *jsonSchema) validate(value any, path string, defs map[string]*jsonSchema) []error {
+	if s.Ref != "" {
+		return defs[strings.TrimPrefix(s.Ref, "#/$defs/")].validate(value, path, defs)
+	}
+
+	invalid := func(format string, args ...any) []error {
+		return []error{fmt.Errorf("%w: %s: %s", ErrInvalidPayload, path, fmt.Sprintf(format, args...))}
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return invalid("must be an object")
+		}
+		return s.validateObject(object, path, defs)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return invalid("must be an array")
+		}
+		var errs []error
+		for i, item := range array {
+			errs = append(errs, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), defs)...)
+		}
+		return errs
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return invalid("must be an integer")
+		}
+		n, err := number.Int64()
+		if err != nil {
+			return invalid("must be an integer")
+		}
+		if s.Minimum != nil && n < int64(*s.Minimum) {
+			return invalid("must be at least %d", *s.Minimum)
+		}
+		return nil
+	case "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return invalid("must be a number")
+		}
+		if _, err := number.Float64(); err != nil {
+			return invalid("must be a number")
+		}
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid("must be a boolean")
+		}
+		return nil
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return invalid("must be a string")
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return invalid("%q is not one of %s", str, strings.Join(s.Enum, ", "))
+		}
+		if s.compiled != nil && !s.compiled.MatchString(str) {
+			return invalid("%q does not match %s", str, s.Pattern)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return invalid("%q is not an RFC 3339 date-time", str)
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (s *jsonSchema) validateObject(object map[string]any, path string, defs map[string]*jsonSchema) []error {
+	var errs []error
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, fmt.Errorf("%w: %s: missing required property %q", ErrInvalidPayload, path, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		childPath := path + "." + name
+		if property, ok := s.Properties[name]; ok {
+			errs = append(errs, property.validate(object[name], childPath, defs)...)
+			continue
+		}
+		switch additional := s.AdditionalProperties.(type) {
+		case *jsonSchema:
+			errs = append(errs, additional.validate(object[name], fmt.Sprintf("%s[%q]", path, name), defs)...)
+		case bool:
+			if !additional {
+				errs = append(errs, fmt.Errorf("%w: %s: unknown property", ErrInvalidPayload, childPath))
+			}
+		}
+	}
+	return errs
+}
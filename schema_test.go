@@ -0,0 +1,84 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateSchema = flag.Bool("update-schema", false, "regenerate trogonerror.schema.json")
+
+func TestJSONSchema(t *testing.T) {
+	if *updateSchema {
+		assert.NoError(t, os.WriteFile("trogonerror.schema.json", trogonerror.JSONSchema(), 0o644))
+	}
+
+	shipped, err := os.ReadFile("trogonerror.schema.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(trogonerror.JSONSchema()), string(shipped), "run go test -run TestJSONSchema -update-schema")
+}
+
+func TestValidatePayload(t *testing.T) {
+	t.Run("Payloads emitted by MarshalJSON conform", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithSubject("/orderId"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+			trogonerror.WithHelpLink("Order docs", "https://shopify.dev/docs/orders"),
+			trogonerror.WithRetryInfoDuration(1500*time.Millisecond),
+			trogonerror.WithLocalizedMessage("es-ES", "Pedido no encontrado"),
+			trogonerror.WithStackTrace(),
+			trogonerror.WithRuntimeInfo("HOME"),
+			trogonerror.WithDebugDetail("lookup failed"),
+			trogonerror.WithWrap(errors.New("sql: no rows in result set")),
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "ROW_MISSING",
+				trogonerror.WithRetryTime(time.Date(2024, 1, 15, 10, 35, 0, 0, time.UTC)))))
+
+		data, marshalErr := err.MarshalJSONFor(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, trogonerror.ValidatePayload(data))
+	})
+
+	t.Run("Reports every violation with its path", func(t *testing.T) {
+		err := trogonerror.ValidatePayload([]byte(`{
+			"specversion": 0,
+			"code": "TEAPOT",
+			"domain": "Shopify Orders",
+			"reason": "ORDER_NOT_FOUND",
+			"visibility": "PUBLIC",
+			"metadata": {"orderId": {"value": 1, "visibility": "PUBLIC"}},
+			"causes": [{"specversion": 1, "code": "UNKNOWN", "message": "", "domain": "db", "reason": "X", "visibility": "SECRET"}],
+			"retryInfo": {"retryOffset": "5 minutes"},
+			"time": "yesterday",
+			"extra": true
+		}`))
+
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidPayload)
+		for _, violation := range []string{
+			`$: missing required property "message"`,
+			`$.specversion: must be at least 1`,
+			`$.code: "TEAPOT" is not one of CANCELLED`,
+			`$.domain: "Shopify Orders" does not match`,
+			`$.metadata["orderId"].value: must be a string`,
+			`$.causes[0].visibility: "SECRET" is not one of INTERNAL, PRIVATE, PUBLIC`,
+			`$.retryInfo.retryOffset: "5 minutes" does not match`,
+			`$.time: "yesterday" is not an RFC 3339 date-time`,
+			`$.extra: unknown property`,
+		} {
+			assert.ErrorContains(t, err, violation)
+		}
+	})
+
+	t.Run("Malformed JSON", func(t *testing.T) {
+		assert.ErrorIs(t, trogonerror.ValidatePayload([]byte(`{`)), trogonerror.ErrInvalidPayload)
+	})
+}
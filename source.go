@@ -0,0 +1,68 @@
+package trogonerror
+
+import (
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// PodNameEnv is the environment variable conventionally populated with the pod name
+// through the Kubernetes downward API
+const PodNameEnv = "POD_NAME"
+
+var (
+	defaultSourceID atomic.Pointer[string]
+	hostname        = sync.OnceValue(func() string {
+		name, _ := os.Hostname()
+		return name
+	})
+)
+
+// SetDefaultSourceID sets the source ID applied to every error created without one.
+// Passing an empty string disables the default.
+func SetDefaultSourceID(sourceID string) {
+	if sourceID == "" {
+		defaultSourceID.Store(nil)
+		return
+	}
+	defaultSourceID.Store(&sourceID)
+}
+
+// DetectSourceID derives a source ID for the running process.
+// It checks the given environment variables in order, then PodNameEnv, then the hostname.
+func DetectSourceID(envKeys ...string) string {
+	for _, key := range slices.Concat(envKeys, []string{PodNameEnv}) {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return hostname()
+}
+
+// WithSourceFromEnv sets the source ID from the given environment variable, leaving it unchanged if unset
+func WithSourceFromEnv(key string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if value := os.Getenv(key); value != "" {
+			e.sourceID = value
+		}
+	})
+}
+
+// WithSourceFromHostname sets the source ID to the machine hostname
+func WithSourceFromHostname() ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if name := hostname(); name != "" {
+			e.sourceID = name
+		}
+	})
+}
+
+func applyDefaultSourceID(e *TrogonError) {
+	if e.sourceID != "" {
+		return
+	}
+	if sourceID := defaultSourceID.Load(); sourceID != nil {
+		e.sourceID = *sourceID
+	}
+}
@@ -0,0 +1,61 @@
+package trogonerror_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceID(t *testing.T) {
+	t.Run("WithSourceFromEnv reads the given variable", func(t *testing.T) {
+		t.Setenv("SERVICE_INSTANCE", "payment-service-prod-01")
+
+		err := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithSourceFromEnv("SERVICE_INSTANCE"))
+
+		assert.Equal(t, "payment-service-prod-01", err.SourceID())
+	})
+
+	t.Run("WithSourceFromEnv leaves source ID unchanged when unset", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithSourceID("payment-service"),
+			trogonerror.WithSourceFromEnv("TROGON_UNSET_VARIABLE"))
+
+		assert.Equal(t, "payment-service", err.SourceID())
+	})
+
+	t.Run("WithSourceFromHostname uses the hostname", func(t *testing.T) {
+		expected, _ := os.Hostname()
+
+		err := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithSourceFromHostname())
+
+		assert.Equal(t, expected, err.SourceID())
+	})
+
+	t.Run("DetectSourceID prefers explicit variables, then pod name, then hostname", func(t *testing.T) {
+		hostname, _ := os.Hostname()
+		t.Setenv(trogonerror.PodNameEnv, "")
+		assert.Equal(t, hostname, trogonerror.DetectSourceID())
+
+		t.Setenv(trogonerror.PodNameEnv, "checkout-7d9f8b-x2x4q")
+		assert.Equal(t, "checkout-7d9f8b-x2x4q", trogonerror.DetectSourceID())
+
+		t.Setenv("SERVICE_INSTANCE", "checkout-canary")
+		assert.Equal(t, "checkout-canary", trogonerror.DetectSourceID("SERVICE_INSTANCE"))
+	})
+
+	t.Run("SetDefaultSourceID applies to errors without a source ID", func(t *testing.T) {
+		trogonerror.SetDefaultSourceID("inventory-service")
+		t.Cleanup(func() { trogonerror.SetDefaultSourceID("") })
+
+		err := trogonerror.NewError("shopify.inventory", "NO_STOCK")
+		assert.Equal(t, "inventory-service", err.SourceID())
+
+		explicit := trogonerror.NewError("shopify.inventory", "NO_STOCK",
+			trogonerror.WithSourceID("inventory-worker"))
+		assert.Equal(t, "inventory-worker", explicit.SourceID())
+	})
+}
@@ -0,0 +1,91 @@
+package trogonerror
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+// StackTrace returns the stack as return addresses, the representation used by
+// github.com/pkg/errors and sniffed for by error reporting SDKs such as Sentry
+func (d DebugInfo) StackTrace() []uintptr {
+	if len(d.stackFrames) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, 0, len(d.stackFrames))
+	for i, frame := range d.stackFrames {
+		// Inlined calls share a PC with their caller; CallersFrames expands them again
+		if i > 0 && frame.PC == d.stackFrames[i-1].PC {
+			continue
+		}
+		// runtime.Frame holds the call instruction, pkg/errors frames hold the return address
+		pcs = append(pcs, frame.PC+1)
+	}
+	return pcs
+}
+
+// StackTrace returns the captured stack in the github.com/pkg/errors representation, or nil
+func (e TrogonError) StackTrace() []uintptr {
+	if e.debugInfo == nil {
+		return nil
+	}
+	return e.debugInfo.StackTrace()
+}
+
+// WithStackTraceFrom adopts the stack trace of the deepest error in err's chain exposing a
+// github.com/pkg/errors style StackTrace() method, so the origin of a wrapped error is kept.
+// The method is detected by reflection to avoid depending on pkg/errors.
+func WithStackTraceFrom(err error) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		var pcs []uintptr
+		for ; err != nil; err = errors.Unwrap(err) {
+			if trace := reflectedStackTrace(err); len(trace) > 0 {
+				pcs = trace
+			}
+		}
+
+		if len(pcs) > 0 {
+			setStackFrames(e, framesFromPCs(pcs))
+		}
+	})
+}
+
+// reflectedStackTrace calls a StackTrace() method returning a slice of uintptr-kinded values
+func reflectedStackTrace(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.NumOut() != 1 {
+		return nil
+	}
+	if out := methodType.Out(0); out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
+}
+
+func framesFromPCs(pcs []uintptr) []runtime.Frame {
+	frames := runtime.CallersFrames(pcs)
+	var stackFrames []runtime.Frame
+
+	for {
+		frame, more := frames.Next()
+		stackFrames = append(stackFrames, frame)
+
+		if !more {
+			break
+		}
+	}
+
+	return stackFrames
+}
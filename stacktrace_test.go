@@ -0,0 +1,89 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+// pkgErrorsFrame and pkgErrorsStack mirror the github.com/pkg/errors types
+type pkgErrorsFrame uintptr
+
+type pkgErrorsStack []pkgErrorsFrame
+
+type pkgErrorsError struct {
+	msg   string
+	stack pkgErrorsStack
+}
+
+func (e *pkgErrorsError) Error() string { return e.msg }
+
+func (e *pkgErrorsError) StackTrace() pkgErrorsStack { return e.stack }
+
+//go:noinline
+func newPkgErrorsError(msg string) error {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	stack := make(pkgErrorsStack, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = pkgErrorsFrame(pc)
+	}
+	return &pkgErrorsError{msg: msg, stack: stack}
+}
+
+func TestWithStackTraceFrom(t *testing.T) {
+	t.Run("Adopts the trace of a pkg/errors style error", func(t *testing.T) {
+		cause := fmt.Errorf("query orders: %w", newPkgErrorsError("connection reset"))
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_LOOKUP_FAILED",
+			trogonerror.WithWrap(cause),
+			trogonerror.WithStackTraceFrom(cause))
+
+		frames := err.DebugInfo().StackFrames()
+		assert.NotEmpty(t, frames)
+		assert.Contains(t, frames[0].Function, "TestWithStackTraceFrom")
+	})
+
+	t.Run("Leaves debug info untouched without a trace", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_LOOKUP_FAILED",
+			trogonerror.WithStackTraceFrom(errors.New("connection reset")))
+
+		assert.Nil(t, err.DebugInfo())
+	})
+
+	t.Run("Adopts traces from other TrogonErrors", func(t *testing.T) {
+		inner := trogonerror.NewError("shopify.database", "CONNECTION_FAILED", trogonerror.WithStackTrace())
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_LOOKUP_FAILED",
+			trogonerror.WithWrap(inner),
+			trogonerror.WithStackTraceFrom(inner))
+
+		assert.Equal(t, inner.DebugInfo().StackFrames()[0].Function, err.DebugInfo().StackFrames()[0].Function)
+		assert.Equal(t, inner.DebugInfo().StackFrames()[0].Line, err.DebugInfo().StackFrames()[0].Line)
+	})
+}
+
+func TestStackTrace(t *testing.T) {
+	t.Run("Round-trips through runtime.CallersFrames", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_LOOKUP_FAILED", trogonerror.WithStackTrace())
+
+		pcs := err.StackTrace()
+		assert.NotEmpty(t, pcs)
+
+		frames := runtime.CallersFrames(pcs)
+		first, _ := frames.Next()
+		want := err.DebugInfo().StackFrames()[0]
+		assert.Equal(t, want.Function, first.Function)
+		assert.Equal(t, want.Line, first.Line)
+	})
+
+	t.Run("Returns nil without debug info", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_LOOKUP_FAILED")
+
+		assert.Nil(t, err.StackTrace())
+	})
+}
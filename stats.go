@@ -0,0 +1,121 @@
+package trogonerror
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Stats counts created errors per domain, reason and code, so a service can report what is
+// failing right now without an external metrics stack. It is safe for concurrent use.
+//
+// Example usage:
+//
+//	stats := trogonerror.NewStats()
+//	trogonerror.RegisterCreateHook("stats", stats.Record)
+type Stats struct {
+	mu      sync.Mutex
+	entries map[statsKey]*StatsEntry
+}
+
+type statsKey struct {
+	domain string
+	reason string
+	code   Code
+}
+
+// StatsEntry is the number of errors seen for a domain, reason and code, when they were first
+// and last seen, and a summary of the most recent one
+type StatsEntry struct {
+	domain        string
+	reason        string
+	code          Code
+	count         int64
+	firstSeen     time.Time
+	lastSeen      time.Time
+	lastID        string
+	message       string
+	publicMessage string
+	visibility    Visibility
+}
+
+func (s StatsEntry) Domain() string       { return s.domain }
+func (s StatsEntry) Reason() string       { return s.reason }
+func (s StatsEntry) Code() Code           { return s.code }
+func (s StatsEntry) Count() int64         { return s.count }
+func (s StatsEntry) FirstSeen() time.Time { return s.firstSeen }
+func (s StatsEntry) LastSeen() time.Time  { return s.lastSeen }
+
+// LastID returns the ID of the most recent error, if it had one
+func (s StatsEntry) LastID() string { return s.lastID }
+
+// Message returns the message of the most recent error
+func (s StatsEntry) Message() string { return s.message }
+
+// Visibility returns the visibility of the most recent error
+func (s StatsEntry) Visibility() Visibility { return s.visibility }
+
+func (s StatsEntry) messageForAudience(audience Visibility) string {
+	if audience == VisibilityPublic && s.publicMessage != "" {
+		return s.publicMessage
+	}
+	return s.message
+}
+
+// NewStats creates an empty statistics registry
+func NewStats() *Stats {
+	return &Stats{entries: make(map[statsKey]*StatsEntry)}
+}
+
+// Record counts err, timestamped with the package clock. It has the CreateHook signature.
+func (s *Stats) Record(err *TrogonError) {
+	if err == nil {
+		return
+	}
+	key := statsKey{domain: err.domain, reason: err.reason, code: err.code}
+	timestamp := now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &StatsEntry{domain: key.domain, reason: key.reason, code: key.code, firstSeen: timestamp}
+		s.entries[key] = entry
+	}
+	entry.count++
+	entry.lastSeen = timestamp
+	entry.lastID = err.id
+	entry.message = err.Message()
+	entry.publicMessage = err.publicMessage
+	entry.visibility = err.visibility
+}
+
+// Snapshot returns a copy of the current counters, most recently seen first
+func (s *Stats) Snapshot() []StatsEntry {
+	s.mu.Lock()
+	snapshot := make([]StatsEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		snapshot = append(snapshot, *entry)
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(snapshot, func(a, b StatsEntry) int {
+		return cmp.Or(
+			b.lastSeen.Compare(a.lastSeen),
+			cmp.Compare(a.domain, b.domain),
+			cmp.Compare(a.reason, b.reason),
+			cmp.Compare(a.code, b.code),
+		)
+	})
+	return snapshot
+}
+
+// Reset clears all counters
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.entries)
+}
@@ -0,0 +1,105 @@
+package trogonerror
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+	"time"
+)
+
+type statsVarEntry struct {
+	Domain    string    `json:"domain"`
+	Reason    string    `json:"reason"`
+	Code      string    `json:"code"`
+	Count     int64     `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Var returns an expvar.Var rendering the current counters as a JSON array, most recently seen first.
+// Messages are left out, so the counters can be published on /debug/vars without leaking error details.
+//
+// Example usage:
+//
+//	expvar.Publish("trogonerror", stats.Var())
+func (s *Stats) Var() expvar.Var {
+	return expvar.Func(func() any {
+		snapshot := s.Snapshot()
+		entries := make([]statsVarEntry, len(snapshot))
+		for i, entry := range snapshot {
+			entries[i] = statsVarEntry{
+				Domain:    entry.domain,
+				Reason:    entry.reason,
+				Code:      entry.code.String(),
+				Count:     entry.count,
+				FirstSeen: entry.firstSeen,
+				LastSeen:  entry.lastSeen,
+			}
+		}
+		return entries
+	})
+}
+
+type statsHandler struct {
+	stats    *Stats
+	audience Visibility
+	limit    int
+}
+
+// StatsHandlerOption represents options for Stats.Handler
+type StatsHandlerOption func(*statsHandler)
+
+// StatsHandlerWithAudience sets who the page is rendered for. Errors less visible than the audience
+// are left out, and the public message replaces the message for a public audience. Defaults to VisibilityPublic.
+func StatsHandlerWithAudience(audience Visibility) StatsHandlerOption {
+	return func(h *statsHandler) {
+		h.audience = audience
+	}
+}
+
+// StatsHandlerWithLimit caps the number of rendered entries; zero or negative renders all of them
+func StatsHandlerWithLimit(limit int) StatsHandlerOption {
+	return func(h *statsHandler) {
+		h.limit = limit
+	}
+}
+
+// Handler returns an http.Handler rendering a plain-text summary of the errors seen, most recent first,
+// for quick debugging on environments without a full observability stack.
+//
+// Example usage:
+//
+//	mux.Handle("/debug/errors", stats.Handler(trogonerror.StatsHandlerWithAudience(trogonerror.VisibilityInternal)))
+func (s *Stats) Handler(options ...StatsHandlerOption) http.Handler {
+	handler := &statsHandler{stats: s, audience: VisibilityPublic}
+
+	for _, option := range options {
+		option(handler)
+	}
+
+	return handler
+}
+
+func (h *statsHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LAST SEEN\tCOUNT\tCODE\tDOMAIN\tREASON\tLAST ID\tMESSAGE")
+
+	rendered := 0
+	for _, entry := range h.stats.Snapshot() {
+		if entry.visibility < h.audience {
+			continue
+		}
+		if h.limit > 0 && rendered == h.limit {
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			entry.lastSeen.UTC().Format(time.RFC3339), entry.count, entry.code, entry.domain, entry.reason,
+			entry.lastID, entry.messageForAudience(h.audience))
+		rendered++
+	}
+	tw.Flush()
+}
@@ -0,0 +1,105 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	current := start
+	trogonerror.SetClock(func() time.Time { return current })
+	t.Cleanup(func() { trogonerror.SetClock(nil) })
+
+	stats := trogonerror.NewStats()
+	trogonerror.RegisterCreateHook("stats", stats.Record)
+	t.Cleanup(func() { trogonerror.RegisterCreateHook("stats", nil) })
+
+	notFound := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_NOT_FOUND", trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+	notFound.NewError()
+	current = start.Add(time.Minute)
+	trogonerror.NewError("shopify.payments", "CARD_DECLINED", trogonerror.WithCode(trogonerror.CodeFailedPrecondition))
+	current = start.Add(2 * time.Minute)
+	notFound.NewError()
+
+	snapshot := stats.Snapshot()
+
+	assert.Len(t, snapshot, 2)
+	assert.Equal(t, "shopify.orders", snapshot[0].Domain())
+	assert.Equal(t, "ORDER_NOT_FOUND", snapshot[0].Reason())
+	assert.Equal(t, trogonerror.CodeNotFound, snapshot[0].Code())
+	assert.Equal(t, int64(2), snapshot[0].Count())
+	assert.Equal(t, start, snapshot[0].FirstSeen())
+	assert.Equal(t, start.Add(2*time.Minute), snapshot[0].LastSeen())
+	assert.Equal(t, "CARD_DECLINED", snapshot[1].Reason())
+	assert.Equal(t, int64(1), snapshot[1].Count())
+
+	t.Run("Invalid errors are not recorded", func(t *testing.T) {
+		stats.Reset()
+
+		_, err := trogonerror.NewErrorE("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithCode(trogonerror.CodeNotFound), trogonerror.WithCode(trogonerror.CodeInternal))
+
+		assert.Error(t, err)
+		assert.Empty(t, stats.Snapshot())
+	})
+
+	t.Run("Removing the hook stops recording", func(t *testing.T) {
+		stats.Reset()
+		trogonerror.RegisterCreateHook("stats", nil)
+
+		trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND")
+
+		assert.Empty(t, stats.Snapshot())
+	})
+}
+
+func TestStatsHandler(t *testing.T) {
+	trogonerror.SetClock(func() time.Time { return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) })
+	t.Cleanup(func() { trogonerror.SetClock(nil) })
+
+	stats := trogonerror.NewStats()
+	stats.Record(trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.WithPublicMessage("We could not find that order"),
+		trogonerror.WithID("err_123")))
+	stats.Record(trogonerror.NewError("shopify.database", "DEADLOCK", trogonerror.WithCode(trogonerror.CodeAborted)))
+
+	t.Run("Public audience", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		stats.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+		body := recorder.Body.String()
+		assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, body, "ORDER_NOT_FOUND")
+		assert.Contains(t, body, "We could not find that order")
+		assert.Contains(t, body, "err_123")
+		assert.NotContains(t, body, "DEADLOCK")
+	})
+
+	t.Run("Internal audience", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		stats.Handler(trogonerror.StatsHandlerWithAudience(trogonerror.VisibilityInternal)).
+			ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+		assert.Contains(t, recorder.Body.String(), "DEADLOCK")
+		assert.NotContains(t, recorder.Body.String(), "We could not find that order")
+	})
+
+	t.Run("Expvar", func(t *testing.T) {
+		var entries []map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(stats.Var().String()), &entries))
+
+		assert.Len(t, entries, 2)
+		assert.Equal(t, float64(1), entries[0]["count"])
+		assert.NotContains(t, stats.Var().String(), "We could not find that order")
+	})
+}
@@ -0,0 +1,83 @@
+package trogonerror
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSubject reports a subject starting with "/" that is not a well-formed RFC 6901 JSON Pointer
+var ErrInvalidSubject = errors.New("trogonerror: invalid subject")
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// WithSubjectf sets the subject formatted according to a format specifier
+func WithSubjectf(format string, args ...any) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.subject = fmt.Sprintf(format, args...)
+	})
+}
+
+// SubjectPointer builds an RFC 6901 JSON Pointer subject from path segments, escaping "~" and "/"
+// in each of them. Integers become array indices; other values are formatted with fmt.Sprint.
+//
+//	trogonerror.SubjectPointer("items", 3, "sku") // "/items/3/sku"
+func SubjectPointer(segments ...any) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte('/')
+		switch s := segment.(type) {
+		case string:
+			b.WriteString(pointerEscaper.Replace(s))
+		case int:
+			b.WriteString(strconv.Itoa(s))
+		default:
+			b.WriteString(pointerEscaper.Replace(fmt.Sprint(s)))
+		}
+	}
+	return b.String()
+}
+
+// ParseSubjectPointer splits an RFC 6901 JSON Pointer into its unescaped segments,
+// the inverse of SubjectPointer: "/items/3/sku" yields items, 3 and sku
+func ParseSubjectPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w %q: a JSON Pointer must start with \"/\"", ErrInvalidSubject, pointer)
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		for j := 0; j < len(segment); j++ {
+			if segment[j] != '~' {
+				continue
+			}
+			if j+1 == len(segment) || (segment[j+1] != '0' && segment[j+1] != '1') {
+				return nil, fmt.Errorf("%w %q: \"~\" must be escaped as \"~0\"", ErrInvalidSubject, pointer)
+			}
+			j++
+		}
+		segments[i] = pointerUnescaper.Replace(segment)
+	}
+	return segments, nil
+}
+
+// ValidateSubject checks that a subject starting with "/" is a well-formed RFC 6901 JSON Pointer.
+// Other subjects are free-form and always valid.
+func ValidateSubject(subject string) error {
+	if !strings.HasPrefix(subject, "/") {
+		return nil
+	}
+	_, err := ParseSubjectPointer(subject)
+	return err
+}
+
+// SubjectPath returns the unescaped segments of the subject when it is a JSON Pointer,
+// so clients can map the error back onto their request structure
+func (e TrogonError) SubjectPath() ([]string, bool) {
+	segments, err := ParseSubjectPointer(e.subject)
+	return segments, err == nil
+}
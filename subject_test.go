@@ -0,0 +1,73 @@
+package trogonerror_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectPointer(t *testing.T) {
+	assert.Equal(t, "/items/3/sku", trogonerror.SubjectPointer("items", 3, "sku"))
+	assert.Equal(t, "/a~1b/m~0n", trogonerror.SubjectPointer("a/b", "m~n"))
+	assert.Equal(t, "/items/7", trogonerror.SubjectPointer("items", uint8(7)))
+	assert.Equal(t, "", trogonerror.SubjectPointer())
+}
+
+func TestWithSubjectf(t *testing.T) {
+	err := trogonerror.NewError("shopify.orders", "INVALID_LINE_ITEM",
+		trogonerror.WithSubjectf("/lineItems/%d/quantity", 2))
+
+	assert.Equal(t, "/lineItems/2/quantity", err.Subject())
+}
+
+func TestParseSubjectPointer(t *testing.T) {
+	segments, err := trogonerror.ParseSubjectPointer("/a~1b/m~0n/3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "m~n", "3"}, segments)
+
+	segments, err = trogonerror.ParseSubjectPointer(trogonerror.SubjectPointer("~01", "x/y"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"~01", "x/y"}, segments)
+
+	_, err = trogonerror.ParseSubjectPointer("/items/~2")
+	assert.ErrorIs(t, err, trogonerror.ErrInvalidSubject)
+	_, err = trogonerror.ParseSubjectPointer("/items~")
+	assert.ErrorIs(t, err, trogonerror.ErrInvalidSubject)
+	_, err = trogonerror.ParseSubjectPointer("items")
+	assert.ErrorIs(t, err, trogonerror.ErrInvalidSubject)
+}
+
+func TestSubjectValidation(t *testing.T) {
+	t.Run("Validate reports malformed pointers", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "INVALID_LINE_ITEM",
+			trogonerror.WithSubject("/lineItems/~/quantity"))
+
+		assert.ErrorIs(t, err.Validate(), trogonerror.ErrInvalidSubject)
+	})
+
+	t.Run("Free-form subjects are valid", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "INVALID_LINE_ITEM",
+			trogonerror.WithSubject("line item ~2"))
+
+		assert.NoError(t, err.Validate())
+		_, ok := err.SubjectPath()
+		assert.False(t, ok)
+	})
+
+	t.Run("NewErrorE rejects malformed pointers", func(t *testing.T) {
+		_, err := trogonerror.NewErrorE("shopify.orders", "INVALID_LINE_ITEM",
+			trogonerror.WithSubject("/lineItems/~3"))
+
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidSubject)
+	})
+
+	t.Run("SubjectPath returns the unescaped segments", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "INVALID_LINE_ITEM",
+			trogonerror.WithSubject(trogonerror.SubjectPointer("lineItems", 2, "quantity")))
+
+		path, ok := err.SubjectPath()
+		assert.True(t, ok)
+		assert.Equal(t, []string{"lineItems", "2", "quantity"}, path)
+	})
+}
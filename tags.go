@@ -0,0 +1,53 @@
+package trogonerror
+
+import "slices"
+
+// WithTags adds tags such as "billing", "customer-facing" or "slo-impacting", so alert routing
+// and dashboards can slice errors along dimensions other than the domain.
+// Tags are kept sorted and deduplicated; empty tags are ignored.
+func WithTags(tags ...string) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		e.tags = addTags(e.tags, tags)
+	})
+}
+
+// WithChangeTags adds tags to the copy
+func WithChangeTags(tags ...string) ChangeOption {
+	return mutable(func(e *TrogonError) {
+		e.tags = addTags(e.tags, tags)
+	})
+}
+
+// TemplateWithTags adds tags shared by every error created from the template
+func TemplateWithTags(tags ...string) TemplateOption {
+	return func(t *ErrorTemplate) {
+		t.tags = addTags(t.tags, tags)
+	}
+}
+
+// Tags returns a sorted copy of the error's tags
+func (e TrogonError) Tags() []string { return slices.Clone(e.tags) }
+
+// HasTag reports whether the error is tagged with tag
+func (e TrogonError) HasTag(tag string) bool {
+	_, found := slices.BinarySearch(e.tags, tag)
+	return found
+}
+
+// addTags returns a new sorted, deduplicated slice so slices shared with templates and
+// copies are never written to
+func addTags(existing, tags []string) []string {
+	merged := make([]string, 0, len(existing)+len(tags))
+	merged = append(merged, existing...)
+	for _, tag := range tags {
+		if tag != "" {
+			merged = append(merged, tag)
+		}
+	}
+	slices.Sort(merged)
+	merged = slices.Compact(merged)
+	if len(merged) == 0 {
+		return nil
+	}
+	return slices.Clip(merged)
+}
@@ -0,0 +1,51 @@
+package trogonerror_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	t.Run("Sorted and deduplicated", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.billing", "CHARGE_FAILED",
+			trogonerror.WithTags("slo-impacting", "billing"),
+			trogonerror.WithTags("billing", "", "customer-facing"))
+
+		assert.Equal(t, []string{"billing", "customer-facing", "slo-impacting"}, err.Tags())
+		assert.True(t, err.HasTag("billing"))
+		assert.False(t, err.HasTag("shipping"))
+	})
+
+	t.Run("Templates", func(t *testing.T) {
+		template := trogonerror.NewErrorTemplate("shopify.billing", "CHARGE_FAILED",
+			trogonerror.TemplateWithTags("billing"))
+
+		first := template.NewError(trogonerror.WithTags("customer-facing"))
+		second := template.NewError()
+		changed := second.WithChanges(trogonerror.WithChangeTags("slo-impacting"))
+
+		assert.Equal(t, []string{"billing", "customer-facing"}, first.Tags())
+		assert.Equal(t, []string{"billing"}, second.Tags())
+		assert.Equal(t, []string{"billing", "slo-impacting"}, changed.Tags())
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.billing", "CHARGE_FAILED", trogonerror.WithTags("billing", "slo-impacting"))
+
+		data, marshalErr := json.Marshal(err)
+		var decoded trogonerror.TrogonError
+		unmarshalErr := json.Unmarshal(data, &decoded)
+
+		assert.NoError(t, marshalErr)
+		assert.NoError(t, unmarshalErr)
+		assert.Contains(t, string(data), `"tags":["billing","slo-impacting"]`)
+		assert.Equal(t, err.Tags(), decoded.Tags())
+		assert.Contains(t, err.Error(), "\n  tags: billing, slo-impacting")
+		assert.Contains(t, trogonerror.KeyValueFormatter.Format(err), "tags=billing,slo-impacting")
+		assert.Equal(t, `tags: [billing slo-impacting] != [billing]`, trogonerror.Diff(err,
+			trogonerror.NewError("shopify.billing", "CHARGE_FAILED", trogonerror.WithTags("billing")), trogonerror.CompareIgnoringTime()))
+	})
+}
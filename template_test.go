@@ -3,6 +3,7 @@ package trogonerror_test
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -267,3 +268,115 @@ func ExampleErrorTemplate_reusable() {
 	// Same domain: true
 	// Same reason: true
 }
+
+func TestErrorTemplate_PrototypeIsolation(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_FAILED",
+		trogonerror.TemplateWithCode(trogonerror.CodeInternal),
+		trogonerror.TemplateWithHelpLink("Status Page", "https://status.shopify.com"),
+		trogonerror.TemplateWithHelpLink("Contact Support", "https://admin.shopify.com/support"))
+
+	first := template.NewError(
+		trogonerror.WithHelpLink("Order Details", "https://admin.shopify.com/orders/1"),
+		trogonerror.WithCode(trogonerror.CodeUnavailable))
+	second := template.NewError(
+		trogonerror.WithHelpLink("Order Details", "https://admin.shopify.com/orders/2"))
+	third := template.NewError()
+
+	assert.Len(t, first.Help().Links(), 3)
+	assert.Equal(t, "https://admin.shopify.com/orders/1", first.Help().Links()[2].URL())
+	assert.Len(t, second.Help().Links(), 3)
+	assert.Equal(t, "https://admin.shopify.com/orders/2", second.Help().Links()[2].URL())
+	assert.Len(t, third.Help().Links(), 2)
+	assert.Equal(t, trogonerror.CodeUnavailable, first.Code())
+	assert.Equal(t, trogonerror.CodeInternal, third.Code())
+}
+
+func BenchmarkErrorTemplate_NewError(b *testing.B) {
+	template := trogonerror.NewErrorTemplate("shopify.users", "NOT_FOUND",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound),
+		trogonerror.TemplateWithMessage("User not found"),
+		trogonerror.TemplateWithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.TemplateWithHelpLink("Customer Console", "https://admin.shopify.com/customers"))
+
+	b.Run("NoOptions", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = template.NewError()
+		}
+	})
+
+	b.Run("WithMetadata", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = template.NewError(
+				trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/1234567890"))
+		}
+	})
+}
+
+func TestErrorTemplate_Immutable(t *testing.T) {
+	help := trogonerror.NewError("shopify.orders", "ORDER_FAILED",
+		trogonerror.WithHelpLink("Status Page", "https://status.shopify.com")).Help()
+	template := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_FAILED",
+		trogonerror.TemplateWithHelp(*help),
+		trogonerror.TemplateWithTags("checkout"))
+
+	err := template.NewError()
+	err.Help().Links()[0] = trogonerror.HelpLink{}
+	err.Tags()[0] = "mutated"
+	template.Help().Links()[0] = trogonerror.HelpLink{}
+	help.Links()[0] = trogonerror.HelpLink{}
+
+	fresh := template.NewError()
+	assert.Equal(t, "https://status.shopify.com", fresh.Help().Links()[0].URL())
+	assert.Equal(t, []string{"checkout"}, fresh.Tags())
+}
+
+func TestErrorTemplate_ConcurrentNewError(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_FAILED",
+		trogonerror.TemplateWithCode(trogonerror.CodeInternal),
+		trogonerror.TemplateWithTags("checkout"),
+		trogonerror.TemplateWithHelpLink("Order", "https://admin.shopify.com/orders/{orderId}"))
+
+	var wg sync.WaitGroup
+	for i := range 64 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				orderID := fmt.Sprintf("%d-%d", i, j)
+				err := template.NewError(
+					trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", orderID),
+					trogonerror.WithHelpLink("Support", "https://admin.shopify.com/support"),
+					trogonerror.WithTags("retry"),
+					trogonerror.WithCode(trogonerror.CodeUnavailable))
+
+				links := err.Help().Links()
+				if !assert.Len(t, links, 2) {
+					return
+				}
+				assert.Equal(t, "https://admin.shopify.com/orders/"+orderID, links[0].URL())
+				assert.Equal(t, []string{"checkout", "retry"}, err.Tags())
+				_ = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	fresh := template.NewError()
+	assert.Equal(t, trogonerror.CodeInternal, fresh.Code())
+	assert.Equal(t, []string{"checkout"}, fresh.Tags())
+	assert.Empty(t, fresh.Help().Links())
+}
+
+func TestErrorTemplate_NewErrorf(t *testing.T) {
+	template := trogonerror.NewErrorTemplate("shopify.users", "NOT_FOUND",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+
+	err := template.NewErrorf("user %s not found after %d attempts", "42", 3,
+		trogonerror.WithSubject("/userId"))
+
+	assert.Equal(t, "user 42 not found after 3 attempts", err.Message())
+	assert.Equal(t, "/userId", err.Subject())
+	assert.True(t, template.Is(err))
+}
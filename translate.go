@@ -0,0 +1,90 @@
+package trogonerror
+
+import (
+	"errors"
+	"sync"
+)
+
+// Translator converts a third-party error, such as a driver or SDK error, into a TrogonError.
+// It reports false for errors it does not recognize.
+type Translator func(err error) (*TrogonError, bool)
+
+// ErrUntranslated is the template used by Translate for errors no translator recognized
+var ErrUntranslated = NewErrorTemplate("trogon.runtime", "UNTRANSLATED",
+	TemplateWithCode(CodeUnknown))
+
+type translatorEntry struct {
+	name       string
+	translator Translator
+}
+
+var (
+	translatorsMu sync.RWMutex
+	translators   []translatorEntry
+)
+
+// RegisterTranslator registers a translator used by Translate, tried in registration order.
+// Registering the same name again replaces the previous translator.
+// It is intended to be called during program initialization, typically by the package owning the errors.
+func RegisterTranslator(name string, translator Translator) {
+	translatorsMu.Lock()
+	defer translatorsMu.Unlock()
+
+	for i, entry := range translators {
+		if entry.name == name {
+			translators[i].translator = translator
+			return
+		}
+	}
+	translators = append(translators, translatorEntry{name: name, translator: translator})
+}
+
+// Translate converts any error into a TrogonError. It returns the first TrogonError already in
+// err's chain, otherwise the result of the first registered translator recognizing err, and
+// falls back to an ErrUntranslated error wrapping err. A nil error translates to nil.
+func Translate(err error) *TrogonError {
+	if err == nil {
+		return nil
+	}
+
+	if translated, ok := translate(err); ok {
+		return translated
+	}
+	return ErrUntranslated.NewError(WithWrap(err))
+}
+
+// translate returns the first TrogonError in err's chain or the result of the first registered
+// translator recognizing err
+func translate(err error) (*TrogonError, bool) {
+	var trogonErr *TrogonError
+	if errors.As(err, &trogonErr) && trogonErr != nil {
+		return trogonErr, true
+	}
+
+	translatorsMu.RLock()
+	defer translatorsMu.RUnlock()
+
+	for _, entry := range translators {
+		if translated, ok := entry.translator(err); ok && translated != nil {
+			return translated, true
+		}
+	}
+	return nil, false
+}
+
+// WithCauseFromError adds any error as a cause, so chains mixing TrogonErrors and other errors keep
+// every link. A TrogonError in err's chain is added as is and errors a registered translator recognizes
+// are added translated; other errors become a minimal ErrUntranslated cause with CodeUnknown,
+// their Error() text as message, wrapping them. A nil err adds nothing.
+func WithCauseFromError(err error) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		if err == nil {
+			return
+		}
+		cause, ok := translate(err)
+		if !ok {
+			cause = ErrUntranslated.NewError(WithErrorMessage(err), WithWrap(err))
+		}
+		WithCause(cause)(e)
+	})
+}
@@ -0,0 +1,93 @@
+package trogonerror
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"syscall"
+)
+
+var (
+	// ErrNetworkTimeout is the template for network operations that timed out
+	ErrNetworkTimeout = NewErrorTemplate("trogon.net", "TIMEOUT",
+		TemplateWithCode(CodeDeadlineExceeded))
+
+	// ErrConnectionRefused is the template for connections refused by the remote host
+	ErrConnectionRefused = NewErrorTemplate("trogon.net", "CONNECTION_REFUSED",
+		TemplateWithCode(CodeUnavailable))
+
+	// ErrConnectionReset is the template for connections reset or closed by the remote host
+	ErrConnectionReset = NewErrorTemplate("trogon.net", "CONNECTION_RESET",
+		TemplateWithCode(CodeUnavailable))
+
+	// ErrFilePermission is the template for file operations denied by the operating system
+	ErrFilePermission = NewErrorTemplate("trogon.os", "PERMISSION_DENIED",
+		TemplateWithCode(CodePermissionDenied))
+
+	// ErrFileNotExist is the template for file operations on paths that do not exist
+	ErrFileNotExist = NewErrorTemplate("trogon.os", "NOT_EXIST",
+		TemplateWithCode(CodeNotFound))
+)
+
+// The standard library translators are registered first, so they can be replaced by name
+func init() {
+	RegisterTranslator("context", func(err error) (*TrogonError, bool) { return FromContextError(err) })
+	RegisterTranslator("net", TranslateNetError)
+	RegisterTranslator("os", TranslateOSError)
+}
+
+// TranslateNetError translates network timeouts into ErrNetworkTimeout and refused or reset
+// connections into ErrConnectionRefused and ErrConnectionReset. The original error is wrapped
+// and the operation, network and address of a *net.OpError are recorded as internal metadata.
+// It is registered with Translate under the name "net".
+func TranslateNetError(err error) (*TrogonError, bool) {
+	var template *ErrorTemplate
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		template = ErrConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED), errors.Is(err, syscall.EPIPE):
+		template = ErrConnectionReset
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		template = ErrNetworkTimeout
+	default:
+		return nil, false
+	}
+
+	options := []ErrorOption{WithWrap(err)}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		options = append(options,
+			WithMetadataValue(VisibilityInternal, "op", opErr.Op),
+			WithMetadataValue(VisibilityInternal, "network", opErr.Net))
+		if opErr.Addr != nil {
+			options = append(options, WithMetadataValue(VisibilityInternal, "address", opErr.Addr.String()))
+		}
+	}
+	return template.NewError(options...), true
+}
+
+// TranslateOSError translates os.ErrPermission into ErrFilePermission and os.ErrNotExist into
+// ErrFileNotExist. The original error is wrapped and the operation and path of a *fs.PathError
+// are recorded as internal metadata. It is registered with Translate under the name "os".
+func TranslateOSError(err error) (*TrogonError, bool) {
+	var template *ErrorTemplate
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		template = ErrFilePermission
+	case errors.Is(err, fs.ErrNotExist):
+		template = ErrFileNotExist
+	default:
+		return nil, false
+	}
+
+	options := []ErrorOption{WithWrap(err)}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		options = append(options,
+			WithMetadataValue(VisibilityInternal, "op", pathErr.Op),
+			WithMetadataValue(VisibilityInternal, "path", pathErr.Path))
+	}
+	return template.NewError(options...), true
+}
@@ -0,0 +1,97 @@
+package trogonerror_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateNetError(t *testing.T) {
+	t.Run("Read timeout", func(t *testing.T) {
+		listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, listenErr)
+		defer listener.Close()
+
+		conn, dialErr := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, dialErr)
+		defer conn.Close()
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(-time.Second)))
+		_, readErr := conn.Read(make([]byte, 1))
+
+		err, ok := trogonerror.TranslateNetError(readErr)
+
+		assert.True(t, ok)
+		assert.True(t, trogonerror.ErrNetworkTimeout.Is(err))
+		assert.Equal(t, trogonerror.CodeDeadlineExceeded, err.Code())
+		assert.Equal(t, "read", err.Metadata()["op"].Value())
+		assert.Equal(t, "tcp", err.Metadata()["network"].Value())
+		assert.ErrorIs(t, err, readErr)
+	})
+
+	t.Run("Connection refused", func(t *testing.T) {
+		listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, listenErr)
+		address := listener.Addr().String()
+		listener.Close()
+
+		_, dialErr := net.Dial("tcp", address)
+
+		err, ok := trogonerror.TranslateNetError(dialErr)
+		assert.True(t, ok)
+		assert.True(t, trogonerror.ErrConnectionRefused.Is(err))
+		assert.Equal(t, trogonerror.CodeUnavailable, err.Code())
+		assert.Equal(t, address, err.Metadata()["address"].Value())
+	})
+
+	t.Run("Connection reset", func(t *testing.T) {
+		cause := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+		err, ok := trogonerror.TranslateNetError(fmt.Errorf("fetch: %w", cause))
+
+		assert.True(t, ok)
+		assert.True(t, trogonerror.ErrConnectionReset.Is(err))
+		assert.True(t, err.IsRetryable())
+	})
+
+	t.Run("Unrelated errors", func(t *testing.T) {
+		_, ok := trogonerror.TranslateNetError(os.ErrNotExist)
+		assert.False(t, ok)
+	})
+}
+
+func TestTranslateOSError(t *testing.T) {
+	t.Run("Not exist", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.json")
+		_, openErr := os.Open(path)
+
+		err, ok := trogonerror.TranslateOSError(openErr)
+
+		assert.True(t, ok)
+		assert.True(t, trogonerror.ErrFileNotExist.Is(err))
+		assert.Equal(t, trogonerror.CodeNotFound, err.Code())
+		assert.Equal(t, "open", err.Metadata()["op"].Value())
+		assert.Equal(t, path, err.Metadata()["path"].Value())
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Permission", func(t *testing.T) {
+		err, ok := trogonerror.TranslateOSError(&os.PathError{Op: "open", Path: "/etc/shadow", Err: os.ErrPermission})
+
+		assert.True(t, ok)
+		assert.True(t, trogonerror.ErrFilePermission.Is(err))
+		assert.Equal(t, trogonerror.CodePermissionDenied, err.Code())
+	})
+
+	t.Run("Registered with Translate", func(t *testing.T) {
+		assert.True(t, trogonerror.ErrFileNotExist.Is(trogonerror.Translate(os.ErrNotExist)))
+		assert.True(t, trogonerror.ErrContextDeadlineExceeded.Is(trogonerror.Translate(context.DeadlineExceeded)))
+	})
+}
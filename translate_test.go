@@ -0,0 +1,100 @@
+package trogonerror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+type stripeError struct {
+	code string
+}
+
+func (e *stripeError) Error() string { return "stripe: " + e.code }
+
+var errCardDeclined = trogonerror.NewErrorTemplate("shopify.payments", "CARD_DECLINED",
+	trogonerror.TemplateWithCode(trogonerror.CodeFailedPrecondition))
+
+func init() {
+	trogonerror.RegisterTranslator("stripe", func(err error) (*trogonerror.TrogonError, bool) {
+		var stripeErr *stripeError
+		if !errors.As(err, &stripeErr) || stripeErr.code != "card_declined" {
+			return nil, false
+		}
+		return errCardDeclined.NewError(trogonerror.WithWrap(err)), true
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("Registered translator", func(t *testing.T) {
+		cause := fmt.Errorf("charge: %w", &stripeError{code: "card_declined"})
+
+		err := trogonerror.Translate(cause)
+
+		assert.True(t, errCardDeclined.Is(err))
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("TrogonErrors in the chain are returned as is", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND")
+
+		assert.Same(t, original, trogonerror.Translate(fmt.Errorf("lookup: %w", original)))
+	})
+
+	t.Run("Falls back to an untranslated error", func(t *testing.T) {
+		cause := &stripeError{code: "rate_limit"}
+
+		err := trogonerror.Translate(cause)
+
+		assert.True(t, trogonerror.ErrUntranslated.Is(err))
+		assert.Equal(t, trogonerror.CodeUnknown, err.Code())
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.Nil(t, trogonerror.Translate(nil))
+	})
+
+	t.Run("Registering a name again replaces the translator", func(t *testing.T) {
+		errFirst := trogonerror.NewErrorTemplate("shopify.test", "FIRST")
+		errSecond := trogonerror.NewErrorTemplate("shopify.test", "SECOND")
+		sentinel := errors.New("sentinel")
+		translateTo := func(template *trogonerror.ErrorTemplate) trogonerror.Translator {
+			return func(err error) (*trogonerror.TrogonError, bool) {
+				if !errors.Is(err, sentinel) {
+					return nil, false
+				}
+				return template.NewError(trogonerror.WithWrap(err)), true
+			}
+		}
+
+		trogonerror.RegisterTranslator("test.replace", translateTo(errFirst))
+		trogonerror.RegisterTranslator("test.replace", translateTo(errSecond))
+
+		assert.True(t, errSecond.Is(trogonerror.Translate(sentinel)))
+	})
+}
+
+func TestWithCauseFromError(t *testing.T) {
+	original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND")
+	declined := &stripeError{code: "card_declined"}
+	plain := errors.New("dial tcp 10.0.0.7:5432: connection refused")
+
+	err := trogonerror.NewError("shopify.checkout", "CHECKOUT_FAILED",
+		trogonerror.WithCauseFromError(fmt.Errorf("lookup: %w", original)),
+		trogonerror.WithCauseFromError(declined),
+		trogonerror.WithCauseFromError(plain),
+		trogonerror.WithCauseFromError(nil))
+
+	causes := err.Causes()
+	assert.Len(t, causes, 3)
+	assert.Same(t, original, causes[0])
+	assert.True(t, errCardDeclined.Is(causes[1]))
+	assert.True(t, trogonerror.ErrUntranslated.Is(causes[2]))
+	assert.Equal(t, trogonerror.CodeUnknown, causes[2].Code())
+	assert.Equal(t, plain.Error(), causes[2].Message())
+	assert.ErrorIs(t, causes[2], plain)
+}
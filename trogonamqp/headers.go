@@ -0,0 +1,127 @@
+// Package trogonamqp writes TrogonErrors to AMQP message headers for RabbitMQ, mirroring the
+// header encoding trogonerror.EncodeHeaders provides for Kafka and NATS.
+//
+// Tables are plain maps so the package does not depend on an AMQP client; they convert
+// directly to and from amqp.Table:
+//
+//	table, err := trogonamqp.Encode(trogonErr)
+//	publishing.Headers = amqp.Table(table)
+//
+//	trogonErr, err := trogonamqp.Decode(delivery.Headers)
+package trogonamqp
+
+import (
+	"errors"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+// HeaderTruncated is set to true when the error was reduced to fit the size limit
+const HeaderTruncated = "trogon-error-truncated"
+
+// DefaultMaxSize keeps the headers well below RabbitMQ's default frame_max of 128 KiB,
+// which bounds the size of a message's header frame
+const DefaultMaxSize = 32 << 10
+
+// ErrHeadersTooLarge is returned when even the routing headers exceed the size limit
+var ErrHeadersTooLarge = errors.New("trogonamqp: error headers exceed the size limit")
+
+var defaultPolicy = trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic,
+	trogonerror.SerializationPolicyWithVisibilityFiltering())
+
+type encoder struct {
+	policy  *trogonerror.SerializationPolicy
+	maxSize int
+}
+
+// Option represents options for Encode
+type Option func(*encoder)
+
+// WithPolicy sets the serialization policy. By default errors are serialized for a public audience
+// with visibility filtering, so only public metadata reaches the broker.
+func WithPolicy(policy *trogonerror.SerializationPolicy) Option {
+	return func(e *encoder) {
+		e.policy = policy
+	}
+}
+
+// WithMaxSize bounds the total size of the header names and values, DefaultMaxSize by default
+func WithMaxSize(maxBytes int) Option {
+	return func(e *encoder) {
+		e.maxSize = maxBytes
+	}
+}
+
+// Encode writes the error to AMQP headers. When the full encoding exceeds the size limit,
+// debug info and metadata are dropped first, then the serialized error, keeping only the
+// routing headers; HeaderTruncated marks the reduced encodings. The reduced serialized error
+// is always encoded for a public audience.
+func Encode(err *trogonerror.TrogonError, options ...Option) (map[string]any, error) {
+	enc := &encoder{policy: defaultPolicy, maxSize: DefaultMaxSize}
+	for _, option := range options {
+		option(enc)
+	}
+
+	headers, encodeErr := trogonerror.EncodeHeaders(err, enc.policy)
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+	if size(headers) <= enc.maxSize {
+		return toTable(headers, false), nil
+	}
+
+	reduced := err.WithChanges(trogonerror.WithChangeMetadata(nil))
+	headers, encodeErr = trogonerror.EncodeHeaders(reduced, defaultPolicy)
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+	if size(headers) <= enc.maxSize {
+		return toTable(headers, true), nil
+	}
+
+	delete(headers, trogonerror.HeaderError)
+	if size(headers) <= enc.maxSize {
+		return toTable(headers, true), nil
+	}
+	return nil, ErrHeadersTooLarge
+}
+
+// Decode reconstructs an error from AMQP headers written by Encode,
+// returning trogonerror.ErrNoErrorHeaders when the headers carry no error
+func Decode(table map[string]any) (*trogonerror.TrogonError, error) {
+	headers := make(map[string]string)
+	for _, key := range []string{
+		trogonerror.HeaderError,
+		trogonerror.HeaderDomain,
+		trogonerror.HeaderReason,
+		trogonerror.HeaderCode,
+		trogonerror.HeaderID,
+	} {
+		switch value := table[key].(type) {
+		case string:
+			headers[key] = value
+		case []byte:
+			headers[key] = string(value)
+		}
+	}
+	return trogonerror.DecodeHeaders(headers)
+}
+
+func size(headers map[string]string) int {
+	total := 0
+	for key, value := range headers {
+		total += len(key) + len(value)
+	}
+	return total
+}
+
+func toTable(headers map[string]string, truncated bool) map[string]any {
+	table := make(map[string]any, len(headers)+1)
+	for key, value := range headers {
+		table[key] = value
+	}
+	if truncated {
+		table[HeaderTruncated] = true
+	}
+	return table
+}
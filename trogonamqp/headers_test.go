@@ -0,0 +1,96 @@
+package trogonamqp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogonamqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderError(options ...trogonerror.ErrorOption) *trogonerror.TrogonError {
+	return trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", append([]trogonerror.ErrorOption{
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithID("err_123"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "orders-7"),
+		trogonerror.WithDebugDetail("lookup failed"),
+	}, options...)...)
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("Round trip keeps only public metadata by default", func(t *testing.T) {
+		table, err := trogonamqp.Encode(newOrderError())
+		assert.NoError(t, err)
+
+		decoded, err := trogonamqp.Decode(table)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ORDER_NOT_FOUND", decoded.Reason())
+		assert.Equal(t, "err_123", decoded.ID())
+		assert.Equal(t, "gid://shopify/Order/1", decoded.Metadata()["orderId"].Value())
+		assert.NotContains(t, decoded.Metadata(), "shard")
+		assert.Nil(t, decoded.DebugInfo())
+		assert.NotContains(t, table, trogonamqp.HeaderTruncated)
+	})
+
+	t.Run("Custom policy", func(t *testing.T) {
+		table, _ := trogonamqp.Encode(newOrderError(),
+			trogonamqp.WithPolicy(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal)))
+
+		decoded, err := trogonamqp.Decode(table)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "orders-7", decoded.Metadata()["shard"].Value())
+		assert.Equal(t, "lookup failed", decoded.DebugInfo().Detail())
+	})
+
+	t.Run("Drops metadata over the size limit", func(t *testing.T) {
+		err := newOrderError(trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "note", strings.Repeat("x", 2000)))
+
+		table, encodeErr := trogonamqp.Encode(err, trogonamqp.WithMaxSize(1024))
+		decoded, decodeErr := trogonamqp.Decode(table)
+
+		assert.NoError(t, encodeErr)
+		assert.NoError(t, decodeErr)
+		assert.Equal(t, true, table[trogonamqp.HeaderTruncated])
+		assert.Equal(t, "ORDER_NOT_FOUND", decoded.Reason())
+		assert.Empty(t, decoded.Metadata())
+	})
+
+	t.Run("Keeps only routing headers when the error does not fit", func(t *testing.T) {
+		err := newOrderError(trogonerror.WithMessage(strings.Repeat("x", 2000)))
+
+		table, encodeErr := trogonamqp.Encode(err, trogonamqp.WithMaxSize(1024))
+		decoded, decodeErr := trogonamqp.Decode(table)
+
+		assert.NoError(t, encodeErr)
+		assert.NoError(t, decodeErr)
+		assert.NotContains(t, table, trogonerror.HeaderError)
+		assert.Equal(t, trogonerror.CodeNotFound, decoded.Code())
+		assert.Equal(t, "err_123", decoded.ID())
+	})
+
+	t.Run("Fails when even routing headers do not fit", func(t *testing.T) {
+		_, err := trogonamqp.Encode(newOrderError(), trogonamqp.WithMaxSize(16))
+		assert.ErrorIs(t, err, trogonamqp.ErrHeadersTooLarge)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("Byte slice values", func(t *testing.T) {
+		decoded, err := trogonamqp.Decode(map[string]any{
+			trogonerror.HeaderDomain: []byte("shopify.orders"),
+			trogonerror.HeaderReason: []byte("ORDER_NOT_FOUND"),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "shopify.orders", decoded.Domain())
+	})
+
+	t.Run("No error headers", func(t *testing.T) {
+		_, err := trogonamqp.Decode(map[string]any{"x-death": []any{}})
+		assert.ErrorIs(t, err, trogonerror.ErrNoErrorHeaders)
+	})
+}
@@ -0,0 +1,123 @@
+// Package trogonaws translates AWS SDK errors into TrogonErrors.
+//
+// Importing the package registers Translate with trogonerror.RegisterTranslator:
+//
+//	import _ "github.com/TrogonStack/trogonerror/trogonaws"
+//
+// Errors are recognized through the methods of smithy.APIError (ErrorCode and ErrorMessage)
+// and of the SDK response errors (ServiceRequestID), so the SDK is not imported.
+package trogonaws
+
+import (
+	"errors"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+const domain = "trogon.aws"
+
+var (
+	// ErrThrottled is the template for requests rejected by AWS rate limiting
+	ErrThrottled = trogonerror.NewErrorTemplate(domain, "THROTTLED",
+		trogonerror.TemplateWithCode(trogonerror.CodeResourceExhausted))
+
+	// ErrAccessDenied is the template for requests the caller is not authorized to make
+	ErrAccessDenied = trogonerror.NewErrorTemplate(domain, "ACCESS_DENIED",
+		trogonerror.TemplateWithCode(trogonerror.CodePermissionDenied))
+
+	// ErrInvalidCredentials is the template for requests with expired or invalid credentials
+	ErrInvalidCredentials = trogonerror.NewErrorTemplate(domain, "INVALID_CREDENTIALS",
+		trogonerror.TemplateWithCode(trogonerror.CodeUnauthenticated))
+
+	// ErrNotFound is the template for requests on resources that do not exist
+	ErrNotFound = trogonerror.NewErrorTemplate(domain, "NOT_FOUND",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+
+	// ErrConditionalCheckFailed is the template for conditional writes whose condition did not hold
+	ErrConditionalCheckFailed = trogonerror.NewErrorTemplate(domain, "CONDITIONAL_CHECK_FAILED",
+		trogonerror.TemplateWithCode(trogonerror.CodeAborted))
+
+	// ErrValidation is the template for requests AWS rejected as malformed
+	ErrValidation = trogonerror.NewErrorTemplate(domain, "VALIDATION_FAILED",
+		trogonerror.TemplateWithCode(trogonerror.CodeInvalidArgument))
+)
+
+// templates maps the AWS error codes shared across services to their template
+var templates = map[string]*trogonerror.ErrorTemplate{
+	"Throttling":                             ErrThrottled,
+	"ThrottlingException":                    ErrThrottled,
+	"ThrottledException":                     ErrThrottled,
+	"RequestThrottled":                       ErrThrottled,
+	"RequestThrottledException":              ErrThrottled,
+	"TooManyRequestsException":               ErrThrottled,
+	"ProvisionedThroughputExceededException": ErrThrottled,
+	"RequestLimitExceeded":                   ErrThrottled,
+	"SlowDown":                               ErrThrottled,
+
+	"AccessDenied":          ErrAccessDenied,
+	"AccessDeniedException": ErrAccessDenied,
+	"UnauthorizedOperation": ErrAccessDenied,
+
+	"ExpiredToken":                ErrInvalidCredentials,
+	"ExpiredTokenException":       ErrInvalidCredentials,
+	"InvalidClientTokenId":        ErrInvalidCredentials,
+	"UnrecognizedClientException": ErrInvalidCredentials,
+	"SignatureDoesNotMatch":       ErrInvalidCredentials,
+
+	"NotFound":                  ErrNotFound,
+	"NoSuchKey":                 ErrNotFound,
+	"NoSuchBucket":              ErrNotFound,
+	"NoSuchEntity":              ErrNotFound,
+	"ResourceNotFoundException": ErrNotFound,
+
+	"ConditionalCheckFailedException": ErrConditionalCheckFailed,
+	"PreconditionFailed":              ErrConditionalCheckFailed,
+
+	"ValidationException": ErrValidation,
+	"ValidationError":     ErrValidation,
+}
+
+// apiError is the subset of smithy.APIError used for translation
+type apiError interface {
+	error
+	ErrorCode() string
+	ErrorMessage() string
+}
+
+// requestIDError is implemented by the SDK's HTTP response errors
+type requestIDError interface {
+	ServiceRequestID() string
+}
+
+func init() {
+	trogonerror.RegisterTranslator("aws", Translate)
+}
+
+// Translate is a trogonerror.Translator for AWS SDK errors with a well-known error code.
+// The original error is wrapped, the AWS error code and request ID are recorded as private
+// metadata, or internal on an internal error, and the AWS error message as internal metadata.
+func Translate(err error) (*trogonerror.TrogonError, bool) {
+	var api apiError
+	if !errors.As(err, &api) {
+		return nil, false
+	}
+	template, ok := templates[api.ErrorCode()]
+	if !ok {
+		return nil, false
+	}
+
+	private := min(trogonerror.VisibilityPrivate, template.Visibility())
+	options := []trogonerror.ErrorOption{
+		trogonerror.WithWrap(err),
+		trogonerror.WithMetadataValue(private, "awsErrorCode", api.ErrorCode()),
+	}
+	if message := api.ErrorMessage(); message != "" {
+		options = append(options, trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "awsErrorMessage", message))
+	}
+	var withRequestID requestIDError
+	if errors.As(err, &withRequestID) && withRequestID.ServiceRequestID() != "" {
+		options = append(options, trogonerror.WithMetadataValue(private, "awsRequestId", withRequestID.ServiceRequestID()))
+	}
+
+	return template.NewError(options...), true
+}
@@ -0,0 +1,102 @@
+package trogonaws_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogonaws"
+	"github.com/stretchr/testify/assert"
+)
+
+// genericAPIError mirrors the shape of smithy.GenericAPIError
+type genericAPIError struct {
+	Code    string
+	Message string
+}
+
+func (e *genericAPIError) Error() string        { return fmt.Sprintf("api error %s: %s", e.Code, e.Message) }
+func (e *genericAPIError) ErrorCode() string    { return e.Code }
+func (e *genericAPIError) ErrorMessage() string { return e.Message }
+
+// responseError mirrors the shape of awshttp.ResponseError wrapping the API error
+type responseError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *responseError) Error() string {
+	return "https response error, RequestID: " + e.RequestID + ", " + e.Err.Error()
+}
+func (e *responseError) Unwrap() error            { return e.Err }
+func (e *responseError) ServiceRequestID() string { return e.RequestID }
+
+func newSDKError(code, message string) error {
+	return fmt.Errorf("operation error DynamoDB: PutItem, %w", &responseError{
+		RequestID: "7f9c2ba4-e0a3-4b2c-9a1f-3c6d8e5f1a2b",
+		Err:       &genericAPIError{Code: code, Message: message},
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		code     string
+		template *trogonerror.ErrorTemplate
+		want     trogonerror.Code
+	}{
+		{code: "ThrottlingException", template: trogonaws.ErrThrottled, want: trogonerror.CodeResourceExhausted},
+		{code: "SlowDown", template: trogonaws.ErrThrottled, want: trogonerror.CodeResourceExhausted},
+		{code: "AccessDeniedException", template: trogonaws.ErrAccessDenied, want: trogonerror.CodePermissionDenied},
+		{code: "ExpiredToken", template: trogonaws.ErrInvalidCredentials, want: trogonerror.CodeUnauthenticated},
+		{code: "NoSuchKey", template: trogonaws.ErrNotFound, want: trogonerror.CodeNotFound},
+		{code: "ResourceNotFoundException", template: trogonaws.ErrNotFound, want: trogonerror.CodeNotFound},
+		{code: "ConditionalCheckFailedException", template: trogonaws.ErrConditionalCheckFailed, want: trogonerror.CodeAborted},
+		{code: "ValidationException", template: trogonaws.ErrValidation, want: trogonerror.CodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			cause := newSDKError(tt.code, "The conditional request failed")
+
+			err, ok := trogonaws.Translate(cause)
+
+			assert.True(t, ok)
+			assert.True(t, tt.template.Is(err))
+			assert.Equal(t, tt.want, err.Code())
+			assert.ErrorIs(t, err, cause)
+		})
+	}
+
+	t.Run("Records the error code and request ID as metadata", func(t *testing.T) {
+		err, _ := trogonaws.Translate(newSDKError("ConditionalCheckFailedException", "The conditional request failed"))
+
+		assert.Equal(t, "ConditionalCheckFailedException", err.Metadata()["awsErrorCode"].Value())
+		assert.Equal(t, "7f9c2ba4-e0a3-4b2c-9a1f-3c6d8e5f1a2b", err.Metadata()["awsRequestId"].Value())
+		assert.Equal(t, "The conditional request failed", err.Metadata()["awsErrorMessage"].Value())
+		assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()["awsErrorMessage"].Visibility())
+	})
+
+	t.Run("Translated errors are valid", func(t *testing.T) {
+		for _, code := range []string{"ThrottlingException", "AccessDeniedException", "ConditionalCheckFailedException"} {
+			err, ok := trogonaws.Translate(newSDKError(code, "message"))
+			assert.True(t, ok, code)
+
+			assert.NoError(t, err.Validate(), code)
+			assert.NoError(t, err.ValidateStrict(), code)
+			assert.Equal(t, min(trogonerror.VisibilityPrivate, err.Visibility()), err.Metadata()["awsRequestId"].Visibility(), code)
+		}
+	})
+
+	t.Run("Unrecognized errors", func(t *testing.T) {
+		_, ok := trogonaws.Translate(newSDKError("InternalServerError", "oops"))
+		assert.False(t, ok)
+
+		_, ok = trogonaws.Translate(errors.New("connection reset"))
+		assert.False(t, ok)
+	})
+
+	t.Run("Registered with trogonerror.Translate", func(t *testing.T) {
+		assert.True(t, trogonaws.ErrThrottled.Is(trogonerror.Translate(newSDKError("Throttling", "Rate exceeded"))))
+	})
+}
@@ -0,0 +1,232 @@
+// Package trogoncatalog loads error definitions from declarative catalog files, so they can be
+// reviewed as data and shared with services written in other languages.
+//
+// A catalog is a YAML or JSON document listing error definitions:
+//
+//	errors:
+//	  - domain: shopify.orders
+//	    reason: ORDER_NOT_FOUND
+//	    code: NOT_FOUND
+//	    message: order not found
+//	    visibility: PUBLIC
+//	    help:
+//	      - description: Orders API
+//	        url: https://shopify.dev/docs/api/orders
+//	      - description: Orders runbook
+//	        url: https://runbooks.shopify.com/orders
+//	        kind: RUNBOOK
+//	    localizations:
+//	      fr-FR: commande introuvable
+//
+// Catalogs are typically embedded and registered at init:
+//
+//	//go:embed errors.yaml
+//	var catalogFS embed.FS
+//
+//	var catalog = trogoncatalog.MustRegister(catalogFS, "errors.yaml")
+package trogoncatalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/TrogonStack/trogonerror"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidCatalog reports a catalog that cannot be decoded or defines invalid errors
+var ErrInvalidCatalog = errors.New("trogoncatalog: invalid catalog")
+
+// Catalog is a set of error definitions
+type Catalog struct {
+	Errors []Entry `yaml:"errors" json:"errors"`
+
+	templates map[trogonerror.ErrorKey]*trogonerror.ErrorTemplate
+	entries   map[trogonerror.ErrorKey]*Entry
+}
+
+// Entry defines an error template. Code and visibility are spelled as in the JSON wire format,
+// such as NOT_FOUND and PUBLIC; they default to UNKNOWN and INTERNAL.
+type Entry struct {
+	Domain        string            `yaml:"domain" json:"domain"`
+	Reason        string            `yaml:"reason" json:"reason"`
+	Code          string            `yaml:"code,omitempty" json:"code,omitempty"`
+	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
+	PublicMessage string            `yaml:"publicMessage,omitempty" json:"publicMessage,omitempty"`
+	Visibility    string            `yaml:"visibility,omitempty" json:"visibility,omitempty"`
+	Owner         string            `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Tags          []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Help          []HelpLink        `yaml:"help,omitempty" json:"help,omitempty"`
+	Localizations map[string]string `yaml:"localizations,omitempty" json:"localizations,omitempty"`
+}
+
+// HelpLink defines a help link of an entry. Kind is one of DOCUMENTATION (the default), RUNBOOK,
+// DASHBOARD or SUPPORT, and the URL may contain {key} placeholders resolved from metadata.
+type HelpLink struct {
+	Description string `yaml:"description" json:"description"`
+	URL         string `yaml:"url" json:"url"`
+	Kind        string `yaml:"kind,omitempty" json:"kind,omitempty"`
+}
+
+var helpLinkKinds = map[string]trogonerror.HelpLinkKind{
+	"":              trogonerror.HelpLinkDocumentation,
+	"DOCUMENTATION": trogonerror.HelpLinkDocumentation,
+	"RUNBOOK":       trogonerror.HelpLinkRunbook,
+	"DASHBOARD":     trogonerror.HelpLinkDashboard,
+	"SUPPORT":       trogonerror.HelpLinkSupport,
+}
+
+// Parse decodes and validates a YAML or JSON catalog. Unknown fields are rejected,
+// and every invalid definition is reported together with errors.Join.
+func Parse(data []byte) (*Catalog, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var catalog Catalog
+	if err := decoder.Decode(&catalog); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+	}
+
+	catalog.templates = make(map[trogonerror.ErrorKey]*trogonerror.ErrorTemplate, len(catalog.Errors))
+	catalog.entries = make(map[trogonerror.ErrorKey]*Entry, len(catalog.Errors))
+
+	var errs []error
+	for i := range catalog.Errors {
+		entry := &catalog.Errors[i]
+		template, err := entry.template()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: errors[%d]: %w", ErrInvalidCatalog, i, err))
+			continue
+		}
+
+		k := trogonerror.ErrorKey{Domain: entry.Domain, Reason: entry.Reason}
+		if _, ok := catalog.entries[k]; ok {
+			errs = append(errs, fmt.Errorf("%w: errors[%d]: %s %s is defined more than once", ErrInvalidCatalog, i, entry.Domain, entry.Reason))
+			continue
+		}
+		catalog.entries[k] = entry
+		catalog.templates[k] = template
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &catalog, nil
+}
+
+// Load reads and parses the catalog file name from fsys, such as an embed.FS
+func Load(fsys fs.FS, name string) (*Catalog, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// MustRegister loads the catalog file name from fsys and registers its templates,
+// panicking on any error since catalogs are loaded at init
+func MustRegister(fsys fs.FS, name string) *Catalog {
+	catalog, err := Load(fsys, name)
+	if err != nil {
+		panic(err)
+	}
+	catalog.Register()
+	return catalog
+}
+
+func (e Entry) template() (*trogonerror.ErrorTemplate, error) {
+	var errs []error
+	errs = append(errs, trogonerror.ValidateDomain(e.Domain), trogonerror.ValidateReason(e.Reason))
+
+	options := []trogonerror.TemplateOption{
+		trogonerror.TemplateWithMessage(e.Message),
+		trogonerror.TemplateWithPublicMessage(e.PublicMessage),
+		trogonerror.TemplateWithOwner(e.Owner),
+		trogonerror.TemplateWithTags(e.Tags...),
+	}
+	if len(e.Localizations) > 0 {
+		options = append(options, trogonerror.TemplateWithLocalizedMessages(e.Localizations))
+	}
+	if e.Code != "" {
+		code, err := trogonerror.ParseCode(e.Code)
+		errs = append(errs, err)
+		options = append(options, trogonerror.TemplateWithCode(code))
+	}
+	if e.Visibility != "" {
+		visibility, err := trogonerror.ParseVisibility(e.Visibility)
+		errs = append(errs, err)
+		options = append(options, trogonerror.TemplateWithVisibility(visibility))
+	}
+	for _, link := range e.Help {
+		kind, ok := helpLinkKinds[strings.ToUpper(link.Kind)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown help link kind %q", link.Kind))
+		}
+		options = append(options, trogonerror.TemplateWithHelpLinkKind(kind, link.Description, link.URL))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return trogonerror.NewErrorTemplate(e.Domain, e.Reason, options...), nil
+}
+
+// Template returns the template defined for domain and reason
+func (c *Catalog) Template(domain, reason string) (*trogonerror.ErrorTemplate, bool) {
+	template, ok := c.templates[trogonerror.ErrorKey{Domain: domain, Reason: reason}]
+	return template, ok
+}
+
+// Templates returns the templates of the catalog, in definition order
+func (c *Catalog) Templates() []*trogonerror.ErrorTemplate {
+	templates := make([]*trogonerror.ErrorTemplate, 0, len(c.Errors))
+	for _, entry := range c.Errors {
+		templates = append(templates, c.templates[trogonerror.ErrorKey{Domain: entry.Domain, Reason: entry.Reason}])
+	}
+	return templates
+}
+
+// Register registers every template of the catalog with trogonerror.RegisterTemplate
+func (c *Catalog) Register() {
+	for _, template := range c.Templates() {
+		trogonerror.RegisterTemplate(template)
+	}
+}
+
+// Locales returns the locales the catalog has messages for, sorted
+func (c *Catalog) Locales() []string {
+	locales := make(map[string]struct{})
+	for _, entry := range c.Errors {
+		for locale := range entry.Localizations {
+			locales[locale] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(locales))
+}
+
+// Localize returns err with the catalog message best matching locale as its localized message,
+// walking the same fallback chain as TrogonError.MessageFor, see trogonerror.LocaleChain.
+// It returns err itself when the catalog has no such message for err's domain and reason.
+func (c *Catalog) Localize(err *trogonerror.TrogonError, locale string) *trogonerror.TrogonError {
+	if err == nil {
+		return nil
+	}
+	entry, ok := c.entries[err.Key()]
+	if !ok {
+		return err
+	}
+	for _, candidate := range trogonerror.LocaleChain(locale) {
+		for messageLocale, message := range entry.Localizations {
+			if strings.EqualFold(messageLocale, candidate) {
+				return err.WithChanges(trogonerror.WithChangeLocalizedMessage(messageLocale, message))
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package trogoncatalog_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogoncatalog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	catalog, err := trogoncatalog.Load(os.DirFS("testdata"), "errors.yaml")
+	assert.NoError(t, err)
+
+	template, ok := catalog.Template("shopify.orders", "ORDER_NOT_FOUND")
+	assert.True(t, ok)
+
+	orderErr := template.NewError(trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1234"))
+	assert.Equal(t, trogonerror.CodeNotFound, orderErr.Code())
+	assert.Equal(t, "order not found", orderErr.Message())
+	assert.Equal(t, trogonerror.VisibilityPublic, orderErr.Visibility())
+	assert.Equal(t, "team-orders", orderErr.Owner())
+	assert.Equal(t, []string{"orders"}, orderErr.Tags())
+	runbook, ok := orderErr.Help().Link(trogonerror.HelpLinkRunbook)
+	assert.True(t, ok)
+	assert.Equal(t, "https://runbooks.shopify.com/orders/1234", runbook.URL())
+
+	assert.Len(t, catalog.Templates(), 2)
+	assert.Equal(t, []string{"es-ES", "fr-FR"}, catalog.Locales())
+
+	t.Run("Localize", func(t *testing.T) {
+		localized := catalog.Localize(orderErr, "fr-FR")
+		assert.Equal(t, "commande introuvable", localized.LocalizedMessage().Message())
+
+		assert.Same(t, orderErr, catalog.Localize(orderErr, "de-DE"))
+	})
+
+	t.Run("Localize falls back to parent locales", func(t *testing.T) {
+		localized := catalog.Localize(orderErr, "fr-fr-x-private")
+		assert.Equal(t, "fr-FR", localized.LocalizedMessage().Locale())
+		assert.Equal(t, "commande introuvable", localized.LocalizedMessage().Message())
+
+		trogonerror.SetDefaultLocale("es-ES")
+		t.Cleanup(func() { trogonerror.SetDefaultLocale("") })
+		assert.Equal(t, "pedido no encontrado", catalog.Localize(orderErr, "de-DE").LocalizedMessage().Message())
+	})
+
+	t.Run("Templates carry the localizations", func(t *testing.T) {
+		assert.Len(t, template.LocalizedMessages(), 2)
+		assert.Equal(t, "pedido no encontrado", orderErr.MessageFor("es-ES"))
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		catalog.Register()
+
+		registered, ok := trogonerror.LookupTemplate("shopify.payments", "CARD_DECLINED")
+		assert.True(t, ok)
+		assert.Equal(t, "Your card was declined", registered.NewError().PublicMessage())
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		catalog, err := trogoncatalog.Parse([]byte(`{"errors": [{"domain": "shopify.orders", "reason": "ORDER_NOT_FOUND", "code": "NOT_FOUND"}]}`))
+
+		assert.NoError(t, err)
+		template, ok := catalog.Template("shopify.orders", "ORDER_NOT_FOUND")
+		assert.True(t, ok)
+		assert.Equal(t, trogonerror.CodeNotFound, template.Code())
+	})
+
+	t.Run("Invalid definitions", func(t *testing.T) {
+		_, err := trogoncatalog.Parse([]byte(`
+errors:
+  - domain: Shopify Orders
+    reason: ORDER_NOT_FOUND
+  - domain: shopify.orders
+    reason: ORDER_NOT_FOUND
+    code: MISSING
+    visibility: SECRET
+  - domain: shopify.orders
+    reason: ORDER_NOT_FOUND
+  - domain: shopify.orders
+    reason: ORDER_NOT_FOUND
+`))
+
+		assert.ErrorIs(t, err, trogoncatalog.ErrInvalidCatalog)
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidDomain)
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidCode)
+		assert.ErrorIs(t, err, trogonerror.ErrInvalidVisibility)
+		assert.ErrorContains(t, err, "defined more than once")
+	})
+
+	t.Run("Unknown fields", func(t *testing.T) {
+		_, err := trogoncatalog.Parse([]byte("errors:\n  - domain: shopify.orders\n    reason: ORDER_NOT_FOUND\n    severity: high\n"))
+
+		assert.ErrorIs(t, err, trogoncatalog.ErrInvalidCatalog)
+	})
+}
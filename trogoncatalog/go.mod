@@ -0,0 +1,15 @@
+module github.com/TrogonStack/trogonerror/trogoncatalog
+
+go 1.24.2
+
+require (
+	github.com/TrogonStack/trogonerror v0.4.0
+	github.com/stretchr/testify v1.11.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
+
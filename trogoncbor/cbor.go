@@ -0,0 +1,34 @@
+// Package trogoncbor encodes errors as CBOR (RFC 8949) for high-volume internal transports where JSON
+// is too large or slow. The layout mirrors the JSON encoding with field names replaced by integer keys.
+package trogoncbor
+
+import (
+	"github.com/TrogonStack/trogonerror"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// encMode encodes struct fields under small integer keys and times as RFC 3339 strings
+// with nanoseconds, keeping the same precision as the JSON encoding
+var encMode = func() cbor.EncMode {
+	mode, err := cbor.EncOptions{Sort: cbor.SortCoreDeterministic, Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// Marshal encodes the error as CBOR, subject to trogonerror.DefaultSerializationPolicy
+func Marshal(err *trogonerror.TrogonError) ([]byte, error) {
+	return MarshalFor(err, trogonerror.DefaultSerializationPolicy())
+}
+
+// MarshalFor encodes the error like Marshal, applying policy instead of the default one
+func MarshalFor(err *trogonerror.TrogonError, policy *trogonerror.SerializationPolicy) ([]byte, error) {
+	return err.MarshalWith(encMode.Marshal, policy)
+}
+
+// Unmarshal decodes an error encoded by Marshal into err, following the compatibility
+// policy of TrogonError.UnmarshalJSON for unknown codes, visibilities and fields
+func Unmarshal(data []byte, err *trogonerror.TrogonError) error {
+	return err.UnmarshalWith(func(v any) error { return cbor.Unmarshal(data, v) })
+}
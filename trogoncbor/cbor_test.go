@@ -0,0 +1,91 @@
+package trogoncbor_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogoncbor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCodecError() *trogonerror.TrogonError {
+	return trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.WithID("err_123"),
+		trogonerror.WithTime(time.Date(2024, 1, 15, 10, 30, 0, 123456789, time.UTC)),
+		trogonerror.WithSubject("/orderId"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+		trogonerror.WithHelpLink("Order docs", "https://shopify.dev/docs/orders"),
+		trogonerror.WithRetryInfoDuration(1500*time.Millisecond),
+		trogonerror.WithLocalizedMessage("es-ES", "Pedido no encontrado"),
+		trogonerror.WithCause(trogonerror.NewError("shopify.database", "ROW_MISSING")))
+}
+
+func TestCBOR(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		original := newCodecError()
+
+		data, err := trogoncbor.Marshal(original)
+		assert.NoError(t, err)
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, trogoncbor.Unmarshal(data, &decoded))
+		assert.Empty(t, trogonerror.Diff(original, &decoded))
+	})
+
+	t.Run("Smaller than JSON", func(t *testing.T) {
+		cborData, _ := trogoncbor.Marshal(newCodecError())
+		jsonData, _ := json.Marshal(newCodecError())
+
+		assert.Less(t, len(cborData), len(jsonData))
+	})
+
+	t.Run("Policy strips debug info", func(t *testing.T) {
+		debuggable := trogonerror.NewError("shopify.payments", "PAYMENT_DECLINED",
+			trogonerror.WithCode(trogonerror.CodeInternal),
+			trogonerror.WithStackTrace(),
+			trogonerror.WithDebugDetail("gateway returned 502"))
+
+		data, err := trogoncbor.Marshal(debuggable)
+		assert.NoError(t, err)
+
+		var decoded trogonerror.TrogonError
+		assert.NoError(t, trogoncbor.Unmarshal(data, &decoded))
+		assert.Nil(t, decoded.DebugInfo())
+
+		data, err = trogoncbor.MarshalFor(debuggable, trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal))
+		assert.NoError(t, err)
+		assert.NoError(t, trogoncbor.Unmarshal(data, &decoded))
+		assert.Equal(t, "gateway returned 502", decoded.DebugInfo().Detail())
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		var decoded trogonerror.TrogonError
+		assert.Error(t, trogoncbor.Unmarshal([]byte{0xff}, &decoded))
+	})
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	err := newCodecError()
+
+	b.Run("JSON", func(b *testing.B) {
+		b.ReportAllocs()
+		var data []byte
+		for b.Loop() {
+			data, _ = json.Marshal(err)
+		}
+		b.ReportMetric(float64(len(data)), "payload-bytes")
+	})
+
+	b.Run("CBOR", func(b *testing.B) {
+		b.ReportAllocs()
+		var data []byte
+		for b.Loop() {
+			data, _ = trogoncbor.Marshal(err)
+		}
+		b.ReportMetric(float64(len(data)), "payload-bytes")
+	})
+}
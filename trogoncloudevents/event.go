@@ -0,0 +1,112 @@
+// Package trogoncloudevents attaches TrogonErrors to CloudEvents, so event-driven services
+// publish failure events with the same error model they use synchronously.
+//
+// The helpers accept any event with the methods of the CloudEvents SDK's event.Event,
+// so the SDK is not imported:
+//
+//	event := cloudevents.NewEvent()
+//	event.SetType("com.shopify.orders.failed")
+//	err := trogoncloudevents.SetExtensions(&event, trogonErr, nil)
+package trogoncloudevents
+
+import (
+	"errors"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+// Extension attribute names. CloudEvents restricts attribute names to lowercase letters and digits.
+// ExtensionError carries the serialized error; the others carry identifying fields so brokers and
+// subscriptions can filter on them.
+const (
+	ExtensionError  = "trogonerror"
+	ExtensionDomain = "trogonerrordomain"
+	ExtensionReason = "trogonerrorreason"
+	ExtensionCode   = "trogonerrorcode"
+)
+
+// ContentType identifies event data holding a serialized error
+const ContentType = "application/vnd.trogon.error+json"
+
+// ErrNoError is returned when the event carries no error
+var ErrNoError = errors.New("trogoncloudevents: event carries no error")
+
+// ExtensionWriter is implemented by *event.Event
+type ExtensionWriter interface {
+	SetExtension(name string, value any)
+}
+
+// ExtensionReader is implemented by event.Event
+type ExtensionReader interface {
+	Extensions() map[string]any
+}
+
+// DataWriter is implemented by *event.Event
+type DataWriter interface {
+	SetData(contentType string, obj any) error
+}
+
+// DataReader is implemented by event.Event
+type DataReader interface {
+	DataContentType() string
+	Data() []byte
+}
+
+// SetExtensions attaches the error as extension attributes, applying policy
+// (trogonerror.DefaultSerializationPolicy when nil). Use it when the event data is the
+// original payload, e.g. for a failed command republished with its error.
+func SetExtensions(event ExtensionWriter, err *trogonerror.TrogonError, policy *trogonerror.SerializationPolicy) error {
+	headers, encodeErr := trogonerror.EncodeHeaders(err, policy)
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	event.SetExtension(ExtensionError, headers[trogonerror.HeaderError])
+	event.SetExtension(ExtensionDomain, headers[trogonerror.HeaderDomain])
+	event.SetExtension(ExtensionReason, headers[trogonerror.HeaderReason])
+	event.SetExtension(ExtensionCode, headers[trogonerror.HeaderCode])
+	return nil
+}
+
+// FromExtensions reconstructs an error attached with SetExtensions,
+// returning ErrNoError when the event has no error extensions
+func FromExtensions(event ExtensionReader) (*trogonerror.TrogonError, error) {
+	extensions := event.Extensions()
+	headers := make(map[string]string)
+	for header, extension := range map[string]string{
+		trogonerror.HeaderError:  ExtensionError,
+		trogonerror.HeaderDomain: ExtensionDomain,
+		trogonerror.HeaderReason: ExtensionReason,
+		trogonerror.HeaderCode:   ExtensionCode,
+	} {
+		if value, ok := extensions[extension].(string); ok {
+			headers[header] = value
+		}
+	}
+
+	err, decodeErr := trogonerror.DecodeHeaders(headers)
+	if errors.Is(decodeErr, trogonerror.ErrNoErrorHeaders) {
+		return nil, ErrNoError
+	}
+	return err, decodeErr
+}
+
+// SetData makes the error the event data with ContentType, applying policy
+// (trogonerror.DefaultSerializationPolicy when nil). Use it for failure events whose
+// payload is the error itself.
+func SetData(event DataWriter, err *trogonerror.TrogonError, policy *trogonerror.SerializationPolicy) error {
+	payload, encodeErr := trogonerror.EncodePayload(err, policy)
+	if encodeErr != nil {
+		return encodeErr
+	}
+	return event.SetData(ContentType, payload)
+}
+
+// FromData reconstructs an error set with SetData,
+// returning ErrNoError when the event data is not a serialized error
+func FromData(event DataReader) (*trogonerror.TrogonError, error) {
+	if event.DataContentType() != ContentType {
+		return nil, ErrNoError
+	}
+	return trogonerror.DecodePayload(event.Data())
+}
@@ -0,0 +1,82 @@
+package trogoncloudevents_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogoncloudevents"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeEvent mirrors the accessors of the CloudEvents SDK's event.Event
+type fakeEvent struct {
+	extensions  map[string]any
+	contentType string
+	data        []byte
+}
+
+func (e *fakeEvent) SetExtension(name string, value any) {
+	if e.extensions == nil {
+		e.extensions = make(map[string]any)
+	}
+	e.extensions[name] = value
+}
+
+func (e *fakeEvent) Extensions() map[string]any { return e.extensions }
+
+func (e *fakeEvent) SetData(contentType string, obj any) error {
+	e.contentType = contentType
+	e.data = obj.([]byte)
+	return nil
+}
+
+func (e *fakeEvent) DataContentType() string { return e.contentType }
+func (e *fakeEvent) Data() []byte            { return e.data }
+
+func newOrderError() *trogonerror.TrogonError {
+	return trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"))
+}
+
+func TestExtensions(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		original := newOrderError()
+		event := &fakeEvent{}
+
+		err := trogoncloudevents.SetExtensions(event, original, nil)
+		decoded, decodeErr := trogoncloudevents.FromExtensions(event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, decodeErr)
+		assert.Equal(t, "shopify.orders", event.extensions[trogoncloudevents.ExtensionDomain])
+		assert.Equal(t, "ORDER_NOT_FOUND", event.extensions[trogoncloudevents.ExtensionReason])
+		assert.Equal(t, "NOT_FOUND", event.extensions[trogoncloudevents.ExtensionCode])
+		assert.True(t, trogonerror.Equal(original, decoded))
+	})
+
+	t.Run("No error", func(t *testing.T) {
+		_, err := trogoncloudevents.FromExtensions(&fakeEvent{extensions: map[string]any{"traceparent": "00-abc"}})
+		assert.ErrorIs(t, err, trogoncloudevents.ErrNoError)
+	})
+}
+
+func TestData(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		original := newOrderError()
+		event := &fakeEvent{}
+
+		err := trogoncloudevents.SetData(event, original, nil)
+		decoded, decodeErr := trogoncloudevents.FromData(event)
+
+		assert.NoError(t, err)
+		assert.NoError(t, decodeErr)
+		assert.Equal(t, trogoncloudevents.ContentType, event.contentType)
+		assert.True(t, trogonerror.Equal(original, decoded))
+	})
+
+	t.Run("Other content types", func(t *testing.T) {
+		_, err := trogoncloudevents.FromData(&fakeEvent{contentType: "application/json", data: []byte(`{}`)})
+		assert.ErrorIs(t, err, trogoncloudevents.ErrNoError)
+	})
+}
@@ -0,0 +1,51 @@
+// Command trogoncodegen generates error templates from a catalog file, for use with go:generate:
+//
+//	//go:generate go run github.com/TrogonStack/trogonerror/trogoncodegen/cmd/trogoncodegen -catalog errors.yaml -o errors_gen.go
+//
+// The package name defaults to $GOPACKAGE, set by go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TrogonStack/trogonerror/trogoncatalog"
+	"github.com/TrogonStack/trogonerror/trogoncodegen"
+)
+
+func main() {
+	catalogPath := flag.String("catalog", "", "catalog file to generate templates from")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	output := flag.String("o", "", "output file; standard output when empty")
+	flag.Parse()
+
+	if err := run(*catalogPath, *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "trogoncodegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(catalogPath, pkg, output string) error {
+	if catalogPath == "" || pkg == "" {
+		return fmt.Errorf("-catalog and -package are required")
+	}
+
+	catalog, err := trogoncatalog.Load(os.DirFS(filepath.Dir(catalogPath)), filepath.Base(catalogPath))
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := trogoncodegen.GenerateFromCatalog(&buf, pkg, catalog, trogoncodegen.WithSource(filepath.Base(catalogPath))); err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(output, buf.Bytes(), 0o644)
+}
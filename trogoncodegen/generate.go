@@ -0,0 +1,206 @@
+// Package trogoncodegen generates Go source declaring error templates, with constants for their
+// domains and reasons, from a catalog file or from the templates registered with trogonerror.RegisterTemplate.
+// Generated definitions stay in sync with the catalog shared with other services.
+//
+// It is typically run through go:generate with the trogoncodegen command:
+//
+//	//go:generate go run github.com/TrogonStack/trogonerror/trogoncodegen/cmd/trogoncodegen -catalog errors.yaml -package orders -o errors_gen.go
+package trogoncodegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogoncatalog"
+)
+
+// ErrDuplicateIdentifier is returned when two domains, reasons or templates map to the same Go identifier
+var ErrDuplicateIdentifier = errors.New("trogoncodegen: duplicate identifier")
+
+type config struct {
+	source string
+}
+
+// Option represents options for code generation
+type Option func(*config)
+
+// WithSource names the file the code is generated from in the generated header
+func WithSource(source string) Option {
+	return func(c *config) {
+		c.source = source
+	}
+}
+
+// GenerateFromCatalog writes the source declaring the templates of catalog in package pkg
+func GenerateFromCatalog(w io.Writer, pkg string, catalog *trogoncatalog.Catalog, options ...Option) error {
+	return Generate(w, pkg, catalog.Templates(), options...)
+}
+
+// GenerateFromRegistry writes the source declaring the templates registered with trogonerror.RegisterTemplate
+func GenerateFromRegistry(w io.Writer, pkg string, options ...Option) error {
+	return Generate(w, pkg, trogonerror.Templates(), options...)
+}
+
+// Generate writes gofmt-formatted source declaring, in package pkg, a Domain constant for every domain,
+// a Reason constant for every reason and an Err variable for every template. Variables are named
+// after the reason, prefixed with the domain when several domains share the reason.
+// It returns ErrDuplicateIdentifier when two of them would be declared under the same name.
+func Generate(w io.Writer, pkg string, templates []*trogonerror.ErrorTemplate, options ...Option) error {
+	var cfg config
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	var domains, reasons []string
+	reasonDomains := make(map[string]int)
+	for _, template := range templates {
+		if !slices.Contains(domains, template.Domain()) {
+			domains = append(domains, template.Domain())
+		}
+		if !slices.Contains(reasons, template.Reason()) {
+			reasons = append(reasons, template.Reason())
+		}
+		reasonDomains[template.Reason()]++
+	}
+
+	declared := make(declarations)
+	for _, domain := range domains {
+		if err := declared.add("Domain"+identifier(domain), "domain "+domain); err != nil {
+			return err
+		}
+	}
+	for _, reason := range reasons {
+		if err := declared.add("Reason"+identifier(reason), "reason "+reason); err != nil {
+			return err
+		}
+	}
+	names := make([]string, len(templates))
+	for i, template := range templates {
+		names[i] = "Err" + identifier(template.Reason())
+		if reasonDomains[template.Reason()] > 1 {
+			names[i] = "Err" + identifier(template.Domain()) + identifier(template.Reason())
+		}
+		if err := declared.add(names[i], "template "+template.Domain()+" "+template.Reason()); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if cfg.source != "" {
+		fmt.Fprintf(&buf, "// Code generated by trogoncodegen from %s. DO NOT EDIT.\n\n", cfg.source)
+	} else {
+		buf.WriteString("// Code generated by trogoncodegen. DO NOT EDIT.\n\n")
+	}
+	fmt.Fprintf(&buf, "package %s\n\nimport \"github.com/TrogonStack/trogonerror\"\n\n", pkg)
+
+	buf.WriteString("const (\n")
+	for _, domain := range domains {
+		fmt.Fprintf(&buf, "Domain%s = %q\n", identifier(domain), domain)
+	}
+	buf.WriteString(")\n\nconst (\n")
+	for _, reason := range reasons {
+		fmt.Fprintf(&buf, "Reason%s = %q\n", identifier(reason), reason)
+	}
+	buf.WriteString(")\n\nvar (\n")
+	for i, template := range templates {
+		name := names[i]
+		fmt.Fprintf(&buf, "// %s is the template for %s %s errors: %s\n", name, template.Domain(), template.Reason(), commentText(template.Message()))
+		fmt.Fprintf(&buf, "%s = trogonerror.NewErrorTemplate(Domain%s, Reason%s,\n", name, identifier(template.Domain()), identifier(template.Reason()))
+		for _, option := range templateOptions(template) {
+			fmt.Fprintf(&buf, "%s,\n", option)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.WriteString(")\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("trogoncodegen: formatting generated source: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// declarations maps every declared Go identifier to what it was generated from
+type declarations map[string]string
+
+func (d declarations) add(name, source string) error {
+	if previous, ok := d[name]; ok {
+		return fmt.Errorf("%w: %s and %s both map to %s", ErrDuplicateIdentifier, previous, source, name)
+	}
+	d[name] = source
+	return nil
+}
+
+// commentText joins the lines of a message so it fits in a single line comment
+func commentText(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
+
+var idempotencies = map[trogonerror.Idempotency]string{
+	trogonerror.IdempotencySafe:        "trogonerror.IdempotencySafe",
+	trogonerror.IdempotencyRequiresKey: "trogonerror.IdempotencyRequiresKey",
+	trogonerror.IdempotencyUnsafe:      "trogonerror.IdempotencyUnsafe",
+}
+
+func templateOptions(template *trogonerror.ErrorTemplate) []string {
+	options := []string{"trogonerror.TemplateWithCode(trogonerror.Code" + identifier(template.Code().String()) + ")"}
+	if template.Message() != template.Code().Message() {
+		options = append(options, "trogonerror.TemplateWithMessage("+strconv.Quote(template.Message())+")")
+	}
+	if template.Visibility() != trogonerror.VisibilityInternal {
+		options = append(options, "trogonerror.TemplateWithVisibility(trogonerror.Visibility"+identifier(template.Visibility().String())+")")
+	}
+	if template.PublicMessage() != "" {
+		options = append(options, "trogonerror.TemplateWithPublicMessage("+strconv.Quote(template.PublicMessage())+")")
+	}
+	if template.Owner() != "" {
+		options = append(options, "trogonerror.TemplateWithOwner("+strconv.Quote(template.Owner())+")")
+	}
+	if tags := template.Tags(); len(tags) > 0 {
+		quoted := make([]string, len(tags))
+		for i, tag := range tags {
+			quoted[i] = strconv.Quote(tag)
+		}
+		options = append(options, "trogonerror.TemplateWithTags("+strings.Join(quoted, ", ")+")")
+	}
+	if translations := template.LocalizedMessages(); len(translations) > 0 {
+		quoted := make([]string, len(translations))
+		for i, translation := range translations {
+			quoted[i] = strconv.Quote(translation.Locale()) + ": " + strconv.Quote(translation.Message())
+		}
+		options = append(options, "trogonerror.TemplateWithLocalizedMessages(map[string]string{"+strings.Join(quoted, ", ")+"})")
+	}
+	if idempotency, ok := idempotencies[template.Idempotency()]; ok {
+		options = append(options, "trogonerror.TemplateWithIdempotency("+idempotency+")")
+	}
+	if help := template.Help(); help != nil {
+		for _, link := range help.Links() {
+			if link.Kind() == trogonerror.HelpLinkDocumentation {
+				options = append(options, fmt.Sprintf("trogonerror.TemplateWithHelpLink(%q, %q)", link.Description(), link.URL()))
+				continue
+			}
+			options = append(options, fmt.Sprintf("trogonerror.TemplateWithHelpLinkKind(trogonerror.HelpLink%s, %q, %q)",
+				identifier(link.Kind().String()), link.Description(), link.URL()))
+		}
+	}
+	return options
+}
+
+// identifier converts a domain or an UPPER_SNAKE_CASE name to an exported Go identifier,
+// such as "shopify.orders" to "ShopifyOrders" and "NOT_FOUND" to "NotFound"
+func identifier(name string) string {
+	var b strings.Builder
+	for word := range strings.FieldsFuncSeq(name, func(r rune) bool { return r == '.' || r == '_' || r == '-' }) {
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package trogoncodegen_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogoncatalog"
+	"github.com/TrogonStack/trogonerror/trogoncodegen"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerateFromCatalog(t *testing.T) {
+	catalog, err := trogoncatalog.Load(os.DirFS("testdata"), "errors.yaml")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, trogoncodegen.GenerateFromCatalog(&buf, "orders", catalog, trogoncodegen.WithSource("errors.yaml")))
+
+	if *update {
+		assert.NoError(t, os.WriteFile("testdata/errors_gen.golden", buf.Bytes(), 0o644))
+	}
+	golden, err := os.ReadFile("testdata/errors_gen.golden")
+	assert.NoError(t, err)
+	assert.Equal(t, string(golden), buf.String(), "run go test -run TestGenerateFromCatalog -update")
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("Reasons shared by several domains", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := trogoncodegen.Generate(&buf, "shopify", []*trogonerror.ErrorTemplate{
+			trogonerror.NewErrorTemplate("shopify.orders", "NOT_FOUND", trogonerror.TemplateWithCode(trogonerror.CodeNotFound)),
+			trogonerror.NewErrorTemplate("shopify.products", "NOT_FOUND", trogonerror.TemplateWithCode(trogonerror.CodeNotFound)),
+			trogonerror.NewErrorTemplate("shopify.products", "OUT_OF_STOCK", trogonerror.TemplateWithIdempotency(trogonerror.IdempotencySafe)),
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "ErrShopifyOrdersNotFound = trogonerror.NewErrorTemplate(DomainShopifyOrders, ReasonNotFound,")
+		assert.Contains(t, buf.String(), "ErrShopifyProductsNotFound = trogonerror.NewErrorTemplate(DomainShopifyProducts, ReasonNotFound,")
+		assert.Contains(t, buf.String(), "ErrOutOfStock = trogonerror.NewErrorTemplate(DomainShopifyProducts, ReasonOutOfStock,")
+		assert.Contains(t, buf.String(), "trogonerror.TemplateWithIdempotency(trogonerror.IdempotencySafe)")
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"NOT_FOUND"`)))
+	})
+
+	t.Run("Multi-line messages stay in the comment", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := trogoncodegen.Generate(&buf, "shopify", []*trogonerror.ErrorTemplate{
+			trogonerror.NewErrorTemplate("shopify.orders", "INVALID_ORDER",
+				trogonerror.TemplateWithMessage("The order is invalid.\nfunc broken() {\r\n\tCheck the line items.")),
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "// ErrInvalidOrder is the template for shopify.orders INVALID_ORDER errors: The order is invalid. func broken() { Check the line items.\n")
+		assert.Contains(t, buf.String(), `trogonerror.TemplateWithMessage("The order is invalid.\nfunc broken() {\r\n\tCheck the line items.")`)
+	})
+
+	t.Run("Identifiers shared by several names", func(t *testing.T) {
+		for name, templates := range map[string][]*trogonerror.ErrorTemplate{
+			"domains": {
+				trogonerror.NewErrorTemplate("shopify.orders", "NOT_FOUND"),
+				trogonerror.NewErrorTemplate("shopify-orders", "OUT_OF_STOCK"),
+			},
+			"reasons": {
+				trogonerror.NewErrorTemplate("shopify.orders", "NOT_FOUND"),
+				trogonerror.NewErrorTemplate("shopify.orders", "NOT-FOUND"),
+			},
+			"templates": {
+				trogonerror.NewErrorTemplate("shopify.orders", "NOT_FOUND"),
+				trogonerror.NewErrorTemplate("shopify.orders", "NOT_FOUND"),
+			},
+		} {
+			var buf bytes.Buffer
+			err := trogoncodegen.Generate(&buf, "shopify", templates)
+
+			assert.ErrorIs(t, err, trogoncodegen.ErrDuplicateIdentifier, name)
+			assert.Empty(t, buf.String(), name)
+		}
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		trogonerror.RegisterTemplate(trogonerror.NewErrorTemplate("shopify.codegen", "REGISTERED"))
+
+		var buf bytes.Buffer
+		assert.NoError(t, trogoncodegen.GenerateFromRegistry(&buf, "shopify"))
+		assert.Contains(t, buf.String(), "ErrRegistered = trogonerror.NewErrorTemplate(DomainShopifyCodegen, ReasonRegistered,")
+	})
+}
@@ -0,0 +1,120 @@
+// Package trogonerrortest provides helpers for snapshot testing the errors a package produces.
+//
+// Errors are rendered into a canonical form and compared against golden files under testdata.
+// Run the tests with -update to rewrite the golden files from the current output:
+//
+//	func TestOrderNotFound(t *testing.T) {
+//		trogonerrortest.FreezeTime(t, time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC))
+//		trogonerrortest.AssertGolden(t, "order_not_found", orders.Lookup(ctx, "gid://shopify/Order/1"))
+//	}
+package trogonerrortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+var update = flag.Bool("update", false, "rewrite trogonerrortest golden files")
+
+var internal = trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal)
+
+// Canonical renders err as indented JSON with sorted keys, including debug info. Stack entries
+// keep only the base name of their file, and environment dependent debug info (goroutine dumps
+// and runtime snapshots) is dropped, so the output is stable across machines.
+func Canonical(err *trogonerror.TrogonError) (string, error) {
+	data, marshalErr := err.MarshalJSONFor(internal)
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+
+	var raw any
+	if unmarshalErr := json.Unmarshal(data, &raw); unmarshalErr != nil {
+		return "", unmarshalErr
+	}
+
+	canonical, marshalErr := json.MarshalIndent(normalize(raw), "", "  ")
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	return string(canonical) + "\n", nil
+}
+
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if debugInfo, ok := v["debugInfo"].(map[string]any); ok {
+			delete(debugInfo, "goroutines")
+			delete(debugInfo, "runtime")
+			if entries, ok := debugInfo["stackEntries"].([]any); ok {
+				for i, entry := range entries {
+					if s, ok := entry.(string); ok {
+						entries[i] = normalizeStackEntry(s)
+					}
+				}
+			}
+		}
+		for key, child := range v {
+			v[key] = normalize(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = normalize(child)
+		}
+	}
+	return value
+}
+
+// normalizeStackEntry reduces "/abs/path/file.go:42 pkg.Func" to "file.go:42 pkg.Func"
+func normalizeStackEntry(entry string) string {
+	location, function, found := strings.Cut(entry, " ")
+	if !found {
+		return filepath.Base(entry)
+	}
+	return filepath.Base(location) + " " + function
+}
+
+// AssertGolden compares the canonical form of err with testdata/<name>.golden,
+// rewriting the file instead when the tests run with -update
+func AssertGolden(t testing.TB, name string, err *trogonerror.TrogonError) {
+	t.Helper()
+
+	got, canonicalErr := Canonical(err)
+	if canonicalErr != nil {
+		t.Fatalf("trogonerrortest: rendering %s: %v", name, canonicalErr)
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o755); mkdirErr != nil {
+			t.Fatalf("trogonerrortest: %v", mkdirErr)
+		}
+		if writeErr := os.WriteFile(path, []byte(got), 0o644); writeErr != nil {
+			t.Fatalf("trogonerrortest: %v", writeErr)
+		}
+		return
+	}
+
+	want, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("trogonerrortest: %v (run with -update to create it)", readErr)
+	}
+	if !bytes.Equal(want, []byte(got)) {
+		t.Errorf("trogonerrortest: %s does not match the golden file (run with -update to accept)\n--- want\n%s\n+++ got\n%s", path, want, got)
+	}
+}
+
+// FreezeTime makes the error clock return timestamp until the test ends
+func FreezeTime(t testing.TB, timestamp time.Time) {
+	t.Helper()
+
+	trogonerror.SetClock(func() time.Time { return timestamp })
+	t.Cleanup(func() { trogonerror.SetClock(nil) })
+}
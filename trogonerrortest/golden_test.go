@@ -0,0 +1,60 @@
+package trogonerrortest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogonerrortest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderError() *trogonerror.TrogonError {
+	return trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithTimeNow(),
+		trogonerror.WithID("err_123"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shopId", "42"),
+		trogonerror.WithCallerInfo(),
+		trogonerror.WithRuntimeInfo(),
+		trogonerror.WithWrap(errors.New("sql: no rows in result set")))
+}
+
+func TestAssertGolden(t *testing.T) {
+	trogonerrortest.FreezeTime(t, time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC))
+
+	trogonerrortest.AssertGolden(t, "order_not_found", newOrderError())
+}
+
+func TestCanonical(t *testing.T) {
+	trogonerrortest.FreezeTime(t, time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC))
+
+	first, err := trogonerrortest.Canonical(newOrderError())
+	assert.NoError(t, err)
+	second, err := trogonerrortest.Canonical(newOrderError())
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Contains(t, first, `"time": "2024-01-15T14:30:45Z"`)
+	assert.Contains(t, first, `"golden_test.go:`)
+	assert.NotContains(t, first, "/golden_test.go")
+	assert.NotContains(t, first, `"runtime"`)
+	assert.True(t, strings.Index(first, `"orderId"`) < strings.Index(first, `"shopId"`))
+}
+
+func TestFreezeTime(t *testing.T) {
+	timestamp := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+
+	t.Run("Frozen", func(t *testing.T) {
+		trogonerrortest.FreezeTime(t, timestamp)
+
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithTimeNow())
+		assert.Equal(t, timestamp, *err.Time())
+	})
+
+	err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithTimeNow())
+	assert.NotEqual(t, timestamp, *err.Time())
+}
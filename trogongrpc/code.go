@@ -0,0 +1,17 @@
+package trogongrpc
+
+import "github.com/TrogonStack/trogonerror"
+
+// Code returns the numeric gRPC status code for err, honoring WithGRPCCode overrides, for interceptors
+// building statuses without this package depending on gRPC:
+//
+//	return nil, status.Error(codes.Code(trogongrpc.Code(err)), err.Error())
+//
+// Errors that are not TrogonErrors are converted with Translate first. A nil err yields 0 (OK).
+func Code(err error) uint32 {
+	trogonErr := trogonerror.Translate(err)
+	if trogonErr == nil {
+		return 0
+	}
+	return uint32(trogonErr.GRPCCode())
+}
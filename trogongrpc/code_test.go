@@ -0,0 +1,21 @@
+package trogongrpc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogongrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCode(t *testing.T) {
+	notFound := trogonerror.NewError("shopify.users", "NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound))
+
+	assert.Equal(t, uint32(5), trogongrpc.Code(notFound))
+	assert.Equal(t, uint32(9), trogongrpc.Code(notFound.WithChanges(
+		trogonerror.WithChangeGRPCCode(trogonerror.CodeFailedPrecondition))))
+	assert.Equal(t, uint32(2), trogongrpc.Code(errors.New("boom")))
+	assert.Equal(t, uint32(0), trogongrpc.Code(nil))
+}
@@ -0,0 +1,17 @@
+module github.com/TrogonStack/trogonerror/trogongrpc
+
+go 1.24.2
+
+require (
+	github.com/TrogonStack/trogonerror v0.4.0
+	github.com/TrogonStack/trogonerror/trogoncbor v0.1.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fxamacker/cbor/v2 v2.9.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
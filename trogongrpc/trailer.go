@@ -0,0 +1,118 @@
+// Package trogongrpc carries complete TrogonErrors in gRPC trailer metadata, for streaming RPCs
+// where the serialized error does not fit the status message.
+//
+// Trailers are plain maps so the package does not depend on gRPC; they convert directly to and
+// from metadata.MD:
+//
+//	trailer, err := trogongrpc.EncodeTrailer(trogonErr)
+//	grpc.SetTrailer(ctx, metadata.MD(trailer))
+//
+//	var trailer metadata.MD
+//	_, err := client.Checkout(ctx, req, grpc.Trailer(&trailer))
+//	trogonErr, decodeErr := trogongrpc.DecodeTrailer(trailer)
+package trogongrpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogoncbor"
+)
+
+// Trailer keys written by EncodeTrailer. The error is CBOR-encoded in one or more values of
+// TrailerError; the "-bin" suffix makes gRPC transmit them base64-encoded.
+const (
+	TrailerError  = "trogon-error-bin"
+	TrailerChunks = "trogon-error-chunks"
+)
+
+// DefaultChunkSize keeps every value well below the 8 KiB header size limits common in proxies
+const DefaultChunkSize = 4 << 10
+
+var (
+	// ErrNoError is returned by DecodeTrailer when the trailer carries no error
+	ErrNoError = errors.New("trogongrpc: no error in trailer")
+	// ErrIncompleteTrailer is returned by DecodeTrailer when chunks are missing
+	ErrIncompleteTrailer = errors.New("trogongrpc: incomplete error trailer")
+)
+
+var defaultPolicy = trogonerror.NewSerializationPolicy(trogonerror.VisibilityPublic,
+	trogonerror.SerializationPolicyWithVisibilityFiltering())
+
+type encoder struct {
+	policy    *trogonerror.SerializationPolicy
+	chunkSize int
+}
+
+// Option represents options for EncodeTrailer
+type Option func(*encoder)
+
+// WithPolicy sets the serialization policy. By default errors are serialized for a public audience
+// with visibility filtering, so only public metadata reaches the client.
+func WithPolicy(policy *trogonerror.SerializationPolicy) Option {
+	return func(e *encoder) {
+		e.policy = policy
+	}
+}
+
+// WithChunkSize sets the maximum size of every trailer value, DefaultChunkSize by default
+func WithChunkSize(chunkSize int) Option {
+	return func(e *encoder) {
+		if chunkSize > 0 {
+			e.chunkSize = chunkSize
+		}
+	}
+}
+
+// EncodeTrailer serializes the error into trailer metadata, split into as many values as needed
+func EncodeTrailer(err *trogonerror.TrogonError, options ...Option) (map[string][]string, error) {
+	enc := &encoder{policy: defaultPolicy, chunkSize: DefaultChunkSize}
+	for _, option := range options {
+		option(enc)
+	}
+
+	payload, encodeErr := trogoncbor.MarshalFor(err, enc.policy)
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+
+	var chunks []string
+	for len(payload) > 0 {
+		n := min(len(payload), enc.chunkSize)
+		chunks = append(chunks, string(payload[:n]))
+		payload = payload[n:]
+	}
+
+	return map[string][]string{
+		TrailerError:  chunks,
+		TrailerChunks: {strconv.Itoa(len(chunks))},
+	}, nil
+}
+
+// DecodeTrailer reassembles an error written by EncodeTrailer, returning ErrNoError when the
+// trailer carries no error
+func DecodeTrailer(trailer map[string][]string) (*trogonerror.TrogonError, error) {
+	chunks := trailer[TrailerError]
+	if len(chunks) == 0 {
+		return nil, ErrNoError
+	}
+	if counts := trailer[TrailerChunks]; len(counts) > 0 {
+		expected, err := strconv.Atoi(counts[0])
+		if err != nil || expected != len(chunks) {
+			return nil, fmt.Errorf("%w: got %d chunks, want %s", ErrIncompleteTrailer, len(chunks), counts[0])
+		}
+	}
+
+	var payload []byte
+	for _, chunk := range chunks {
+		payload = append(payload, chunk...)
+	}
+
+	var decoded trogonerror.TrogonError
+	if err := trogoncbor.Unmarshal(payload, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
@@ -0,0 +1,65 @@
+package trogongrpc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogongrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCheckoutError() *trogonerror.TrogonError {
+	return trogonerror.NewError("shopify.checkout", "CART_INVALID",
+		trogonerror.WithCode(trogonerror.CodeInvalidArgument),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.WithMessage(strings.Repeat("every line item must be in stock; ", 200)),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "cartId", "gid://shopify/Cart/1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "carts-3"))
+}
+
+func TestEncodeTrailer(t *testing.T) {
+	t.Run("Round trip across chunks", func(t *testing.T) {
+		trailer, err := trogongrpc.EncodeTrailer(newCheckoutError(), trogongrpc.WithChunkSize(1024))
+		assert.NoError(t, err)
+		assert.Greater(t, len(trailer[trogongrpc.TrailerError]), 1)
+		for _, chunk := range trailer[trogongrpc.TrailerError] {
+			assert.LessOrEqual(t, len(chunk), 1024)
+		}
+
+		decoded, err := trogongrpc.DecodeTrailer(trailer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CART_INVALID", decoded.Reason())
+		assert.Equal(t, newCheckoutError().Message(), decoded.Message())
+		assert.Contains(t, decoded.Metadata(), "cartId")
+		assert.NotContains(t, decoded.Metadata(), "shard")
+	})
+
+	t.Run("Internal policy", func(t *testing.T) {
+		trailer, err := trogongrpc.EncodeTrailer(newCheckoutError(),
+			trogongrpc.WithPolicy(trogonerror.NewSerializationPolicy(trogonerror.VisibilityInternal)))
+		assert.NoError(t, err)
+
+		decoded, err := trogongrpc.DecodeTrailer(trailer)
+
+		assert.NoError(t, err)
+		assert.Contains(t, decoded.Metadata(), "shard")
+	})
+
+	t.Run("Missing chunks", func(t *testing.T) {
+		trailer, err := trogongrpc.EncodeTrailer(newCheckoutError(), trogongrpc.WithChunkSize(1024))
+		assert.NoError(t, err)
+		trailer[trogongrpc.TrailerError] = trailer[trogongrpc.TrailerError][1:]
+
+		_, err = trogongrpc.DecodeTrailer(trailer)
+
+		assert.ErrorIs(t, err, trogongrpc.ErrIncompleteTrailer)
+	})
+
+	t.Run("No error", func(t *testing.T) {
+		_, err := trogongrpc.DecodeTrailer(map[string][]string{"content-type": {"application/grpc"}})
+
+		assert.ErrorIs(t, err, trogongrpc.ErrNoError)
+	})
+}
@@ -0,0 +1,146 @@
+// Package trogonlint provides an analysis.Analyzer enforcing the TrogonError definition conventions
+// at build time. Run it with go vet through the trogonlint command or register it with golangci-lint:
+//
+//	go vet -vettool=$(which trogonlint) ./...
+package trogonlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/TrogonStack/trogonerror"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const packagePath = "github.com/TrogonStack/trogonerror"
+
+// Analyzer reports error definitions that break the TrogonError conventions:
+// domains that are not lowercase dotted identifiers, reasons that are not UPPER_SNAKE_CASE,
+// errors combining WithRetryInfoDuration and WithRetryTime, and direct NewError calls
+// in packages that declare error templates in package-level variables.
+var Analyzer = &analysis.Analyzer{
+	Name:     "trogonlint",
+	Doc:      "check TrogonError definitions against the specification conventions",
+	URL:      "https://pkg.go.dev/github.com/TrogonStack/trogonerror/trogonlint",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	declaresTemplates := declaresTemplates(pass)
+	var newErrorCalls []*ast.CallExpr
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+
+		switch name := calleeName(pass, call); name {
+		case "NewErrorTemplate":
+			checkIdentifiers(pass, call)
+		case "NewError", "NewErrorE":
+			if isPackageFunc(pass, call) {
+				checkIdentifiers(pass, call)
+				if name == "NewError" {
+					newErrorCalls = append(newErrorCalls, call)
+				}
+			}
+			checkRetryExclusivity(pass, call)
+		}
+	})
+
+	if declaresTemplates {
+		for _, call := range newErrorCalls {
+			pass.Reportf(call.Pos(), "package declares error templates; create errors from a template instead of calling NewError directly")
+		}
+	}
+
+	return nil, nil
+}
+
+// declaresTemplates reports whether the package declares error templates in package-level variables
+func declaresTemplates(pass *analysis.Pass) bool {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+
+			found := false
+			ast.Inspect(gen, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok && calleeName(pass, call) == "NewErrorTemplate" {
+					found = true
+				}
+				return !found
+			})
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// calleeName returns the name of the trogonerror function or method called, or ""
+func calleeName(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != packagePath {
+		return ""
+	}
+	return fn.Name()
+}
+
+func isPackageFunc(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	return fn != nil && fn.Signature().Recv() == nil
+}
+
+func checkIdentifiers(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) < 2 {
+		return
+	}
+	if domain, ok := constantString(pass, call.Args[0]); ok {
+		if err := trogonerror.ValidateDomain(domain); err != nil {
+			pass.Reportf(call.Args[0].Pos(), "domain %q should be lowercase dot-separated identifiers like \"shopify.users\"", domain)
+		}
+	}
+	if reason, ok := constantString(pass, call.Args[1]); ok {
+		if err := trogonerror.ValidateReason(reason); err != nil {
+			pass.Reportf(call.Args[1].Pos(), "reason %q should be UPPER_SNAKE_CASE like \"NOT_FOUND\"", reason)
+		}
+	}
+}
+
+func checkRetryExclusivity(pass *analysis.Pass, call *ast.CallExpr) {
+	var offset, retryTime ast.Expr
+	for _, arg := range call.Args {
+		option, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		switch calleeName(pass, option) {
+		case "WithRetryInfoDuration":
+			offset = option
+		case "WithRetryTime":
+			retryTime = option
+		}
+	}
+
+	if offset != nil && retryTime != nil {
+		pass.Reportf(retryTime.Pos(), "WithRetryInfoDuration and WithRetryTime are mutually exclusive; the last one silently wins")
+	}
+}
+
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
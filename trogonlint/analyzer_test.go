@@ -0,0 +1,12 @@
+package trogonlint_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror/trogonlint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), trogonlint.Analyzer, "orders", "payments")
+}
@@ -0,0 +1,14 @@
+// Command trogonlint checks TrogonError definitions against the specification conventions.
+//
+//	go install github.com/TrogonStack/trogonerror/trogonlint/cmd/trogonlint@latest
+//	go vet -vettool=$(which trogonlint) ./...
+package main
+
+import (
+	"github.com/TrogonStack/trogonerror/trogonlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(trogonlint.Analyzer)
+}
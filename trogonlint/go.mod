@@ -0,0 +1,13 @@
+module github.com/TrogonStack/trogonerror/trogonlint
+
+go 1.24.2
+
+require (
+	github.com/TrogonStack/trogonerror v0.4.0
+	golang.org/x/tools v0.42.0
+)
+
+require (
+	golang.org/x/mod v0.33.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+)
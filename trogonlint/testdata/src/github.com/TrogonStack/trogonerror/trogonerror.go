@@ -0,0 +1,23 @@
+package trogonerror
+
+import "time"
+
+type TrogonError struct{}
+
+type ErrorOption func(*TrogonError)
+
+type ErrorTemplate struct{}
+
+type TemplateOption func(*ErrorTemplate)
+
+func NewError(domain, reason string, options ...ErrorOption) *TrogonError { return nil }
+
+func NewErrorE(domain, reason string, options ...ErrorOption) (*TrogonError, error) { return nil, nil }
+
+func NewErrorTemplate(domain, reason string, options ...TemplateOption) *ErrorTemplate { return nil }
+
+func (et *ErrorTemplate) NewError(options ...ErrorOption) *TrogonError { return nil }
+
+func WithRetryInfoDuration(retryOffset time.Duration) ErrorOption { return nil }
+
+func WithRetryTime(retryTime time.Time) ErrorOption { return nil }
@@ -0,0 +1,28 @@
+package orders
+
+import (
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+const domain = "shopify.orders"
+
+func lookup(reason string) *trogonerror.TrogonError {
+	trogonerror.NewError(domain, "ORDER_NOT_FOUND")
+	trogonerror.NewError("Shopify.Orders", "ORDER_NOT_FOUND") // want `domain "Shopify.Orders" should be lowercase`
+	trogonerror.NewError(domain, "order_not_found")           // want `reason "order_not_found" should be UPPER_SNAKE_CASE`
+	trogonerror.NewErrorE(domain, "orderNotFound")            // want `reason "orderNotFound" should be UPPER_SNAKE_CASE`
+
+	trogonerror.NewError(domain, "ORDER_LOCKED",
+		trogonerror.WithRetryInfoDuration(time.Second),
+		trogonerror.WithRetryTime(time.Now())) // want `WithRetryInfoDuration and WithRetryTime are mutually exclusive`
+
+	return trogonerror.NewError(domain, reason)
+}
+
+func withLocalTemplate() *trogonerror.TrogonError {
+	local := trogonerror.NewErrorTemplate(domain, "ORDER_LOCKED")
+	local.NewError()
+	return trogonerror.NewError(domain, "ORDER_CANCELLED")
+}
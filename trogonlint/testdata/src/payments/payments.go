@@ -0,0 +1,19 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+var ErrPaymentDeclined = trogonerror.NewErrorTemplate("shopify.payments", "PAYMENT_DECLINED")
+
+var ErrGatewayTimeout = trogonerror.NewErrorTemplate("shopify.payments", "gateway-timeout") // want `reason "gateway-timeout" should be UPPER_SNAKE_CASE`
+
+func charge() *trogonerror.TrogonError {
+	ErrPaymentDeclined.NewError(
+		trogonerror.WithRetryInfoDuration(time.Second),
+		trogonerror.WithRetryTime(time.Now())) // want `WithRetryInfoDuration and WithRetryTime are mutually exclusive`
+
+	return trogonerror.NewError("shopify.payments", "CARD_EXPIRED") // want `package declares error templates`
+}
@@ -0,0 +1,89 @@
+// Package trogonotel propagates error identities through OpenTelemetry baggage, so logs and spans
+// of downstream services can reference the error that originated a failure without carrying
+// the whole payload.
+//
+//	ctx, err := trogonotel.ContextWithError(ctx, trogonErr, trogonotel.WithIdentity())
+//	// outgoing requests made with ctx carry the error ID in their baggage header
+//
+//	if id, ok := trogonotel.ErrorID(ctx); ok {
+//	    logger = logger.With("origin_error_id", id)
+//	}
+package trogonotel
+
+import (
+	"context"
+
+	"github.com/TrogonStack/trogonerror"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// Baggage member keys written by ContextWithError
+const (
+	BaggageErrorID     = "trogon.error.id"
+	BaggageErrorDomain = "trogon.error.domain"
+	BaggageErrorReason = "trogon.error.reason"
+)
+
+type options struct {
+	identity bool
+}
+
+// Option represents options for ContextWithError
+type Option func(*options)
+
+// WithIdentity also propagates the error domain and reason. They are only added for public errors,
+// since baggage is forwarded to every downstream service.
+func WithIdentity() Option {
+	return func(o *options) {
+		o.identity = true
+	}
+}
+
+// ContextWithError returns a copy of ctx whose baggage carries the error ID, and optionally its
+// domain and reason. Errors without an ID leave ctx unchanged; WithGeneratedID assigns one.
+func ContextWithError(ctx context.Context, err *trogonerror.TrogonError, opts ...Option) (context.Context, error) {
+	if err == nil || err.ID() == "" {
+		return ctx, nil
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	values := map[string]string{BaggageErrorID: err.ID()}
+	if o.identity && err.Visibility() == trogonerror.VisibilityPublic {
+		values[BaggageErrorDomain] = err.Domain()
+		values[BaggageErrorReason] = err.Reason()
+	}
+
+	bag := baggage.FromContext(ctx)
+	for _, key := range []string{BaggageErrorID, BaggageErrorDomain, BaggageErrorReason} {
+		value, ok := values[key]
+		if !ok {
+			bag = bag.DeleteMember(key)
+			continue
+		}
+		member, memberErr := baggage.NewMemberRaw(key, value)
+		if memberErr != nil {
+			return ctx, memberErr
+		}
+		if bag, memberErr = bag.SetMember(member); memberErr != nil {
+			return ctx, memberErr
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// ErrorID returns the originating error ID carried by the baggage of ctx, if any
+func ErrorID(ctx context.Context) (string, bool) {
+	id := baggage.FromContext(ctx).Member(BaggageErrorID).Value()
+	return id, id != ""
+}
+
+// ErrorIdentity returns the originating error domain and reason carried by the baggage of ctx, if any
+func ErrorIdentity(ctx context.Context) (domain, reason string, ok bool) {
+	bag := baggage.FromContext(ctx)
+	domain, reason = bag.Member(BaggageErrorDomain).Value(), bag.Member(BaggageErrorReason).Value()
+	return domain, reason, domain != "" && reason != ""
+}
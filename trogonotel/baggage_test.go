@@ -0,0 +1,63 @@
+package trogonotel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogonotel"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestContextWithError(t *testing.T) {
+	t.Run("Propagates the error ID", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithID("err_123"))
+
+		ctx, bagErr := trogonotel.ContextWithError(context.Background(), err)
+
+		assert.NoError(t, bagErr)
+		id, ok := trogonotel.ErrorID(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "err_123", id)
+		_, _, ok = trogonotel.ErrorIdentity(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("Identity of public errors", func(t *testing.T) {
+		member, _ := baggage.NewMemberRaw("tenant", "acme")
+		existing, _ := baggage.New(member)
+		ctx := baggage.ContextWithBaggage(context.Background(), existing)
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithID("err_123"),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic))
+
+		ctx, bagErr := trogonotel.ContextWithError(ctx, err, trogonotel.WithIdentity())
+
+		assert.NoError(t, bagErr)
+		domain, reason, ok := trogonotel.ErrorIdentity(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "shopify.orders", domain)
+		assert.Equal(t, "ORDER_NOT_FOUND", reason)
+		assert.Equal(t, "acme", baggage.FromContext(ctx).Member("tenant").Value())
+	})
+
+	t.Run("Identity of internal errors is not propagated", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.database", "DEADLOCK", trogonerror.WithID("err_456"))
+
+		ctx, bagErr := trogonotel.ContextWithError(context.Background(), err, trogonotel.WithIdentity())
+
+		assert.NoError(t, bagErr)
+		_, _, ok := trogonotel.ErrorIdentity(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("Errors without an ID", func(t *testing.T) {
+		ctx := context.Background()
+
+		got, bagErr := trogonotel.ContextWithError(ctx, trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND"))
+
+		assert.NoError(t, bagErr)
+		assert.Equal(t, ctx, got)
+	})
+}
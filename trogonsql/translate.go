@@ -0,0 +1,153 @@
+// Package trogonsql translates database/sql and driver errors into TrogonErrors.
+//
+// Importing the package registers Translate with trogonerror.RegisterTranslator:
+//
+//	import _ "github.com/TrogonStack/trogonerror/trogonsql"
+//
+// Driver errors are recognized by shape rather than by type, so no driver is imported:
+// PostgreSQL errors expose their SQLSTATE through a SQLState() method or a Code field
+// (pgx, lib/pq), and MySQL errors through a Number field (go-sql-driver/mysql).
+package trogonsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/TrogonStack/trogonerror"
+)
+
+const domain = "trogon.sql"
+
+var (
+	// ErrNoRows is the template for queries expecting a row that returned none
+	ErrNoRows = trogonerror.NewErrorTemplate(domain, "NO_ROWS",
+		trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+
+	// ErrUniqueViolation is the template for inserts or updates violating a unique constraint
+	ErrUniqueViolation = trogonerror.NewErrorTemplate(domain, "UNIQUE_VIOLATION",
+		trogonerror.TemplateWithCode(trogonerror.CodeAlreadyExists))
+
+	// ErrSerializationFailure is the template for transactions aborted by a serialization conflict
+	ErrSerializationFailure = trogonerror.NewErrorTemplate(domain, "SERIALIZATION_FAILURE",
+		trogonerror.TemplateWithCode(trogonerror.CodeAborted))
+
+	// ErrDeadlock is the template for transactions aborted to break a deadlock or a lock wait timeout
+	ErrDeadlock = trogonerror.NewErrorTemplate(domain, "DEADLOCK",
+		trogonerror.TemplateWithCode(trogonerror.CodeAborted))
+)
+
+// SQLSTATE classes and MySQL error numbers recognized by Translate
+const (
+	sqlStateUniqueViolation      = "23505"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+
+	mysqlDuplicateEntry  = 1062
+	mysqlLockWaitTimeout = 1205
+	mysqlLockDeadlock    = 1213
+)
+
+func init() {
+	trogonerror.RegisterTranslator("database/sql", Translate)
+}
+
+// Translate is a trogonerror.Translator for database errors. The original error is wrapped and
+// the SQLSTATE, MySQL error number, constraint and table, when known, are recorded as internal metadata.
+func Translate(err error) (*trogonerror.TrogonError, bool) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoRows.NewError(trogonerror.WithWrap(err)), true
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return trogonerror.FromContextError(err)
+	}
+
+	details, ok := driverDetails(err)
+	if !ok {
+		return nil, false
+	}
+
+	var template *trogonerror.ErrorTemplate
+	switch {
+	case details.sqlState == sqlStateUniqueViolation || details.number == mysqlDuplicateEntry:
+		template = ErrUniqueViolation
+	case details.sqlState == sqlStateSerializationFailure:
+		template = ErrSerializationFailure
+	case details.sqlState == sqlStateDeadlockDetected || details.number == mysqlLockDeadlock || details.number == mysqlLockWaitTimeout:
+		template = ErrDeadlock
+	default:
+		return nil, false
+	}
+
+	options := []trogonerror.ErrorOption{trogonerror.WithWrap(err)}
+	for _, entry := range []struct{ key, value string }{
+		{"sqlState", details.sqlState},
+		{"mysqlErrorNumber", details.numberText()},
+		{"constraint", details.constraint},
+		{"table", details.table},
+	} {
+		if entry.value != "" {
+			options = append(options, trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, entry.key, entry.value))
+		}
+	}
+	return template.NewError(options...), true
+}
+
+type driverError struct {
+	sqlState   string
+	number     uint64
+	constraint string
+	table      string
+}
+
+func (d driverError) numberText() string {
+	if d.number == 0 {
+		return ""
+	}
+	return strconv.FormatUint(d.number, 10)
+}
+
+// driverDetails reads the details of the first error in the chain that looks like a driver error
+func driverDetails(err error) (driverError, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		value := reflect.ValueOf(err)
+		var details driverError
+
+		if method := value.MethodByName("SQLState"); method.IsValid() && method.Type().NumIn() == 0 &&
+			method.Type().NumOut() == 1 && method.Type().Out(0).Kind() == reflect.String {
+			details.sqlState = method.Call(nil)[0].String()
+		}
+
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Struct {
+			if details.sqlState == "" {
+				if code := stringField(value, "Code"); len(code) == 5 {
+					details.sqlState = code
+				}
+			}
+			if number := value.FieldByName("Number"); number.IsValid() && number.CanUint() {
+				details.number = number.Uint()
+			}
+			details.constraint = stringField(value, "ConstraintName", "Constraint")
+			details.table = stringField(value, "TableName", "Table")
+		}
+
+		if details.sqlState != "" || details.number != 0 {
+			return details, true
+		}
+	}
+	return driverError{}, false
+}
+
+func stringField(value reflect.Value, names ...string) string {
+	for _, name := range names {
+		if field := value.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+			return field.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package trogonsql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogonsql"
+	"github.com/stretchr/testify/assert"
+)
+
+// pgError mirrors the shape of pgconn.PgError
+type pgError struct {
+	Code           string
+	Message        string
+	ConstraintName string
+	TableName      string
+}
+
+func (e *pgError) Error() string    { return "ERROR: " + e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *pgError) SQLState() string { return e.Code }
+
+// pqErrorCode and pqError mirror the shape of lib/pq's Error, whose code has a named string type
+type pqErrorCode string
+
+type pqError struct {
+	Code       pqErrorCode
+	Constraint string
+	Table      string
+}
+
+func (e pqError) Error() string { return "pq: " + string(e.Code) }
+
+// mysqlError mirrors the shape of go-sql-driver/mysql's MySQLError
+type mysqlError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		template *trogonerror.ErrorTemplate
+		code     trogonerror.Code
+		metadata map[string]string
+	}{
+		{
+			name:     "No rows",
+			err:      fmt.Errorf("find order: %w", sql.ErrNoRows),
+			template: trogonsql.ErrNoRows,
+			code:     trogonerror.CodeNotFound,
+		},
+		{
+			name:     "pgx unique violation",
+			err:      &pgError{Code: "23505", Message: "duplicate key", ConstraintName: "orders_pkey", TableName: "orders"},
+			template: trogonsql.ErrUniqueViolation,
+			code:     trogonerror.CodeAlreadyExists,
+			metadata: map[string]string{"sqlState": "23505", "constraint": "orders_pkey", "table": "orders"},
+		},
+		{
+			name:     "lib/pq serialization failure",
+			err:      fmt.Errorf("commit: %w", pqError{Code: "40001", Table: "inventory"}),
+			template: trogonsql.ErrSerializationFailure,
+			code:     trogonerror.CodeAborted,
+			metadata: map[string]string{"sqlState": "40001", "table": "inventory"},
+		},
+		{
+			name:     "PostgreSQL deadlock",
+			err:      &pgError{Code: "40P01", Message: "deadlock detected"},
+			template: trogonsql.ErrDeadlock,
+			code:     trogonerror.CodeAborted,
+			metadata: map[string]string{"sqlState": "40P01"},
+		},
+		{
+			name:     "MySQL duplicate entry",
+			err:      &mysqlError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"},
+			template: trogonsql.ErrUniqueViolation,
+			code:     trogonerror.CodeAlreadyExists,
+			metadata: map[string]string{"mysqlErrorNumber": "1062"},
+		},
+		{
+			name:     "MySQL deadlock",
+			err:      &mysqlError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+			template: trogonsql.ErrDeadlock,
+			code:     trogonerror.CodeAborted,
+			metadata: map[string]string{"mysqlErrorNumber": "1213"},
+		},
+		{
+			name:     "Context deadline",
+			err:      fmt.Errorf("query: %w", context.DeadlineExceeded),
+			template: trogonerror.ErrContextDeadlineExceeded,
+			code:     trogonerror.CodeDeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err, ok := trogonsql.Translate(tt.err)
+
+			assert.True(t, ok)
+			assert.True(t, tt.template.Is(err))
+			assert.Equal(t, tt.code, err.Code())
+			assert.ErrorIs(t, err, tt.err)
+			for key, value := range tt.metadata {
+				assert.Equal(t, value, err.Metadata()[key].Value(), key)
+				assert.Equal(t, trogonerror.VisibilityInternal, err.Metadata()[key].Visibility(), key)
+			}
+		})
+	}
+
+	t.Run("Unrecognized errors", func(t *testing.T) {
+		for _, err := range []error{
+			errors.New("connection reset"),
+			&pgError{Code: "42P01", Message: "relation does not exist"},
+			&mysqlError{Number: 1146, Message: "Table doesn't exist"},
+		} {
+			_, ok := trogonsql.Translate(err)
+			assert.False(t, ok, err.Error())
+		}
+	})
+
+	t.Run("Registered with trogonerror.Translate", func(t *testing.T) {
+		err := trogonerror.Translate(sql.ErrNoRows)
+
+		assert.True(t, trogonsql.ErrNoRows.Is(err))
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/TrogonStack/trogonerror/trogontwirp
+
+go 1.24.2
+
+require (
+	github.com/TrogonStack/trogonerror v0.4.0
+	github.com/stretchr/testify v1.11.1
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,121 @@
+// Package trogontwirp converts between TrogonErrors and Twirp errors, so services still on
+// Twirp can participate in the same error model.
+package trogontwirp
+
+import (
+	"strings"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/twitchtv/twirp"
+)
+
+// Twirp meta keys carrying the error identity across the wire
+const (
+	MetaDomain  = "trogon-domain"
+	MetaReason  = "trogon-reason"
+	MetaID      = "trogon-id"
+	MetaSubject = "trogon-subject"
+)
+
+// Domain is used for errors from Twirp services that did not send a TrogonError
+const Domain = "twirp"
+
+var twirpCodes = map[trogonerror.Code]twirp.ErrorCode{
+	trogonerror.CodeCancelled:          twirp.Canceled,
+	trogonerror.CodeUnknown:            twirp.Unknown,
+	trogonerror.CodeInvalidArgument:    twirp.InvalidArgument,
+	trogonerror.CodeDeadlineExceeded:   twirp.DeadlineExceeded,
+	trogonerror.CodeNotFound:           twirp.NotFound,
+	trogonerror.CodeAlreadyExists:      twirp.AlreadyExists,
+	trogonerror.CodePermissionDenied:   twirp.PermissionDenied,
+	trogonerror.CodeResourceExhausted:  twirp.ResourceExhausted,
+	trogonerror.CodeFailedPrecondition: twirp.FailedPrecondition,
+	trogonerror.CodeAborted:            twirp.Aborted,
+	trogonerror.CodeOutOfRange:         twirp.OutOfRange,
+	trogonerror.CodeUnimplemented:      twirp.Unimplemented,
+	trogonerror.CodeInternal:           twirp.Internal,
+	trogonerror.CodeUnavailable:        twirp.Unavailable,
+	trogonerror.CodeDataLoss:           twirp.DataLoss,
+	trogonerror.CodeUnauthenticated:    twirp.Unauthenticated,
+}
+
+// TwirpCode returns the Twirp error code for code
+func TwirpCode(code trogonerror.Code) twirp.ErrorCode {
+	if twirpCode, ok := twirpCodes[code]; ok {
+		return twirpCode
+	}
+	return twirp.Unknown
+}
+
+// Code returns the TrogonError code for a Twirp error code.
+// Twirp's malformed and bad_route map to CodeInvalidArgument and CodeUnimplemented.
+func Code(twirpCode twirp.ErrorCode) trogonerror.Code {
+	switch twirpCode {
+	case twirp.Malformed:
+		return trogonerror.CodeInvalidArgument
+	case twirp.BadRoute:
+		return trogonerror.CodeUnimplemented
+	}
+	for code, candidate := range twirpCodes {
+		if candidate == twirpCode {
+			return code
+		}
+	}
+	return trogonerror.CodeUnknown
+}
+
+// ToTwirpError converts the error for a Twirp response. The error is masked with MaskForPublic first,
+// so non-public errors only expose their code, public message and ID. Public metadata becomes Twirp metas,
+// next to the domain, reason, ID and subject. The original TrogonError is wrapped, so server hooks can
+// still retrieve it with errors.As.
+func ToTwirpError(err *trogonerror.TrogonError) twirp.Error {
+	public := trogonerror.MaskForPublic(err)
+	twerr := twirp.NewError(TwirpCode(public.Code()), public.Message()).
+		WithMeta(MetaDomain, public.Domain()).
+		WithMeta(MetaReason, public.Reason())
+	if public.ID() != "" {
+		twerr = twerr.WithMeta(MetaID, public.ID())
+	}
+	if public.Subject() != "" {
+		twerr = twerr.WithMeta(MetaSubject, public.Subject())
+	}
+	for key, value := range public.AllMetadata() {
+		if value.Visibility() == trogonerror.VisibilityPublic {
+			twerr = twerr.WithMeta(key, value.Value())
+		}
+	}
+	return twirp.WrapError(twerr, err)
+}
+
+// FromTwirpError converts an error received from a Twirp service. The domain and reason come
+// from the metas written by ToTwirpError; errors from other services get Domain and the
+// upper-cased Twirp code as reason. The remaining metas become public metadata.
+func FromTwirpError(twerr twirp.Error) *trogonerror.TrogonError {
+	domain, reason := twerr.Meta(MetaDomain), twerr.Meta(MetaReason)
+	if domain == "" || reason == "" {
+		domain, reason = Domain, strings.ToUpper(string(twerr.Code()))
+	}
+
+	options := []trogonerror.ErrorOption{
+		trogonerror.WithCode(Code(twerr.Code())),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+	}
+	if twerr.Msg() != "" {
+		options = append(options, trogonerror.WithMessage(twerr.Msg()))
+	}
+	if id := twerr.Meta(MetaID); id != "" {
+		options = append(options, trogonerror.WithID(id))
+	}
+	if subject := twerr.Meta(MetaSubject); subject != "" {
+		options = append(options, trogonerror.WithSubject(subject))
+	}
+	for key, value := range twerr.MetaMap() {
+		switch key {
+		case MetaDomain, MetaReason, MetaID, MetaSubject:
+			continue
+		}
+		options = append(options, trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, key, value))
+	}
+
+	return trogonerror.NewError(domain, reason, options...)
+}
@@ -0,0 +1,90 @@
+package trogontwirp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/TrogonStack/trogonerror/trogontwirp"
+	"github.com/stretchr/testify/assert"
+	"github.com/twitchtv/twirp"
+)
+
+func TestToTwirpError(t *testing.T) {
+	err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+		trogonerror.WithCode(trogonerror.CodeNotFound),
+		trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+		trogonerror.WithID("err_123"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"),
+		trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "orders-7"))
+
+	twerr := trogontwirp.ToTwirpError(err)
+
+	assert.Equal(t, twirp.NotFound, twerr.Code())
+	assert.Equal(t, "resource not found", twerr.Msg())
+	assert.Equal(t, map[string]string{
+		trogontwirp.MetaDomain: "shopify.orders",
+		trogontwirp.MetaReason: "ORDER_NOT_FOUND",
+		trogontwirp.MetaID:     "err_123",
+		"orderId":              "gid://shopify/Order/1",
+	}, twerr.MetaMap())
+
+	var trogonErr *trogonerror.TrogonError
+	assert.True(t, errors.As(twerr, &trogonErr))
+	assert.Same(t, err, trogonErr)
+}
+
+func TestToTwirpErrorMasksInternalErrors(t *testing.T) {
+	err := trogonerror.NewError("shopify.database", "SHARD_UNREACHABLE",
+		trogonerror.WithCode(trogonerror.CodeUnavailable),
+		trogonerror.WithMessage("orders-7 primary refused connection"),
+		trogonerror.WithID("err_456"))
+
+	twerr := trogontwirp.ToTwirpError(err)
+
+	assert.Equal(t, twirp.Unavailable, twerr.Code())
+	assert.NotContains(t, twerr.Msg(), "orders-7")
+	assert.Equal(t, map[string]string{
+		trogontwirp.MetaDomain: trogonerror.MaskedDomain,
+		trogontwirp.MetaReason: "UNAVAILABLE",
+		trogontwirp.MetaID:     "err_456",
+	}, twerr.MetaMap())
+
+	var trogonErr *trogonerror.TrogonError
+	assert.True(t, errors.As(twerr, &trogonErr))
+	assert.Same(t, err, trogonErr)
+}
+
+func TestFromTwirpError(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		original := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithSubject("/orderId"),
+			trogonerror.WithID("err_123"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "gid://shopify/Order/1"))
+
+		converted := trogontwirp.FromTwirpError(trogontwirp.ToTwirpError(original))
+
+		assert.True(t, trogonerror.Equal(original, converted, trogonerror.CompareIgnoringTime()))
+	})
+
+	t.Run("Errors from other Twirp services", func(t *testing.T) {
+		converted := trogontwirp.FromTwirpError(twirp.InvalidArgumentError("email", "must be valid"))
+
+		assert.Equal(t, trogontwirp.Domain, converted.Domain())
+		assert.Equal(t, "INVALID_ARGUMENT", converted.Reason())
+		assert.Equal(t, trogonerror.CodeInvalidArgument, converted.Code())
+		assert.Equal(t, "email must be valid", converted.Message())
+		assert.Equal(t, "email", converted.Metadata()["argument"].Value())
+	})
+}
+
+func TestCode(t *testing.T) {
+	for code := trogonerror.CodeCancelled; code <= trogonerror.CodeUnauthenticated; code++ {
+		assert.Equal(t, code, trogontwirp.Code(trogontwirp.TwirpCode(code)), code.String())
+	}
+	assert.Equal(t, trogonerror.CodeInvalidArgument, trogontwirp.Code(twirp.Malformed))
+	assert.Equal(t, trogonerror.CodeUnimplemented, trogontwirp.Code(twirp.BadRoute))
+	assert.Equal(t, trogonerror.CodeUnknown, trogontwirp.Code("teapot"))
+}
@@ -0,0 +1,127 @@
+package trogonerror
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrorTemplateT is an ErrorTemplate whose errors take a typed payload, so error parameters are
+// checked by the compiler instead of being spelled as metadata keys at every call site.
+// Fields of the payload struct tagged with `trogon:"key,visibility[,omitempty]"` become metadata;
+// the visibility is one of internal (the default), private or public, and omitempty skips zero values.
+// Untagged fields are ignored.
+//
+// Example usage:
+//
+//	type OrderNotFound struct {
+//	    OrderID string `trogon:"orderId,public"`
+//	    ShopID  string `trogon:"shopId,private"`
+//	    Shard   int    `trogon:"shard,internal,omitempty"`
+//	}
+//
+//	var ErrOrderNotFound = trogonerror.NewErrorTemplateT[OrderNotFound]("shopify.orders", "ORDER_NOT_FOUND",
+//	    trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+//
+//	return ErrOrderNotFound.NewError(OrderNotFound{OrderID: id, ShopID: shop})
+type ErrorTemplateT[T any] struct {
+	template *ErrorTemplate
+	fields   []typedField
+}
+
+type typedField struct {
+	index      []int
+	key        string
+	visibility Visibility
+	omitEmpty  bool
+}
+
+// NewErrorTemplateT creates a typed template. It panics when T is not a struct, or a pointer to one,
+// or when a tag is malformed, since templates are defined at package level.
+func NewErrorTemplateT[T any](domain, reason string, options ...TemplateOption) *ErrorTemplateT[T] {
+	return &ErrorTemplateT[T]{
+		template: NewErrorTemplate(domain, reason, options...),
+		fields:   typedFields(reflect.TypeFor[T]()),
+	}
+}
+
+func typedFields(typ reflect.Type) []typedField {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("trogonerror: typed template payload must be a struct, got %s", typ))
+	}
+
+	var fields []typedField
+	for _, field := range reflect.VisibleFields(typ) {
+		tag, ok := field.Tag.Lookup("trogon")
+		if !ok || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			panic(fmt.Sprintf("trogonerror: tagged field %s.%s must be exported", typ, field.Name))
+		}
+
+		key, flags, _ := strings.Cut(tag, ",")
+		if key == "" {
+			panic(fmt.Sprintf("trogonerror: tagged field %s.%s has no metadata key", typ, field.Name))
+		}
+		typed := typedField{index: field.Index, key: key, visibility: VisibilityInternal}
+		for flag := range strings.SplitSeq(flags, ",") {
+			switch flag {
+			case "", "internal":
+			case "private":
+				typed.visibility = VisibilityPrivate
+			case "public":
+				typed.visibility = VisibilityPublic
+			case "omitempty":
+				typed.omitEmpty = true
+			default:
+				panic(fmt.Sprintf("trogonerror: tagged field %s.%s has unknown option %q", typ, field.Name, flag))
+			}
+		}
+		fields = append(fields, typed)
+	}
+	return fields
+}
+
+// NewError creates an error from the template with the payload fields as metadata, then applies options
+func (t *ErrorTemplateT[T]) NewError(payload T, options ...ErrorOption) *TrogonError {
+	return t.template.NewError(append([]ErrorOption{t.withPayload(payload)}, options...)...)
+}
+
+func (t *ErrorTemplateT[T]) withPayload(payload T) ErrorOption {
+	return mutable(func(e *TrogonError) {
+		value := reflect.ValueOf(payload)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return
+			}
+			value = value.Elem()
+		}
+
+		for _, field := range t.fields {
+			fieldValue, err := value.FieldByIndexErr(field.index)
+			if err != nil || (field.omitEmpty && fieldValue.IsZero()) {
+				continue
+			}
+			addMetadataValue(e, field.visibility, field.key, formatTypedValue(fieldValue))
+		}
+	})
+}
+
+func formatTypedValue(value reflect.Value) string {
+	if value.Kind() == reflect.String {
+		return value.String()
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// Template returns the untyped template, for APIs such as AsAny that take an *ErrorTemplate
+func (t *ErrorTemplateT[T]) Template() *ErrorTemplate { return t.template }
+
+// Is checks if the given error matches this template's domain and reason
+func (t *ErrorTemplateT[T]) Is(target error) bool {
+	return t.template.Is(target)
+}
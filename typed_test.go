@@ -0,0 +1,58 @@
+package trogonerror_test
+
+import (
+	"testing"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderNotFound struct {
+	OrderID string `trogon:"orderId,public"`
+	ShopID  string `trogon:"shopId,private"`
+	Shard   int    `trogon:"shard,omitempty"`
+	Retried bool   `trogon:"retried"`
+	Note    string
+}
+
+var errTypedOrderNotFound = trogonerror.NewErrorTemplateT[orderNotFound]("shopify.orders", "ORDER_NOT_FOUND",
+	trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+
+func TestErrorTemplateT(t *testing.T) {
+	t.Run("Maps tagged fields to metadata", func(t *testing.T) {
+		err := errTypedOrderNotFound.NewError(orderNotFound{OrderID: "gid://shopify/Order/1", ShopID: "shop_1", Note: "ignored"})
+
+		assert.Equal(t, trogonerror.CodeNotFound, err.Code())
+		metadata := err.Metadata()
+		assert.Len(t, metadata, 3)
+		assert.Equal(t, "gid://shopify/Order/1", metadata["orderId"].Value())
+		assert.Equal(t, trogonerror.VisibilityPublic, metadata["orderId"].Visibility())
+		assert.Equal(t, trogonerror.VisibilityPrivate, metadata["shopId"].Visibility())
+		assert.Equal(t, "false", metadata["retried"].Value())
+		assert.Equal(t, trogonerror.VisibilityInternal, metadata["retried"].Visibility())
+		assert.True(t, errTypedOrderNotFound.Is(err))
+	})
+
+	t.Run("Options are applied after the payload", func(t *testing.T) {
+		err := errTypedOrderNotFound.NewError(orderNotFound{Shard: 7},
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "orders-7"))
+
+		assert.Equal(t, "orders-7", err.Metadata()["shard"].Value())
+	})
+
+	t.Run("Pointer payloads", func(t *testing.T) {
+		template := trogonerror.NewErrorTemplateT[*orderNotFound]("shopify.orders", "ORDER_NOT_FOUND")
+
+		assert.Equal(t, "gid://shopify/Order/1", template.NewError(&orderNotFound{OrderID: "gid://shopify/Order/1"}).Metadata()["orderId"].Value())
+		assert.Empty(t, template.NewError(nil).Metadata())
+	})
+
+	t.Run("Invalid payload types", func(t *testing.T) {
+		assert.Panics(t, func() { trogonerror.NewErrorTemplateT[string]("shopify.orders", "ORDER_NOT_FOUND") })
+		assert.Panics(t, func() {
+			trogonerror.NewErrorTemplateT[struct {
+				ID string `trogon:"id,secret"`
+			}]("shopify.orders", "ORDER_NOT_FOUND")
+		})
+	})
+}
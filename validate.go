@@ -0,0 +1,287 @@
+package trogonerror
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+var (
+	// ErrInvalidDomain reports a domain that is not a lowercase dotted identifier like "shopify.users"
+	ErrInvalidDomain = errors.New("trogonerror: invalid domain")
+	// ErrInvalidReason reports a reason that is not an UPPER_SNAKE_CASE identifier like "NOT_FOUND"
+	ErrInvalidReason = errors.New("trogonerror: invalid reason")
+	// ErrConflictingOptions reports options that overwrite each other with different values
+	ErrConflictingOptions = errors.New("trogonerror: conflicting options")
+	// ErrInvalidSpecVersion reports a missing specification version
+	ErrInvalidSpecVersion = errors.New("trogonerror: invalid spec version")
+	// ErrInvalidCode reports a code outside the range defined by the specification
+	ErrInvalidCode = errors.New("trogonerror: invalid code")
+	// ErrInvalidVisibility reports a visibility outside the defined values, or, in ValidateStrict,
+	// metadata more visible than its error
+	ErrInvalidVisibility = errors.New("trogonerror: invalid visibility")
+	// ErrInvalidRetryInfo reports retry info with both or neither of a retry offset and a retry time,
+	// or with a negative max attempts or max delay, or a backoff multiplier below 1
+	ErrInvalidRetryInfo = errors.New("trogonerror: invalid retry info")
+	// ErrInvalidRateLimitInfo reports rate limit info with a negative limit, remaining count or window,
+	// or more remaining requests than the limit allows
+	ErrInvalidRateLimitInfo = errors.New("trogonerror: invalid rate limit info")
+	// ErrInvalidDeadlineInfo reports deadline info with a negative elapsed time or stage duration, or an unnamed stage
+	ErrInvalidDeadlineInfo = errors.New("trogonerror: invalid deadline info")
+	// ErrInvalidLocale reports a localized message whose locale is not a well-formed BCP 47 language tag
+	ErrInvalidLocale = errors.New("trogonerror: invalid locale")
+)
+
+var (
+	domainPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*(\.[a-z][a-z0-9_-]*)*$`)
+	reasonPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+	// localePattern follows the langtag and privateuse productions of RFC 5646
+	localePattern = regexp.MustCompile(`^(?i:[a-z]{2,3}(-[a-z]{3}){0,3}|[a-z]{4,8})(?i:-[a-z]{4})?(?i:-([a-z]{2}|[0-9]{3}))?` +
+		`(?i:-([a-z0-9]{5,8}|[0-9][a-z0-9]{3}))*(?i:-[0-9a-wy-z](-[a-z0-9]{2,8})+)*(?i:-x(-[a-z0-9]{1,8})+)?$|^(?i:x(-[a-z0-9]{1,8})+)$`)
+)
+
+// ValidateDomain checks that domain is a lowercase dotted identifier like "shopify.users"
+func ValidateDomain(domain string) error {
+	if !domainPattern.MatchString(domain) {
+		return fmt.Errorf("%w %q: must be lowercase dot-separated identifiers like \"shopify.users\"", ErrInvalidDomain, domain)
+	}
+	return nil
+}
+
+// ValidateReason checks that reason is an UPPER_SNAKE_CASE identifier like "NOT_FOUND"
+func ValidateReason(reason string) error {
+	if !reasonPattern.MatchString(reason) {
+		return fmt.Errorf("%w %q: must be UPPER_SNAKE_CASE like \"NOT_FOUND\"", ErrInvalidReason, reason)
+	}
+	return nil
+}
+
+// ParseCode returns the code named name, such as "NOT_FOUND"
+func ParseCode(name string) (Code, error) {
+	for code := CodeCancelled; code <= CodeUnauthenticated; code++ {
+		if code.String() == name {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrInvalidCode, name)
+}
+
+// ParseVisibility returns the visibility named name, such as "PUBLIC"
+func ParseVisibility(name string) (Visibility, error) {
+	for _, visibility := range []Visibility{VisibilityInternal, VisibilityPrivate, VisibilityPublic} {
+		if visibility.String() == name {
+			return visibility, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrInvalidVisibility, name)
+}
+
+// NewErrorE creates a TrogonError like NewError but validates its input instead of accepting it silently:
+// the domain and reason format, options overwriting each other's code or visibility with a different
+// value, retry offsets mixed with retry times, and malformed JSON Pointer subjects. It is meant for errors
+// built from dynamic input such as configuration or network payloads; all problems are reported together
+// with errors.Join.
+func NewErrorE(domain, reason string, options ...ErrorOption) (*TrogonError, error) {
+	var errs []error
+	if err := ValidateDomain(domain); err != nil {
+		errs = append(errs, err)
+	}
+	if err := ValidateReason(reason); err != nil {
+		errs = append(errs, err)
+	}
+
+	err := &TrogonError{
+		specVersion: SpecVersion,
+		code:        CodeUnknown,
+		domain:      domain,
+		reason:      reason,
+		visibility:  VisibilityInternal,
+	}
+
+	errs = append(errs, applyOptionsStrictly(err, options)...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	applyDefaults(err)
+	return err, nil
+}
+
+// NewErrorE creates an error from the template like NewError, but reports options overwriting each
+// other's code or visibility with a different value, retry offsets mixed with retry times,
+// and malformed JSON Pointer subjects, like the package-level NewErrorE
+func (et *ErrorTemplate) NewErrorE(options ...ErrorOption) (*TrogonError, error) {
+	err := et.newPrototypeCopy()
+	if errs := applyOptionsStrictly(err, options); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	applyDefaults(err)
+	return err, nil
+}
+
+// MustNewError is like NewErrorE but panics on invalid input. It is meant for package-level
+// sentinel errors, where a silent misconfiguration is worse than a crash at startup.
+func MustNewError(domain, reason string, options ...ErrorOption) *TrogonError {
+	err, validationErr := NewErrorE(domain, reason, options...)
+	if validationErr != nil {
+		panic(validationErr)
+	}
+	return err
+}
+
+// MustNewError is like NewErrorE but panics on conflicting options
+func (et *ErrorTemplate) MustNewError(options ...ErrorOption) *TrogonError {
+	err, validationErr := et.NewErrorE(options...)
+	if validationErr != nil {
+		panic(validationErr)
+	}
+	return err
+}
+
+// MustNewErrorTemplate is like NewErrorTemplate but panics when the domain or reason is invalid.
+// It is meant for package-level template definitions.
+//
+// Example usage:
+//
+//	var ErrOrderNotFound = trogonerror.MustNewErrorTemplate("shopify.orders", "ORDER_NOT_FOUND",
+//	    trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+func MustNewErrorTemplate(domain, reason string, options ...TemplateOption) *ErrorTemplate {
+	if err := errors.Join(ValidateDomain(domain), ValidateReason(reason)); err != nil {
+		panic(err)
+	}
+	return NewErrorTemplate(domain, reason, options...)
+}
+
+// applyOptionsStrictly applies options to err, reporting the ones overwriting each other
+// and a malformed JSON Pointer subject
+func applyOptionsStrictly(err *TrogonError, options []ErrorOption) []error {
+	var errs []error
+	var codeSet, visibilitySet bool
+	for _, option := range options {
+		before := *err
+		option(err)
+
+		if err.code != before.code {
+			if codeSet {
+				errs = append(errs, fmt.Errorf("%w: code set to both %s and %s", ErrConflictingOptions, before.code, err.code))
+			}
+			codeSet = true
+		}
+		if err.visibility != before.visibility {
+			if visibilitySet {
+				errs = append(errs, fmt.Errorf("%w: visibility set to both %s and %s", ErrConflictingOptions, before.visibility, err.visibility))
+			}
+			visibilitySet = true
+		}
+		if before.retryInfo != nil && err.retryInfo != before.retryInfo &&
+			(before.retryInfo.retryOffset != nil || before.retryInfo.retryTime != nil) &&
+			(before.retryInfo.retryOffset == nil) != (err.retryInfo.retryOffset == nil) {
+			errs = append(errs, fmt.Errorf("%w: retry offset and retry time are mutually exclusive", ErrConflictingOptions))
+		}
+	}
+	if subjectErr := ValidateSubject(err.subject); subjectErr != nil {
+		errs = append(errs, subjectErr)
+	}
+	return errs
+}
+
+// Validate checks the error, and recursively its causes, against the TrogonError specification:
+// required fields and their format, known code and visibility values, retry info with exactly one
+// of offset and time and a sane retry contract, consistent rate limit and deadline info, a well-formed
+// BCP 47 locale on the localized message, and a well-formed JSON Pointer subject when it starts with "/".
+// Every problem is reported together with errors.Join.
+// It is meant for tests and for serialization boundaries receiving errors from other services.
+//
+// Metadata may be more visible than its error, such as a public user ID on an internal error:
+// MaskForPublic keeps it when masking the error. Use ValidateStrict to reject it.
+func (e *TrogonError) Validate() error {
+	return e.validate(false)
+}
+
+// ValidateStrict checks the error like Validate, and also reports metadata more visible than
+// its error with ErrInvalidVisibility, for services that keep visibility consistent throughout
+func (e *TrogonError) ValidateStrict() error {
+	return e.validate(true)
+}
+
+func (e *TrogonError) validate(strict bool) error {
+	var errs []error
+
+	if e.specVersion < 1 {
+		errs = append(errs, fmt.Errorf("%w: %d", ErrInvalidSpecVersion, e.specVersion))
+	}
+	if err := ValidateDomain(e.domain); err != nil {
+		errs = append(errs, err)
+	}
+	if err := ValidateReason(e.reason); err != nil {
+		errs = append(errs, err)
+	}
+	if e.code < CodeCancelled || e.code > CodeUnauthenticated {
+		errs = append(errs, fmt.Errorf("%w: %d", ErrInvalidCode, e.code))
+	}
+	if e.visibility < VisibilityInternal || e.visibility > VisibilityPublic {
+		errs = append(errs, fmt.Errorf("%w: %d", ErrInvalidVisibility, e.visibility))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(e.metadata)) {
+		if visibility := e.metadata[key].visibility; strict && visibility > e.visibility {
+			errs = append(errs, fmt.Errorf("%w: metadata %q is %s on a %s error", ErrInvalidVisibility, key, visibility, e.visibility))
+		}
+	}
+
+	if e.retryInfo != nil {
+		if (e.retryInfo.retryOffset == nil) == (e.retryInfo.retryTime == nil) {
+			errs = append(errs, fmt.Errorf("%w: exactly one of retry offset and retry time must be set", ErrInvalidRetryInfo))
+		}
+		if e.retryInfo.maxAttempts < 0 || e.retryInfo.maxDelay < 0 {
+			errs = append(errs, fmt.Errorf("%w: max attempts and max delay must not be negative", ErrInvalidRetryInfo))
+		}
+		if e.retryInfo.backoffMultiplier != 0 && e.retryInfo.backoffMultiplier < 1 {
+			errs = append(errs, fmt.Errorf("%w: backoff multiplier %v is less than 1", ErrInvalidRetryInfo, e.retryInfo.backoffMultiplier))
+		}
+	}
+
+	if info := e.rateLimitInfo; info != nil {
+		if info.limit < 0 || info.remaining < 0 || info.window < 0 {
+			errs = append(errs, fmt.Errorf("%w: limit, remaining and window must not be negative", ErrInvalidRateLimitInfo))
+		} else if info.remaining > info.limit {
+			errs = append(errs, fmt.Errorf("%w: %d remaining exceeds the limit of %d", ErrInvalidRateLimitInfo, info.remaining, info.limit))
+		}
+	}
+
+	if info := e.deadlineInfo; info != nil {
+		if info.elapsed < 0 {
+			errs = append(errs, fmt.Errorf("%w: negative elapsed time %s", ErrInvalidDeadlineInfo, info.elapsed))
+		}
+		for i, stage := range info.stages {
+			if stage.name == "" || stage.duration < 0 {
+				errs = append(errs, fmt.Errorf("%w: stage %d must be named and not negative", ErrInvalidDeadlineInfo, i))
+			}
+		}
+	}
+
+	if e.localizedMessage != nil && !localePattern.MatchString(e.localizedMessage.locale) {
+		errs = append(errs, fmt.Errorf("%w %q", ErrInvalidLocale, e.localizedMessage.locale))
+	}
+	for _, translation := range e.translations {
+		if !localePattern.MatchString(translation.locale) {
+			errs = append(errs, fmt.Errorf("%w %q", ErrInvalidLocale, translation.locale))
+		}
+	}
+
+	if err := ValidateSubject(e.subject); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, cause := range e.causes {
+		if cause == nil {
+			continue
+		}
+		if err := cause.validate(strict); err != nil {
+			errs = append(errs, fmt.Errorf("cause %d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package trogonerror_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TrogonStack/trogonerror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorE(t *testing.T) {
+	t.Run("Valid input", func(t *testing.T) {
+		err, validationErr := trogonerror.NewErrorE("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithRetryInfoDuration(time.Second))
+
+		assert.NoError(t, validationErr)
+		assert.Equal(t, trogonerror.NewError("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithRetryInfoDuration(time.Second)), err)
+	})
+
+	tests := []struct {
+		name    string
+		domain  string
+		reason  string
+		options []trogonerror.ErrorOption
+		want    error
+	}{
+		{name: "Uppercase domain", domain: "Shopify.Users", reason: "NOT_FOUND", want: trogonerror.ErrInvalidDomain},
+		{name: "Empty domain segment", domain: "shopify..users", reason: "NOT_FOUND", want: trogonerror.ErrInvalidDomain},
+		{name: "Domain with spaces", domain: "shopify users", reason: "NOT_FOUND", want: trogonerror.ErrInvalidDomain},
+		{name: "Lowercase reason", domain: "shopify.users", reason: "not_found", want: trogonerror.ErrInvalidReason},
+		{name: "Trailing underscore", domain: "shopify.users", reason: "NOT_FOUND_", want: trogonerror.ErrInvalidReason},
+		{name: "Empty reason", domain: "shopify.users", reason: "", want: trogonerror.ErrInvalidReason},
+		{
+			name: "Conflicting codes", domain: "shopify.users", reason: "NOT_FOUND",
+			options: []trogonerror.ErrorOption{
+				trogonerror.WithCode(trogonerror.CodeNotFound),
+				trogonerror.WithCode(trogonerror.CodeInternal),
+			},
+			want: trogonerror.ErrConflictingOptions,
+		},
+		{
+			name: "Conflicting visibilities", domain: "shopify.users", reason: "NOT_FOUND",
+			options: []trogonerror.ErrorOption{
+				trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+				trogonerror.WithVisibility(trogonerror.VisibilityPrivate),
+			},
+			want: trogonerror.ErrConflictingOptions,
+		},
+		{
+			name: "Retry offset and retry time", domain: "shopify.users", reason: "NOT_FOUND",
+			options: []trogonerror.ErrorOption{
+				trogonerror.WithRetryInfoDuration(time.Second),
+				trogonerror.WithRetryTime(time.Date(2024, 1, 15, 14, 35, 45, 0, time.UTC)),
+			},
+			want: trogonerror.ErrConflictingOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err, validationErr := trogonerror.NewErrorE(tt.domain, tt.reason, tt.options...)
+
+			assert.Nil(t, err)
+			assert.ErrorIs(t, validationErr, tt.want)
+		})
+	}
+
+	t.Run("Reports every problem", func(t *testing.T) {
+		_, validationErr := trogonerror.NewErrorE("Shopify", "not_found",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithCode(trogonerror.CodeInternal))
+
+		assert.ErrorIs(t, validationErr, trogonerror.ErrInvalidDomain)
+		assert.ErrorIs(t, validationErr, trogonerror.ErrInvalidReason)
+		assert.ErrorIs(t, validationErr, trogonerror.ErrConflictingOptions)
+		assert.Len(t, validationErr.(interface{ Unwrap() []error }).Unwrap(), 3)
+	})
+
+	t.Run("Repeating the same value is not a conflict", func(t *testing.T) {
+		_, validationErr := trogonerror.NewErrorE("shopify.users", "NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithCode(trogonerror.CodeNotFound))
+
+		assert.NoError(t, validationErr)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Valid error", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCode(trogonerror.CodeNotFound),
+			trogonerror.WithVisibility(trogonerror.VisibilityPublic),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityInternal, "shard", "7"),
+			trogonerror.WithRetryInfoDuration(time.Second),
+			trogonerror.WithLocalizedMessage("zh-Hant-TW", "找不到訂單"),
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "ROW_MISSING")))
+
+		assert.NoError(t, err.Validate())
+	})
+
+	tests := []struct {
+		name string
+		err  *trogonerror.TrogonError
+		want error
+	}{
+		{
+			name: "Missing domain",
+			err:  trogonerror.NewError("", "ORDER_NOT_FOUND"),
+			want: trogonerror.ErrInvalidDomain,
+		},
+		{
+			name: "Lowercase reason",
+			err:  trogonerror.NewError("shopify.orders", "order_not_found"),
+			want: trogonerror.ErrInvalidReason,
+		},
+		{
+			name: "Unknown code",
+			err:  trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithCode(trogonerror.Code(42))),
+			want: trogonerror.ErrInvalidCode,
+		},
+		{
+			name: "Malformed locale",
+			err: trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+				trogonerror.WithLocalizedMessage("english_US", "Order not found")),
+			want: trogonerror.ErrInvalidLocale,
+		},
+		{
+			name: "Invalid cause",
+			err: trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+				trogonerror.WithCause(trogonerror.NewError("shopify.database", "rowMissing"))),
+			want: trogonerror.ErrInvalidReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.err.Validate(), tt.want)
+		})
+	}
+
+	t.Run("Well-formed locales", func(t *testing.T) {
+		for _, locale := range []string{"en", "es-ES", "es-419", "sr-Latn-RS", "de-CH-1996", "en-US-x-twain", "x-private"} {
+			err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithLocalizedMessage(locale, "message"))
+			assert.NoError(t, err.Validate(), locale)
+		}
+	})
+
+	t.Run("The documented examples are valid", func(t *testing.T) {
+		errUserNotFound := trogonerror.NewErrorTemplate("shopify.users", "NOT_FOUND",
+			trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+		errValidationFailed := trogonerror.NewErrorTemplate("shopify", "VALIDATION_FAILED",
+			trogonerror.TemplateWithCode(trogonerror.CodeInvalidArgument))
+
+		for _, err := range []*trogonerror.TrogonError{
+			errUserNotFound.NewError(
+				trogonerror.WithMetadataValuef(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/%s", "1234567890")),
+			errValidationFailed.NewError(
+				trogonerror.WithSubject("/email"),
+				trogonerror.WithMessage("email is required"),
+				trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "validationType", "REQUIRED")),
+			trogonerror.NewError("shopify.users", "NOT_FOUND",
+				trogonerror.WithCode(trogonerror.CodeNotFound),
+				trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "userId", "gid://shopify/Customer/1234567890")),
+		} {
+			assert.NoError(t, err.Validate())
+		}
+	})
+
+	t.Run("ValidateStrict rejects metadata more visible than its error", func(t *testing.T) {
+		err := trogonerror.NewError("shopify.orders", "ORDER_NOT_FOUND",
+			trogonerror.WithCause(trogonerror.NewError("shopify.database", "ROW_MISSING",
+				trogonerror.WithMetadataValue(trogonerror.VisibilityPrivate, "table", "orders"))),
+			trogonerror.WithMetadataValue(trogonerror.VisibilityPublic, "orderId", "1"))
+
+		assert.NoError(t, err.Validate())
+		assert.ErrorIs(t, err.ValidateStrict(), trogonerror.ErrInvalidVisibility)
+		assert.ErrorContains(t, err.ValidateStrict(), `metadata "orderId" is PUBLIC on a INTERNAL error`)
+		assert.ErrorContains(t, err.ValidateStrict(), `cause 0: trogonerror: invalid visibility: metadata "table" is PRIVATE on a INTERNAL error`)
+	})
+}
+
+func TestMustConstructors(t *testing.T) {
+	t.Run("MustNewError", func(t *testing.T) {
+		err := trogonerror.MustNewError("shopify.orders", "ORDER_NOT_FOUND", trogonerror.WithCode(trogonerror.CodeNotFound))
+		assert.Equal(t, trogonerror.CodeNotFound, err.Code())
+
+		assert.Panics(t, func() { trogonerror.MustNewError("Shopify Orders", "ORDER_NOT_FOUND") })
+	})
+
+	t.Run("MustNewErrorTemplate", func(t *testing.T) {
+		template := trogonerror.MustNewErrorTemplate("shopify.orders", "ORDER_NOT_FOUND")
+		assert.Equal(t, "ORDER_NOT_FOUND", template.NewError().Reason())
+
+		assert.Panics(t, func() { trogonerror.MustNewErrorTemplate("shopify.orders", "order not found") })
+	})
+
+	t.Run("Template MustNewError", func(t *testing.T) {
+		template := trogonerror.NewErrorTemplate("shopify.orders", "ORDER_NOT_FOUND", trogonerror.TemplateWithCode(trogonerror.CodeNotFound))
+
+		err := template.MustNewError(trogonerror.WithCode(trogonerror.CodeFailedPrecondition))
+		assert.Equal(t, trogonerror.CodeFailedPrecondition, err.Code())
+
+		_, validationErr := template.NewErrorE(trogonerror.WithVisibility(trogonerror.VisibilityPublic), trogonerror.WithVisibility(trogonerror.VisibilityPrivate))
+		assert.ErrorIs(t, validationErr, trogonerror.ErrConflictingOptions)
+		assert.Panics(t, func() {
+			template.MustNewError(trogonerror.WithCode(trogonerror.CodeInternal), trogonerror.WithCode(trogonerror.CodeUnavailable))
+		})
+	})
+}